@@ -0,0 +1,407 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// ListChange describes how a server list changed after a refresh
+type ListChange[T any] struct {
+	Added    []T
+	Removed  []T
+	Modified []T // New versions of entries whose definition changed
+}
+
+// Empty reports whether the change contains no entries
+func (c ListChange[T]) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Modified) == 0
+}
+
+// Catalog caches the tools, resources, resource templates and prompts offered
+// by the server. Each list is fetched on first use, following pagination, and
+// indexed by name or URI. When the server sends a *_list_changed notification,
+// lists that have been loaded are refreshed in the background and the
+// registered change callbacks receive the differences.
+//
+// Enable the catalog with WithCatalog and access it with Client.Catalog.
+type Catalog struct {
+	tools     *catalogList[types.Tool]
+	resources *catalogList[types.Resource]
+	templates *catalogList[types.ResourceTemplate]
+	prompts   *catalogList[types.Prompt]
+}
+
+// newCatalog creates a catalog that loads its lists through the client
+func newCatalog(c *Client) *Catalog {
+	return &Catalog{
+		tools: newCatalogList(func(t types.Tool) string { return t.Name },
+			func(ctx context.Context) ([]types.Tool, error) {
+				if !c.HasTools() {
+					return nil, nil
+				}
+				return listAll[types.Tool](ctx, c, "tools/list", "tools")
+			}),
+		resources: newCatalogList(func(r types.Resource) string { return r.URI },
+			func(ctx context.Context) ([]types.Resource, error) {
+				if !c.HasResources() {
+					return nil, nil
+				}
+				return listAll[types.Resource](ctx, c, "resources/list", "resources")
+			}),
+		templates: newCatalogList(func(t types.ResourceTemplate) string { return t.URITemplate },
+			func(ctx context.Context) ([]types.ResourceTemplate, error) {
+				if !c.HasResources() {
+					return nil, nil
+				}
+				return listAll[types.ResourceTemplate](ctx, c, "resources/templates/list", "resourceTemplates")
+			}),
+		prompts: newCatalogList(func(p types.Prompt) string { return p.Name },
+			func(ctx context.Context) ([]types.Prompt, error) {
+				if !c.HasPrompts() {
+					return nil, nil
+				}
+				return listAll[types.Prompt](ctx, c, "prompts/list", "prompts")
+			}),
+	}
+}
+
+// Tools returns all tools, loading them on first use
+func (cat *Catalog) Tools(ctx context.Context) ([]types.Tool, error) {
+	return cat.tools.all(ctx)
+}
+
+// Tool returns the tool with the given name, or nil if the server has no such tool
+func (cat *Catalog) Tool(ctx context.Context, name string) (*types.Tool, error) {
+	return cat.tools.get(ctx, name)
+}
+
+// Resources returns all resources, loading them on first use
+func (cat *Catalog) Resources(ctx context.Context) ([]types.Resource, error) {
+	return cat.resources.all(ctx)
+}
+
+// Resource returns the resource with the given URI, or nil if it is not listed
+func (cat *Catalog) Resource(ctx context.Context, uri string) (*types.Resource, error) {
+	return cat.resources.get(ctx, uri)
+}
+
+// ResourceTemplates returns all resource templates, loading them on first use
+func (cat *Catalog) ResourceTemplates(ctx context.Context) ([]types.ResourceTemplate, error) {
+	return cat.templates.all(ctx)
+}
+
+// ResourceTemplate returns the template with the given URI template, or nil if it is not listed
+func (cat *Catalog) ResourceTemplate(ctx context.Context, uriTemplate string) (*types.ResourceTemplate, error) {
+	return cat.templates.get(ctx, uriTemplate)
+}
+
+// Prompts returns all prompts, loading them on first use
+func (cat *Catalog) Prompts(ctx context.Context) ([]types.Prompt, error) {
+	return cat.prompts.all(ctx)
+}
+
+// Prompt returns the prompt with the given name, or nil if the server has no such prompt
+func (cat *Catalog) Prompt(ctx context.Context, name string) (*types.Prompt, error) {
+	return cat.prompts.get(ctx, name)
+}
+
+// OnToolsChanged registers a callback invoked when a refresh changes the tool list
+func (cat *Catalog) OnToolsChanged(fn func(ListChange[types.Tool])) {
+	cat.tools.subscribe(fn)
+}
+
+// OnResourcesChanged registers a callback invoked when a refresh changes the resource list
+func (cat *Catalog) OnResourcesChanged(fn func(ListChange[types.Resource])) {
+	cat.resources.subscribe(fn)
+}
+
+// OnResourceTemplatesChanged registers a callback invoked when a refresh changes the resource template list
+func (cat *Catalog) OnResourceTemplatesChanged(fn func(ListChange[types.ResourceTemplate])) {
+	cat.templates.subscribe(fn)
+}
+
+// OnPromptsChanged registers a callback invoked when a refresh changes the prompt list
+func (cat *Catalog) OnPromptsChanged(fn func(ListChange[types.Prompt])) {
+	cat.prompts.subscribe(fn)
+}
+
+// Refresh reloads every list that has already been loaded and fires change callbacks
+func (cat *Catalog) Refresh(ctx context.Context) error {
+	if err := cat.tools.refresh(ctx); err != nil {
+		return fmt.Errorf("failed to refresh tools: %w", err)
+	}
+	if err := cat.resources.refresh(ctx); err != nil {
+		return fmt.Errorf("failed to refresh resources: %w", err)
+	}
+	if err := cat.templates.refresh(ctx); err != nil {
+		return fmt.Errorf("failed to refresh resource templates: %w", err)
+	}
+	if err := cat.prompts.refresh(ctx); err != nil {
+		return fmt.Errorf("failed to refresh prompts: %w", err)
+	}
+	return nil
+}
+
+// Invalidate discards all cached lists so they are fetched again on next use
+func (cat *Catalog) Invalidate() {
+	cat.tools.invalidate()
+	cat.resources.invalidate()
+	cat.templates.invalidate()
+	cat.prompts.invalidate()
+}
+
+// catalogList is a lazily loaded list of entries indexed by key
+type catalogList[T any] struct {
+	key  func(T) string
+	load func(ctx context.Context) ([]T, error)
+
+	// refreshMu serializes loads so change sets are computed against a consistent snapshot
+	refreshMu sync.Mutex
+
+	mu        sync.RWMutex
+	loaded    bool
+	items     []T
+	index     map[string]int
+	listeners []func(ListChange[T])
+}
+
+func newCatalogList[T any](key func(T) string, load func(ctx context.Context) ([]T, error)) *catalogList[T] {
+	return &catalogList[T]{key: key, load: load}
+}
+
+// all returns a copy of the list, loading it if necessary
+func (l *catalogList[T]) all(ctx context.Context) ([]T, error) {
+	if err := l.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return append([]T(nil), l.items...), nil
+}
+
+// get returns the entry with the given key, loading the list if necessary
+func (l *catalogList[T]) get(ctx context.Context, key string) (*T, error) {
+	if err := l.ensureLoaded(ctx); err != nil {
+		return nil, err
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	i, ok := l.index[key]
+	if !ok {
+		return nil, nil
+	}
+	item := l.items[i]
+	return &item, nil
+}
+
+// peek returns the entry with the given key if the list is loaded, without fetching it
+func (l *catalogList[T]) peek(key string) (T, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	i, ok := l.index[key]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return l.items[i], true
+}
+
+// ensureLoaded performs the initial load without firing change callbacks
+func (l *catalogList[T]) ensureLoaded(ctx context.Context) error {
+	l.mu.RLock()
+	loaded := l.loaded
+	l.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	l.refreshMu.Lock()
+	defer l.refreshMu.Unlock()
+
+	l.mu.RLock()
+	loaded = l.loaded
+	l.mu.RUnlock()
+	if loaded {
+		return nil
+	}
+
+	items, err := l.load(ctx)
+	if err != nil {
+		return err
+	}
+	l.replace(items)
+	return nil
+}
+
+// refresh reloads a previously loaded list and notifies listeners of any differences.
+// Lists that have never been loaded are left to load lazily.
+func (l *catalogList[T]) refresh(ctx context.Context) error {
+	l.refreshMu.Lock()
+	defer l.refreshMu.Unlock()
+
+	l.mu.RLock()
+	loaded := l.loaded
+	old := l.items
+	l.mu.RUnlock()
+	if !loaded {
+		return nil
+	}
+
+	items, err := l.load(ctx)
+	if err != nil {
+		// Drop the stale list so the next access retries the load
+		l.invalidate()
+		return err
+	}
+
+	change := diffLists(old, items, l.key)
+	listeners := l.replace(items)
+
+	if !change.Empty() {
+		for _, fn := range listeners {
+			fn(change)
+		}
+	}
+	return nil
+}
+
+// replace stores a freshly loaded list and returns the current listeners
+func (l *catalogList[T]) replace(items []T) []func(ListChange[T]) {
+	index := make(map[string]int, len(items))
+	for i, item := range items {
+		index[l.key(item)] = i
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.items = items
+	l.index = index
+	l.loaded = true
+	return append([]func(ListChange[T]){}, l.listeners...)
+}
+
+func (l *catalogList[T]) invalidate() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.loaded = false
+	l.items = nil
+	l.index = nil
+}
+
+func (l *catalogList[T]) subscribe(fn func(ListChange[T])) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.listeners = append(l.listeners, fn)
+}
+
+// diffLists compares two versions of a list by key.
+// Added and Modified follow the order of the new list, Removed the order of the old one.
+func diffLists[T any](old, updated []T, key func(T) string) ListChange[T] {
+	previous := make(map[string]T, len(old))
+	for _, item := range old {
+		previous[key(item)] = item
+	}
+
+	var change ListChange[T]
+	current := make(map[string]bool, len(updated))
+	for _, item := range updated {
+		k := key(item)
+		current[k] = true
+
+		before, existed := previous[k]
+		switch {
+		case !existed:
+			change.Added = append(change.Added, item)
+		case !reflect.DeepEqual(before, item):
+			change.Modified = append(change.Modified, item)
+		}
+	}
+
+	for _, item := range old {
+		if !current[key(item)] {
+			change.Removed = append(change.Removed, item)
+		}
+	}
+
+	return change
+}
+
+// listAll fetches every page of a paginated list method and returns the entries stored under field
+func listAll[T any](ctx context.Context, c *Client, method, field string) ([]T, error) {
+	var items []T
+	var cursor *types.Cursor
+	for {
+		params := struct {
+			Cursor *types.Cursor `json:"cursor,omitempty"`
+		}{
+			Cursor: cursor,
+		}
+
+		var result map[string]json.RawMessage
+		if err := c.call(ctx, &result, method, params); err != nil {
+			return nil, err
+		}
+
+		var page []T
+		if raw, ok := result[field]; ok {
+			if err := json.Unmarshal(raw, &page); err != nil {
+				return nil, fmt.Errorf("failed to decode %s result: %w", method, err)
+			}
+		}
+		items = append(items, page...)
+
+		var next *types.Cursor
+		if raw, ok := result["nextCursor"]; ok {
+			if err := json.Unmarshal(raw, &next); err != nil {
+				return nil, fmt.Errorf("failed to decode %s cursor: %w", method, err)
+			}
+		}
+		if next == nil || *next == "" {
+			return items, nil
+		}
+		cursor = next
+	}
+}
+
+// handleListChanged refreshes cached lists after a *_list_changed notification.
+// Refreshes run in the background because notifications are delivered from the
+// transport's read loop, which must stay free to receive the list responses.
+func (c *Client) handleListChanged(method string) {
+	if method == "notifications/tools/list_changed" {
+		c.toolsMu.Lock()
+		c.tools = nil
+		c.toolsMu.Unlock()
+	}
+
+	if c.catalog == nil {
+		return
+	}
+
+	var refresh func(ctx context.Context) error
+	switch method {
+	case "notifications/tools/list_changed":
+		refresh = c.catalog.tools.refresh
+	case "notifications/resources/list_changed":
+		refresh = func(ctx context.Context) error {
+			if err := c.catalog.resources.refresh(ctx); err != nil {
+				return err
+			}
+			return c.catalog.templates.refresh(ctx)
+		}
+	case "notifications/prompts/list_changed":
+		refresh = c.catalog.prompts.refresh
+	default:
+		return
+	}
+
+	go func() {
+		_ = refresh(c.ctx)
+	}()
+}
@@ -0,0 +1,280 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// catalogServer serves mutable tool, resource and prompt lists to a NotifyingTransport
+type catalogServer struct {
+	mu    sync.Mutex
+	lists map[string]string // Method to JSON result
+	calls map[string]int
+}
+
+func newCatalogTransport(server *catalogServer) *NotifyingTransport {
+	return &NotifyingTransport{MockTransport: MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			server.calls[method]++
+			return json.Unmarshal([]byte(server.lists[method]), result)
+		},
+	}}
+}
+
+func (s *catalogServer) set(method, result string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists[method] = result
+}
+
+func (s *catalogServer) count(method string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[method]
+}
+
+func newCatalogClient(t *testing.T) (*Client, *NotifyingTransport, *catalogServer) {
+	server := &catalogServer{
+		lists: map[string]string{
+			"tools/list":               `{"tools": [{"name": "echo", "inputSchema": {"type": "object"}}, {"name": "add", "inputSchema": {"type": "object"}}]}`,
+			"resources/list":           `{"resources": [{"name": "readme", "uri": "file:///README.md"}]}`,
+			"resources/templates/list": `{"resourceTemplates": [{"name": "file", "uriTemplate": "file:///{+path}"}]}`,
+			"prompts/list":             `{"prompts": [{"name": "greet"}]}`,
+		},
+		calls: make(map[string]int),
+	}
+	transport := newCatalogTransport(server)
+
+	client := NewClient(WithTransport(transport), WithCatalog())
+	client.capabilities = &types.ServerCapabilities{
+		Tools:     &types.ToolsCapability{},
+		Resources: &types.ResourcesCapability{},
+		Prompts:   &types.PromptsCapability{},
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return client, transport, server
+}
+
+func TestCatalogDisabledByDefault(t *testing.T) {
+	client := NewClient(WithTransport(&MockTransport{}))
+	defer client.Close()
+
+	if client.Catalog() != nil {
+		t.Error("Catalog should be nil unless enabled with WithCatalog")
+	}
+}
+
+func TestCatalogLazyLoadAndIndex(t *testing.T) {
+	client, _, server := newCatalogClient(t)
+	catalog := client.Catalog()
+	ctx := context.Background()
+
+	if server.count("tools/list") != 0 {
+		t.Fatal("Catalog should not load before first use")
+	}
+
+	tool, err := catalog.Tool(ctx, "add")
+	if err != nil || tool == nil || tool.Name != "add" {
+		t.Fatalf("Expected tool 'add', got %v, %v", tool, err)
+	}
+	if missing, err := catalog.Tool(ctx, "missing"); err != nil || missing != nil {
+		t.Errorf("Expected nil for unknown tool, got %v, %v", missing, err)
+	}
+	if tools, _ := catalog.Tools(ctx); len(tools) != 2 {
+		t.Errorf("Expected 2 tools, got %d", len(tools))
+	}
+	if server.count("tools/list") != 1 {
+		t.Errorf("Expected a single tools/list call, got %d", server.count("tools/list"))
+	}
+
+	resource, _ := catalog.Resource(ctx, "file:///README.md")
+	if resource == nil || resource.Name != "readme" {
+		t.Errorf("Expected resource indexed by URI, got %v", resource)
+	}
+	template, _ := catalog.ResourceTemplate(ctx, "file:///{+path}")
+	if template == nil || template.Name != "file" {
+		t.Errorf("Expected template indexed by URI template, got %v", template)
+	}
+	prompt, _ := catalog.Prompt(ctx, "greet")
+	if prompt == nil {
+		t.Error("Expected prompt 'greet'")
+	}
+}
+
+func TestCatalogPagination(t *testing.T) {
+	pages := map[string]string{
+		"":  `{"prompts": [{"name": "a"}], "nextCursor": "2"}`,
+		"2": `{"prompts": [{"name": "b"}]}`,
+	}
+	client := NewClient(WithTransport(&MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			data, _ := json.Marshal(params[0])
+			var decoded struct {
+				Cursor string `json:"cursor"`
+			}
+			_ = json.Unmarshal(data, &decoded)
+			return json.Unmarshal([]byte(pages[decoded.Cursor]), result)
+		},
+	}), WithCatalog())
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Prompts: &types.PromptsCapability{}}
+
+	prompts, err := client.Catalog().Prompts(context.Background())
+	if err != nil {
+		t.Fatalf("Prompts failed: %v", err)
+	}
+	if len(prompts) != 2 || prompts[1].Name != "b" {
+		t.Errorf("Expected prompts from both pages, got %+v", prompts)
+	}
+}
+
+func TestCatalogListChanged(t *testing.T) {
+	client, transport, server := newCatalogClient(t)
+	catalog := client.Catalog()
+
+	changes := make(chan ListChange[types.Tool], 1)
+	catalog.OnToolsChanged(func(change ListChange[types.Tool]) { changes <- change })
+
+	if _, err := catalog.Tools(context.Background()); err != nil {
+		t.Fatalf("Tools failed: %v", err)
+	}
+
+	server.set("tools/list", `{"tools": [
+		{"name": "echo", "description": "now documented", "inputSchema": {"type": "object"}},
+		{"name": "multiply", "inputSchema": {"type": "object"}}
+	]}`)
+	transport.notify("notifications/tools/list_changed", `{}`)
+
+	var change ListChange[types.Tool]
+	select {
+	case change = <-changes:
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for tools change")
+	}
+
+	if len(change.Added) != 1 || change.Added[0].Name != "multiply" {
+		t.Errorf("Unexpected added tools: %+v", change.Added)
+	}
+	if len(change.Removed) != 1 || change.Removed[0].Name != "add" {
+		t.Errorf("Unexpected removed tools: %+v", change.Removed)
+	}
+	if len(change.Modified) != 1 || change.Modified[0].Description != "now documented" {
+		t.Errorf("Unexpected modified tools: %+v", change.Modified)
+	}
+
+	if tool, _ := catalog.Tool(context.Background(), "add"); tool != nil {
+		t.Error("Removed tool should no longer be in the catalog")
+	}
+}
+
+func TestCatalogResourcesListChangedRefreshesTemplates(t *testing.T) {
+	client, transport, server := newCatalogClient(t)
+	catalog := client.Catalog()
+	ctx := context.Background()
+
+	changes := make(chan ListChange[types.ResourceTemplate], 1)
+	catalog.OnResourceTemplatesChanged(func(change ListChange[types.ResourceTemplate]) { changes <- change })
+
+	if _, err := catalog.ResourceTemplates(ctx); err != nil {
+		t.Fatalf("ResourceTemplates failed: %v", err)
+	}
+
+	server.set("resources/templates/list", `{"resourceTemplates": []}`)
+	transport.notify("notifications/resources/list_changed", `{}`)
+
+	select {
+	case change := <-changes:
+		if len(change.Removed) != 1 || change.Removed[0].URITemplate != "file:///{+path}" {
+			t.Errorf("Unexpected template change: %+v", change)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for template change")
+	}
+
+	// Resources were never loaded, so the notification must not fetch them
+	if server.count("resources/list") != 0 {
+		t.Error("Unloaded lists should not be refreshed")
+	}
+}
+
+func TestCatalogRefreshWithoutChanges(t *testing.T) {
+	client, _, _ := newCatalogClient(t)
+	catalog := client.Catalog()
+	ctx := context.Background()
+
+	called := false
+	catalog.OnPromptsChanged(func(ListChange[types.Prompt]) { called = true })
+
+	if _, err := catalog.Prompts(ctx); err != nil {
+		t.Fatalf("Prompts failed: %v", err)
+	}
+	if err := catalog.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if called {
+		t.Error("Callbacks should not fire when nothing changed")
+	}
+}
+
+func TestCatalogInvalidate(t *testing.T) {
+	client, _, server := newCatalogClient(t)
+	catalog := client.Catalog()
+	ctx := context.Background()
+
+	_, _ = catalog.Tools(ctx)
+	catalog.Invalidate()
+	_, _ = catalog.Tools(ctx)
+
+	if server.count("tools/list") != 2 {
+		t.Errorf("Expected tools to be reloaded after Invalidate, got %d calls", server.count("tools/list"))
+	}
+}
+
+func TestCatalogBacksArgumentValidation(t *testing.T) {
+	server := &catalogServer{
+		lists: map[string]string{
+			"tools/list": `{"tools": [{"name": "greet", "inputSchema": {"type": "object", "required": ["name"]}}]}`,
+		},
+		calls: make(map[string]int),
+	}
+	client := NewClient(WithTransport(newCatalogTransport(server)), WithCatalog())
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	if err := client.ValidateToolArguments("greet", nil); err == nil {
+		t.Error("Expected validation error for missing required argument")
+	}
+	if err := client.ValidateToolArguments("greet", map[string]interface{}{"name": "Ada"}); err != nil {
+		t.Errorf("Unexpected validation error: %v", err)
+	}
+	if server.count("tools/list") != 1 {
+		t.Errorf("Expected validation to use the catalog's cached tools, got %d calls", server.count("tools/list"))
+	}
+}
+
+func TestToolsListChangedInvalidatesToolCache(t *testing.T) {
+	server := &catalogServer{
+		lists: map[string]string{"tools/list": `{"tools": [{"name": "greet", "inputSchema": {"type": "object"}}]}`},
+		calls: make(map[string]int),
+	}
+	transport := newCatalogTransport(server)
+	client := NewClient(WithTransport(transport))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	_ = client.ValidateToolArguments("greet", nil)
+	transport.notify("notifications/tools/list_changed", `{}`)
+	_ = client.ValidateToolArguments("greet", nil)
+
+	if server.count("tools/list") != 2 {
+		t.Errorf("Expected tools to be listed again after list_changed, got %d calls", server.count("tools/list"))
+	}
+}
@@ -3,6 +3,8 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/Convict3d/mcp-go/transport"
@@ -16,6 +18,12 @@ type Client struct {
 	config       *Config
 	serverInfo   *types.Implementation
 	capabilities *types.ServerCapabilities
+
+	// Tool definitions from the most recent tools/list, keyed by name
+	toolsMu sync.RWMutex
+	tools   map[string]types.Tool
+
+	catalog *Catalog // nil unless enabled with WithCatalog
 }
 
 // Config holds configuration for the MCP client
@@ -26,6 +34,22 @@ type Config struct {
 	Timeout       time.Duration
 	CustomHeaders map[string]string
 	Transport     transport.Transport // Custom transport
+
+	ValidateArguments bool // Validate tool arguments against the tool's inputSchema before calling
+	ValidateOutput    bool // Validate structured tool output against the tool's outputSchema
+
+	LogSink LogSink // Receives notifications/message log entries from the server
+
+	Catalog bool // Cache server lists and refresh them on list_changed notifications
+
+	Interceptors    []Interceptor             // Wrap outgoing requests and incoming requests and notifications
+	RequestHandlers map[string]RequestHandler // Handle requests sent by the server, keyed by method
+
+	RetryPolicy *RetryPolicy // Retry failed idempotent requests, nil disables retries
+
+	ToolPolicies []ToolPolicy // Approve, deny or change tool calls before they are sent
+
+	Capabilities types.ClientCapabilities // Capabilities declared to the server in initialize
 }
 
 // Option defines a function that configures the client
@@ -53,6 +77,47 @@ func WithTransport(t transport.Transport) Option {
 	}
 }
 
+// WithClientCapabilities sets the capabilities declared to the server during
+// initialization. Register request handlers with WithRequestHandler for the
+// server requests the capabilities imply, such as sampling/createMessage.
+func WithClientCapabilities(capabilities types.ClientCapabilities) Option {
+	return func(c *Config) {
+		c.Capabilities = capabilities
+	}
+}
+
+// WithArgumentValidation enables validation of tool arguments against the
+// inputSchema advertised by the tool in tools/list before every CallTool
+func WithArgumentValidation() Option {
+	return func(c *Config) {
+		c.ValidateArguments = true
+	}
+}
+
+// WithOutputValidation enables validation of structured tool output against
+// the outputSchema advertised by the tool in tools/list
+func WithOutputValidation() Option {
+	return func(c *Config) {
+		c.ValidateOutput = true
+	}
+}
+
+// WithLogSink sets the function that receives log messages sent by the server.
+// Use SetLogLevel to choose which messages the server sends.
+func WithLogSink(sink LogSink) Option {
+	return func(c *Config) {
+		c.LogSink = sink
+	}
+}
+
+// WithCatalog enables the cached catalog of tools, resources, resource templates
+// and prompts. See Catalog for details.
+func WithCatalog() Option {
+	return func(c *Config) {
+		c.Catalog = true
+	}
+}
+
 // WithContext sets a custom context (advanced usage)
 func WithContext(ctx context.Context) Option {
 	return func(c *Config) {
@@ -82,11 +147,25 @@ func NewClient(opts ...Option) *Client {
 		opt(config)
 	}
 
-	return &Client{
+	client := &Client{
 		transport: config.Transport,
 		ctx:       context.Background(),
 		config:    config,
 	}
+
+	if config.Catalog {
+		client.catalog = newCatalog(client)
+	}
+
+	// Receive server notifications and requests when the transport can deliver them
+	if receiver, ok := config.Transport.(transport.NotificationReceiver); ok {
+		receiver.SetNotificationHandler(client.handleNotification)
+	}
+	if receiver, ok := config.Transport.(transport.RequestReceiver); ok {
+		receiver.SetRequestHandler(client.handleRequest)
+	}
+
+	return client
 }
 
 // NewSimpleClient creates a client with minimal configuration for quick setup
@@ -102,7 +181,7 @@ func (c *Client) Initialize(protocolVersion string) error {
 		ClientInfo      types.Implementation     `json:"clientInfo"`
 	}{
 		ProtocolVersion: protocolVersion,
-		Capabilities:    types.ClientCapabilities{},
+		Capabilities:    c.config.Capabilities,
 		ClientInfo: types.Implementation{
 			Name:    c.config.ClientName,
 			Version: c.config.ClientVersion,
@@ -110,7 +189,7 @@ func (c *Client) Initialize(protocolVersion string) error {
 	}
 
 	var result types.InitializeResult
-	err := c.transport.Call(c.ctx, &result, "initialize", params)
+	err := c.call(c.ctx, &result, "initialize", params)
 	if err != nil {
 		return err
 	}
@@ -118,14 +197,44 @@ func (c *Client) Initialize(protocolVersion string) error {
 	c.serverInfo = &result.ServerInfo
 	c.capabilities = &result.Capabilities
 
-	// Note: Some MCP servers don't require the initialized notification
-	// If needed, uncomment the following lines:
-	// var empty interface{}
-	// return c.transport.Call(c.ctx, &empty, "initialized", struct{}{})
+	// Servers only send notifications such as list_changed once the
+	// handshake is complete
+	if err := c.notify(c.ctx, "notifications/initialized", nil); err != nil {
+		return fmt.Errorf("failed to send initialized notification: %w", err)
+	}
+	return nil
+}
+
+// handleNotification passes a notification received from the server through
+// the interceptor chain to dispatchNotification
+func (c *Client) handleNotification(method string, params interface{}) {
+	call := &Call{
+		Direction:    Incoming,
+		Method:       method,
+		Params:       params,
+		Notification: true,
+	}
+	_ = c.chain(c.ctx, call, c.dispatchNotification)
+}
 
+// dispatchNotification handles a notification received from the server
+func (c *Client) dispatchNotification(ctx context.Context, call *Call) error {
+	switch call.Method {
+	case "notifications/message":
+		c.handleLogMessage(call.Params)
+	case "notifications/tools/list_changed",
+		"notifications/resources/list_changed",
+		"notifications/prompts/list_changed":
+		c.handleListChanged(call.Method)
+	}
 	return nil
 }
 
+// Catalog returns the client's catalog, or nil if it was not enabled with WithCatalog
+func (c *Client) Catalog() *Catalog {
+	return c.catalog
+}
+
 // GetServerInfo returns information about the connected server
 func (c *Client) GetServerInfo() *types.Implementation {
 	return c.serverInfo
@@ -156,6 +265,16 @@ func (c *Client) HasPrompts() bool {
 	return c.capabilities != nil && c.capabilities.Prompts != nil
 }
 
+// HasLogging returns true if the server supports log level control and log messages
+func (c *Client) HasLogging() bool {
+	return c.capabilities != nil && c.capabilities.Logging != nil
+}
+
+// HasCompletions returns true if the server supports argument completion
+func (c *Client) HasCompletions() bool {
+	return c.capabilities != nil && c.capabilities.Completions != nil
+}
+
 // ListTools retrieves the list of available tools from the server
 func (c *Client) ListTools() ([]types.Tool, error) {
 	if !c.HasTools() {
@@ -163,35 +282,57 @@ func (c *Client) ListTools() ([]types.Tool, error) {
 	}
 
 	var result types.ListToolsResult
-	err := c.transport.Call(c.ctx, &result, "tools/list")
+	err := c.call(c.ctx, &result, "tools/list", nil)
 	if err != nil {
 		return nil, err
 	}
 
+	// A single page is not the complete list the cache must hold
+	if result.NextCursor == nil || *result.NextCursor == "" {
+		c.cacheTools(result.Tools)
+	}
+
 	return result.Tools, nil
 }
 
 // CallTool executes a tool with the given arguments
 func (c *Client) CallTool(name string, arguments map[string]interface{}) (*types.CallToolResult, error) {
+	return c.CallToolContext(c.ctx, name, arguments)
+}
+
+// CallToolContext executes a tool with the given arguments using the provided context
+func (c *Client) CallToolContext(ctx context.Context, name string, arguments map[string]interface{}) (*types.CallToolResult, error) {
 	if !c.HasTools() {
 		return nil, nil
 	}
 
-	params := struct {
-		Name      string                 `json:"name"`
-		Arguments map[string]interface{} `json:"arguments,omitempty"`
-		Meta      types.Meta             `json:"_meta,omitempty"`
-	}{
+	arguments, err := c.applyToolPolicies(ctx, name, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.config.ValidateArguments {
+		if err := c.validateToolArguments(ctx, name, arguments); err != nil {
+			return nil, err
+		}
+	}
+
+	params := &types.CallToolParams{
 		Name:      name,
 		Arguments: arguments,
 	}
 
 	var result types.CallToolResult
-	err := c.transport.Call(c.ctx, &result, "tools/call", params)
-	if err != nil {
+	if err := c.call(ctx, &result, "tools/call", params); err != nil {
 		return nil, err
 	}
 
+	if c.config.ValidateOutput {
+		if err := c.validateStructuredContent(ctx, name, &result); err != nil {
+			return nil, err
+		}
+	}
+
 	return &result, nil
 }
 
@@ -202,7 +343,7 @@ func (c *Client) ListResources() ([]types.Resource, error) {
 	}
 
 	var result types.ListResourcesResult
-	err := c.transport.Call(c.ctx, &result, "resources/list")
+	err := c.call(c.ctx, &result, "resources/list", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -212,19 +353,21 @@ func (c *Client) ListResources() ([]types.Resource, error) {
 
 // ReadResource reads the content of a specific resource
 func (c *Client) ReadResource(uri string) (*types.ReadResourceResult, error) {
+	return c.ReadResourceContext(c.ctx, uri)
+}
+
+// ReadResourceContext reads the content of a specific resource using the provided context
+func (c *Client) ReadResourceContext(ctx context.Context, uri string) (*types.ReadResourceResult, error) {
 	if !c.HasResources() {
 		return nil, nil
 	}
 
-	params := struct {
-		URI  string     `json:"uri"`
-		Meta types.Meta `json:"_meta,omitempty"`
-	}{
+	params := &types.ReadResourceParams{
 		URI: uri,
 	}
 
 	var result types.ReadResourceResult
-	err := c.transport.Call(c.ctx, &result, "resources/read", params)
+	err := c.call(ctx, &result, "resources/read", params)
 	if err != nil {
 		return nil, err
 	}
@@ -239,7 +382,7 @@ func (c *Client) ListPrompts() ([]types.Prompt, error) {
 	}
 
 	var result types.ListPromptsResult
-	err := c.transport.Call(c.ctx, &result, "prompts/list")
+	err := c.call(c.ctx, &result, "prompts/list", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -249,21 +392,22 @@ func (c *Client) ListPrompts() ([]types.Prompt, error) {
 
 // GetPrompt retrieves a specific prompt with arguments
 func (c *Client) GetPrompt(name string, arguments map[string]string) (*types.GetPromptResult, error) {
+	return c.GetPromptContext(c.ctx, name, arguments)
+}
+
+// GetPromptContext retrieves a specific prompt with arguments using the provided context
+func (c *Client) GetPromptContext(ctx context.Context, name string, arguments map[string]string) (*types.GetPromptResult, error) {
 	if !c.HasPrompts() {
 		return nil, nil
 	}
 
-	params := struct {
-		Name      string            `json:"name"`
-		Arguments map[string]string `json:"arguments,omitempty"`
-		Meta      types.Meta        `json:"_meta,omitempty"`
-	}{
+	params := &types.GetPromptParams{
 		Name:      name,
 		Arguments: arguments,
 	}
 
 	var result types.GetPromptResult
-	err := c.transport.Call(c.ctx, &result, "prompts/get", params)
+	err := c.call(ctx, &result, "prompts/get", params)
 	if err != nil {
 		return nil, err
 	}
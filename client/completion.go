@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// CompletePromptArgument asks the server for completions of a prompt argument.
+// contextArgs holds the values of arguments that have already been filled in.
+// It returns nil if the server does not advertise the completions capability.
+func (c *Client) CompletePromptArgument(ctx context.Context, promptName, argName, value string, contextArgs map[string]string) (*types.CompleteResult, error) {
+	ref := types.PromptReference{
+		BaseMetadata: types.BaseMetadata{Name: promptName},
+		Type:         types.ReferenceTypePrompt,
+	}
+	return c.complete(ctx, ref, argName, value, contextArgs)
+}
+
+// CompleteResourceTemplateArgument asks the server for completions of a resource template variable.
+// contextArgs holds the values of variables that have already been filled in.
+// It returns nil if the server does not advertise the completions capability.
+func (c *Client) CompleteResourceTemplateArgument(ctx context.Context, uriTemplate, argName, value string, contextArgs map[string]string) (*types.CompleteResult, error) {
+	ref := types.ResourceTemplateReference{
+		Type: types.ReferenceTypeResource,
+		URI:  uriTemplate,
+	}
+	return c.complete(ctx, ref, argName, value, contextArgs)
+}
+
+// complete sends a completion/complete request for the given reference
+func (c *Client) complete(ctx context.Context, ref types.CompletionReference, argName, value string, contextArgs map[string]string) (*types.CompleteResult, error) {
+	if !c.HasCompletions() {
+		return nil, nil
+	}
+
+	var params types.CompleteRequest
+	params.Method = "completion/complete"
+	params.Params.Ref = ref
+	params.Params.Argument.Name = argName
+	params.Params.Argument.Value = value
+	if len(contextArgs) > 0 {
+		params.Params.Context = &types.CompletionContext{Arguments: contextArgs}
+	}
+
+	var result types.CompleteResult
+	err := c.call(ctx, &result, params.Method, params.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CompletionRequest describes a single completion/complete request
+type CompletionRequest struct {
+	Ref      types.CompletionReference // types.PromptReference or types.ResourceTemplateReference
+	Argument string                    // Name of the argument being completed
+	Value    string                    // Current (partial) value of the argument
+	Context  map[string]string         // Values of arguments already filled in
+}
+
+// Completer issues debounced completion requests for interactive UIs.
+// Each new request replaces the previous one: a pending request is dropped
+// and an in-flight request is cancelled, so only the latest input is answered.
+type Completer struct {
+	client *Client
+	delay  time.Duration
+
+	mu     sync.Mutex
+	seq    uint64
+	timer  *time.Timer
+	cancel context.CancelFunc
+}
+
+// NewCompleter creates a Completer that waits for delay without new input before sending a request
+func NewCompleter(c *Client, delay time.Duration) *Completer {
+	return &Completer{
+		client: c,
+		delay:  delay,
+	}
+}
+
+// Complete schedules a completion request and invokes callback with its result.
+// The callback runs on a separate goroutine and is not called at all if the
+// request is superseded by a later call to Complete or cancelled with Stop.
+func (d *Completer) Complete(ctx context.Context, req CompletionRequest, callback func(*types.CompleteResult, error)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stopLocked()
+	d.seq++
+	seq := d.seq
+
+	reqCtx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+
+	d.timer = time.AfterFunc(d.delay, func() {
+		defer cancel()
+
+		result, err := d.client.complete(reqCtx, req.Ref, req.Argument, req.Value, req.Context)
+
+		d.mu.Lock()
+		current := seq == d.seq && reqCtx.Err() == nil
+		d.mu.Unlock()
+
+		if current {
+			callback(result, err)
+		}
+	})
+}
+
+// Stop drops any pending request and cancels the one in flight
+func (d *Completer) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.stopLocked()
+	d.seq++
+}
+
+// stopLocked stops the timer and cancels the in-flight request; d.mu must be held
+func (d *Completer) stopLocked() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.cancel != nil {
+		d.cancel()
+		d.cancel = nil
+	}
+}
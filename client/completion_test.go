@@ -0,0 +1,191 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// completionTransport answers completion/complete with values prefixed by the argument value
+func completionTransport(gotParams *map[string]interface{}, delay time.Duration) *MockTransport {
+	var mu sync.Mutex
+	return &MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			if method != "completion/complete" {
+				return nil
+			}
+
+			data, _ := json.Marshal(params[0])
+			var decoded map[string]interface{}
+			_ = json.Unmarshal(data, &decoded)
+			if gotParams != nil {
+				mu.Lock()
+				*gotParams = decoded
+				mu.Unlock()
+			}
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			value := decoded["argument"].(map[string]interface{})["value"].(string)
+			completion := result.(*types.CompleteResult)
+			completion.Completion.Values = []string{value + "-1", value + "-2"}
+			return nil
+		},
+	}
+}
+
+func TestCompletePromptArgument(t *testing.T) {
+	var got map[string]interface{}
+	client := NewClient(WithTransport(completionTransport(&got, 0)))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Completions: &types.CompletionsCapability{}}
+
+	result, err := client.CompletePromptArgument(context.Background(), "code_review", "language", "py",
+		map[string]string{"framework": "django"})
+	if err != nil {
+		t.Fatalf("CompletePromptArgument failed: %v", err)
+	}
+
+	if len(result.Completion.Values) != 2 || result.Completion.Values[0] != "py-1" {
+		t.Errorf("Unexpected completion values: %v", result.Completion.Values)
+	}
+
+	ref := got["ref"].(map[string]interface{})
+	if ref["type"] != "ref/prompt" || ref["name"] != "code_review" {
+		t.Errorf("Unexpected ref: %v", ref)
+	}
+
+	ctxArgs := got["context"].(map[string]interface{})["arguments"].(map[string]interface{})
+	if ctxArgs["framework"] != "django" {
+		t.Errorf("Expected context arguments to be sent, got %v", got["context"])
+	}
+}
+
+func TestCompleteResourceTemplateArgument(t *testing.T) {
+	var got map[string]interface{}
+	client := NewClient(WithTransport(completionTransport(&got, 0)))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Completions: &types.CompletionsCapability{}}
+
+	_, err := client.CompleteResourceTemplateArgument(context.Background(), "file:///{path}", "path", "src", nil)
+	if err != nil {
+		t.Fatalf("CompleteResourceTemplateArgument failed: %v", err)
+	}
+
+	ref := got["ref"].(map[string]interface{})
+	if ref["type"] != "ref/resource" || ref["uri"] != "file:///{path}" {
+		t.Errorf("Unexpected ref: %v", ref)
+	}
+
+	if _, ok := got["context"]; ok {
+		t.Error("Expected context to be omitted when no context arguments are given")
+	}
+}
+
+func TestCompleteWithoutCapability(t *testing.T) {
+	called := false
+	client := NewClient(WithTransport(&MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			called = true
+			return nil
+		},
+	}))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{}
+
+	if client.HasCompletions() {
+		t.Error("HasCompletions() should be false without the capability")
+	}
+
+	result, err := client.CompletePromptArgument(context.Background(), "p", "a", "v", nil)
+	if err != nil || result != nil {
+		t.Errorf("Expected nil result and error, got %v, %v", result, err)
+	}
+	if called {
+		t.Error("No request should be sent when completions are not supported")
+	}
+}
+
+func TestCompleterDebounce(t *testing.T) {
+	var got map[string]interface{}
+	client := NewClient(WithTransport(completionTransport(&got, 0)))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Completions: &types.CompletionsCapability{}}
+
+	completer := NewCompleter(client, 20*time.Millisecond)
+
+	results := make(chan []string, 10)
+	ref := types.PromptReference{BaseMetadata: types.BaseMetadata{Name: "greet"}, Type: types.ReferenceTypePrompt}
+	for _, value := range []string{"a", "al", "ali"} {
+		completer.Complete(context.Background(), CompletionRequest{Ref: ref, Argument: "name", Value: value},
+			func(result *types.CompleteResult, err error) {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+					return
+				}
+				results <- result.Completion.Values
+			})
+	}
+
+	select {
+	case values := <-results:
+		if values[0] != "ali-1" {
+			t.Errorf("Expected completion for latest input, got %v", values)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for completion")
+	}
+
+	select {
+	case values := <-results:
+		t.Errorf("Superseded requests should not report results, got %v", values)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCompleterCancelsInFlight(t *testing.T) {
+	client := NewClient(WithTransport(completionTransport(nil, 200*time.Millisecond)))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Completions: &types.CompletionsCapability{}}
+
+	completer := NewCompleter(client, 0)
+
+	results := make(chan string, 10)
+	ref := types.ResourceTemplateReference{Type: types.ReferenceTypeResource, URI: "file:///{path}"}
+
+	completer.Complete(context.Background(), CompletionRequest{Ref: ref, Argument: "path", Value: "old"},
+		func(result *types.CompleteResult, err error) { results <- "old" })
+
+	// Let the first request reach the server before superseding it
+	time.Sleep(20 * time.Millisecond)
+
+	completer.Complete(context.Background(), CompletionRequest{Ref: ref, Argument: "path", Value: "new"},
+		func(result *types.CompleteResult, err error) { results <- "new" })
+
+	select {
+	case got := <-results:
+		if got != "new" {
+			t.Errorf("Expected only the latest request to complete, got %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for completion")
+	}
+
+	completer.Complete(context.Background(), CompletionRequest{Ref: ref, Argument: "path", Value: "stopped"},
+		func(result *types.CompleteResult, err error) { results <- "stopped" })
+	completer.Stop()
+
+	select {
+	case got := <-results:
+		t.Errorf("Stopped request should not report a result, got %q", got)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
@@ -28,18 +28,111 @@ The client supports various configuration options:
 		client.WithClientInfo("app-name", "1.0.0"),    // Set client identification
 		client.WithTimeout(30*time.Second),            // Set operation timeout
 		client.WithTransport(customTransport),         // Use custom transport
+		client.WithArgumentValidation(),               // Validate tool arguments before calling
+		client.WithOutputValidation(),                 // Validate structured tool output
+		client.WithLogSink(client.SlogSink(handler)),  // Forward server logs to slog
+		client.WithCatalog(),                          // Cache server lists and track list_changed
+		client.WithInterceptors(client.LoggingInterceptor(logger)), // Wrap every call
+		client.WithRetryPolicy(client.DefaultRetryPolicy()),        // Retry transient failures
+		client.WithToolPolicy(client.DenyDestructive()),            // Approve tool calls
+		client.WithClientCapabilities(caps),                        // Declare sampling, roots or elicitation support
 	)
 
 # Supported Operations
 
 The client supports all MCP protocol operations:
 
+  - Ping(ctx) - Check that the server is responsive
   - ListTools() - List available tools
   - CallTool(name, args) - Call a specific tool
+  - CallTyped[In, Out](ctx, c, name, in) - Call a tool with typed arguments and output
   - ListResources() - List available resources
   - ReadResource(uri) - Read resource content
+  - ListResourceTemplates() - List all resource templates, following pagination
+  - ReadResourceTemplate(ctx, uriTemplate, vars) - Expand an RFC 6570 template and read the resource
   - ListPrompts() - List available prompts
   - GetPrompt(name, args) - Get prompt with arguments
+  - GetPromptContext(ctx, name, args) - Get prompt with arguments using a context
+  - CompletePromptArgument(ctx, prompt, arg, value, contextArgs) - Complete a prompt argument
+  - CompleteResourceTemplateArgument(ctx, uriTemplate, arg, value, contextArgs) - Complete a template variable
+
+# Catalog
+
+With WithCatalog the client keeps a cached catalog of tools, resources,
+resource templates and prompts. Lists are loaded on first use and refreshed
+when the server sends a list_changed notification:
+
+	c.Catalog().OnToolsChanged(func(change client.ListChange[types.Tool]) {
+		for _, tool := range change.Added {
+			log.Printf("new tool: %s", tool.Name)
+		}
+	})
+
+	tool, err := c.Catalog().Tool(ctx, "get_weather")
+
+# Interceptors
+
+Interceptors wrap every outgoing request and every incoming server request and
+notification. They see the method, the typed params, the result and the error,
+and can rewrite params or answer a call themselves:
+
+	audit := func(ctx context.Context, call *client.Call, next client.Handler) error {
+		if params, ok := call.Params.(*types.CallToolParams); ok {
+			delete(params.Arguments, "api_key")
+		}
+		return next(ctx, call)
+	}
+
+	c := client.NewClient(
+		client.WithTransport(t),
+		client.WithInterceptors(audit, client.TimingInterceptor(recordMetric)),
+	)
+
+Requests sent by the server are answered by handlers registered with
+WithRequestHandler. A handler for ping is built in.
+
+# Retries
+
+WithRetryPolicy retries requests that fail with transient errors, such as
+timeouts, connection resets and HTTP 5xx responses, using exponential backoff
+with jitter. Only idempotent requests are retried: list, read and get
+requests, ping, and tools/call for tools annotated with readOnlyHint or
+idempotentHint. Other tool calls can opt in per call:
+
+	result, err := c.CallToolContext(client.AllowRetry(ctx), "send_report", args)
+
+# Tool Policies
+
+Tool policies run before every tools/call with the tool's definition and
+arguments, and can approve, deny or change the call. Built-in policies deny
+destructive tools, ask for confirmation, or filter tools by name:
+
+	c := client.NewClient(
+		client.WithTransport(t),
+		client.WithToolPolicy(
+			client.AllowTools("fs_*", "search"),
+			client.ConfirmDestructive(askUser),
+		),
+	)
+
+Denied calls return a *ToolDeniedError.
+
+# Multiple Servers
+
+A Pool merges the tools, resources and prompts of several clients into one
+catalog. Tool and prompt names are prefixed with the server's prefix, and
+calls are routed to the owning server:
+
+	pool := client.NewPool(client.WithConflictPolicy(client.ConflictFirstWins))
+	pool.Add("github", githubClient)
+	pool.Add("fs", fsClient, client.WithPrefix("files"))
+
+	tools, err := pool.Tools(ctx) // github__search, files__read, ...
+	result, err := pool.CallTool(ctx, "files__read", args)
+
+Servers that cannot be reached are marked unhealthy and left out of the
+catalog while the others keep serving. Health reports per-server status and
+Check pings every server.
 
 # Error Handling
 
@@ -51,6 +144,14 @@ All client methods return appropriate Go errors:
 		return fmt.Errorf("failed to list tools: %w", err)
 	}
 
+Tool calls made through CallTyped report tool-level failures as *ToolError:
+
+	out, err := client.CallTyped[WeatherInput, WeatherOutput](ctx, c, "get_weather", in)
+	var toolErr *client.ToolError
+	if errors.As(err, &toolErr) {
+		log.Printf("tool failed: %s", toolErr.Text())
+	}
+
 # Transport Layer
 
 The client works with different transport implementations:
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/Convict3d/mcp-go/transport"
+)
+
+// Direction tells whether a call was sent by the client or received from the server
+type Direction int
+
+const (
+	// Outgoing calls are requests sent by the client to the server
+	Outgoing Direction = iota
+	// Incoming calls are requests and notifications sent by the server to the client
+	Incoming
+)
+
+// String returns "outgoing" or "incoming"
+func (d Direction) String() string {
+	if d == Incoming {
+		return "incoming"
+	}
+	return "outgoing"
+}
+
+// Call describes an MCP message passing through the interceptor chain
+type Call struct {
+	Direction    Direction
+	Method       string
+	Notification bool // Set for notifications, which have no result
+
+	// Params holds the request parameters. Outgoing calls carry the typed
+	// params built by the client, such as *types.CallToolParams, which
+	// interceptors may modify or replace. Incoming calls carry decoded JSON.
+	Params interface{}
+
+	// Result holds the response. For outgoing calls it is a pointer that the
+	// server's result is decoded into. For incoming requests it is the value
+	// returned to the server, available after next returns.
+	Result interface{}
+}
+
+// SetResult stores v as the result of the call. Interceptors use it to
+// short-circuit a call by answering without calling next. For outgoing calls
+// v is copied into the result pointer through a JSON round trip, so it may be
+// any value with a compatible JSON encoding.
+func (c *Call) SetResult(v interface{}) error {
+	if c.Direction == Incoming {
+		c.Result = v
+		return nil
+	}
+
+	if c.Result == nil {
+		return fmt.Errorf("call %s has no result destination", c.Method)
+	}
+
+	// Assign directly when the types match to avoid a needless round trip
+	dst := reflect.ValueOf(c.Result)
+	if dst.Kind() == reflect.Ptr && !dst.IsNil() {
+		src := reflect.ValueOf(v)
+		if src.IsValid() && src.Type().AssignableTo(dst.Elem().Type()) {
+			dst.Elem().Set(src)
+			return nil
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	if err := json.Unmarshal(data, c.Result); err != nil {
+		return fmt.Errorf("failed to decode result: %w", err)
+	}
+	return nil
+}
+
+// Handler processes a call at the end of the interceptor chain
+type Handler func(ctx context.Context, call *Call) error
+
+// Interceptor wraps the handling of a call. It may inspect or modify the call
+// before invoking next, inspect the result and error afterwards, or skip next
+// entirely and answer the call itself with SetResult.
+type Interceptor func(ctx context.Context, call *Call, next Handler) error
+
+// WithInterceptors adds interceptors that wrap every outgoing and incoming
+// request and notification. Interceptors run in the order
+// given, the first being the outermost.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *Config) {
+		c.Interceptors = append(c.Interceptors, interceptors...)
+	}
+}
+
+// chain runs call through the configured interceptors and then final
+func (c *Client) chain(ctx context.Context, call *Call, final Handler) error {
+	interceptors := c.config.Interceptors
+	if len(interceptors) == 0 {
+		return final(ctx, call)
+	}
+
+	handler := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context, call *Call) error {
+			return interceptor(ctx, call, next)
+		}
+	}
+	return handler(ctx, call)
+}
+
+// call sends a request to the server through the interceptor chain,
+// retrying it under the retry policy. Each attempt passes through the chain.
+// A nil params sends the request without parameters.
+func (c *Client) call(ctx context.Context, result interface{}, method string, params interface{}) error {
+	return c.withRetry(ctx, method, params, func() error {
+		call := &Call{
+			Direction: Outgoing,
+			Method:    method,
+			Params:    params,
+			Result:    result,
+		}
+
+		return c.chain(ctx, call, func(ctx context.Context, call *Call) error {
+			if call.Params == nil {
+				return c.transport.Call(ctx, call.Result, call.Method)
+			}
+			return c.transport.Call(ctx, call.Result, call.Method, call.Params)
+		})
+	})
+}
+
+// notify sends a notification to the server through the interceptor chain.
+// It does nothing when the transport cannot send notifications.
+func (c *Client) notify(ctx context.Context, method string, params interface{}) error {
+	notifier, ok := c.transport.(transport.Notifier)
+	if !ok {
+		return nil
+	}
+
+	call := &Call{
+		Direction:    Outgoing,
+		Method:       method,
+		Params:       params,
+		Notification: true,
+	}
+	return c.chain(ctx, call, func(ctx context.Context, call *Call) error {
+		return notifier.Notify(ctx, call.Method, call.Params)
+	})
+}
+
+// TimingInterceptor returns an interceptor that reports the duration and
+// outcome of every call to observe, for example to record metrics
+func TimingInterceptor(observe func(call *Call, duration time.Duration, err error)) Interceptor {
+	return func(ctx context.Context, call *Call, next Handler) error {
+		start := time.Now()
+		err := next(ctx, call)
+		observe(call, time.Since(start), err)
+		return err
+	}
+}
+
+// LoggingInterceptor returns an interceptor that logs every call to logger.
+// Successful calls are logged at debug level and failed calls at error level.
+// Params and results are not logged; add a custom interceptor to log them
+// with any redaction they need.
+func LoggingInterceptor(logger *slog.Logger) Interceptor {
+	return func(ctx context.Context, call *Call, next Handler) error {
+		start := time.Now()
+		err := next(ctx, call)
+
+		attrs := []slog.Attr{
+			slog.String("direction", call.Direction.String()),
+			slog.String("method", call.Method),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", err.Error()))
+			logger.LogAttrs(ctx, slog.LevelError, "mcp call failed", attrs...)
+		} else {
+			logger.LogAttrs(ctx, slog.LevelDebug, "mcp call", attrs...)
+		}
+
+		return err
+	}
+}
@@ -0,0 +1,264 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// RequestingTransport is a NotifyingTransport that can also deliver server requests
+type RequestingTransport struct {
+	NotifyingTransport
+	requestHandler func(method string, params interface{}) (interface{}, error)
+}
+
+func (r *RequestingTransport) SetRequestHandler(handler func(method string, params interface{}) (interface{}, error)) {
+	r.requestHandler = handler
+}
+
+func TestInterceptorChainOrder(t *testing.T) {
+	var order []string
+	record := func(name string) Interceptor {
+		return func(ctx context.Context, call *Call, next Handler) error {
+			order = append(order, name+" before")
+			err := next(ctx, call)
+			order = append(order, name+" after")
+			return err
+		}
+	}
+
+	client := NewClient(
+		WithTransport(&MockTransport{
+			callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+				order = append(order, "transport")
+				return nil
+			},
+		}),
+		WithInterceptors(record("outer"), record("inner")),
+	)
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	if _, err := client.ListTools(); err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+
+	expected := "outer before,inner before,transport,inner after,outer after"
+	if got := strings.Join(order, ","); got != expected {
+		t.Errorf("Unexpected order: %s", got)
+	}
+}
+
+func TestInterceptorSeesTypedCall(t *testing.T) {
+	var seen *Call
+	var sentArgs map[string]interface{}
+
+	client := NewClient(
+		WithTransport(&MockTransport{
+			callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+				sentArgs = params[0].(*types.CallToolParams).Arguments
+				result.(*types.CallToolResult).Content = []interface{}{"ok"}
+				return nil
+			},
+		}),
+		WithInterceptors(func(ctx context.Context, call *Call, next Handler) error {
+			// Rewrite arguments before the call is sent
+			if params, ok := call.Params.(*types.CallToolParams); ok {
+				params.Arguments["units"] = "metric"
+			}
+			err := next(ctx, call)
+			seen = call
+			return err
+		}),
+	)
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	result, err := client.CallTool("weather", map[string]interface{}{"city": "Oslo"})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	if seen.Direction != Outgoing || seen.Method != "tools/call" {
+		t.Errorf("Unexpected call: %+v", seen)
+	}
+	if seen.Result.(*types.CallToolResult) != result {
+		t.Error("Interceptor should see the result the caller receives")
+	}
+	if sentArgs["units"] != "metric" || sentArgs["city"] != "Oslo" {
+		t.Errorf("Expected rewritten arguments to be sent, got %v", sentArgs)
+	}
+}
+
+func TestInterceptorShortCircuit(t *testing.T) {
+	called := false
+	client := NewClient(
+		WithTransport(&MockTransport{
+			callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+				called = true
+				return nil
+			},
+		}),
+		WithInterceptors(func(ctx context.Context, call *Call, next Handler) error {
+			if call.Method == "tools/list" {
+				return call.SetResult(map[string]interface{}{
+					"tools": []map[string]interface{}{{"name": "cached"}},
+				})
+			}
+			return next(ctx, call)
+		}),
+	)
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	tools, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if called {
+		t.Error("Short-circuited call should not reach the transport")
+	}
+	if len(tools) != 1 || tools[0].Name != "cached" {
+		t.Errorf("Expected short-circuit result, got %+v", tools)
+	}
+}
+
+func TestInterceptorIncomingNotifications(t *testing.T) {
+	transport := &NotifyingTransport{}
+
+	var calls []*Call
+	var logged int
+	client := NewClient(
+		WithTransport(transport),
+		WithLogSink(func(types.LoggingMessageNotification) { logged++ }),
+		WithInterceptors(func(ctx context.Context, call *Call, next Handler) error {
+			calls = append(calls, call)
+			// Drop debug log messages
+			if params, ok := call.Params.(map[string]interface{}); ok && params["level"] == "debug" {
+				return nil
+			}
+			return next(ctx, call)
+		}),
+	)
+	defer client.Close()
+
+	transport.notify("notifications/message", `{"level": "debug", "data": "noise"}`)
+	transport.notify("notifications/message", `{"level": "info", "data": "hello"}`)
+
+	if len(calls) != 2 || calls[0].Direction != Incoming || !calls[0].Notification {
+		t.Fatalf("Expected two incoming notifications, got %+v", calls)
+	}
+	if logged != 1 {
+		t.Errorf("Expected the interceptor to drop one message, got %d logged", logged)
+	}
+}
+
+func TestIncomingRequests(t *testing.T) {
+	transport := &RequestingTransport{}
+
+	var seen []string
+	client := NewClient(
+		WithTransport(transport),
+		WithRequestHandler("roots/list", func(ctx context.Context, params interface{}) (interface{}, error) {
+			return map[string]interface{}{"roots": []interface{}{}}, nil
+		}),
+		WithInterceptors(func(ctx context.Context, call *Call, next Handler) error {
+			err := next(ctx, call)
+			seen = append(seen, call.Direction.String()+" "+call.Method)
+			return err
+		}),
+	)
+	defer client.Close()
+
+	if transport.requestHandler == nil {
+		t.Fatal("Client did not register a request handler")
+	}
+
+	result, err := transport.requestHandler("roots/list", nil)
+	if err != nil {
+		t.Fatalf("roots/list failed: %v", err)
+	}
+	if _, ok := result.(map[string]interface{})["roots"]; !ok {
+		t.Errorf("Unexpected roots/list result: %v", result)
+	}
+
+	if _, err := transport.requestHandler("ping", nil); err != nil {
+		t.Errorf("Expected built-in ping handler, got %v", err)
+	}
+
+	if _, err := transport.requestHandler("sampling/createMessage", nil); err == nil {
+		t.Error("Expected error for a method without a handler")
+	}
+
+	if len(seen) != 3 || seen[0] != "incoming roots/list" {
+		t.Errorf("Expected interceptor to see incoming requests, got %v", seen)
+	}
+}
+
+func TestTimingInterceptor(t *testing.T) {
+	failure := errors.New("boom")
+
+	var method string
+	var duration time.Duration
+	var gotErr error
+	client := NewClient(
+		WithTransport(&MockTransport{
+			callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+				time.Sleep(10 * time.Millisecond)
+				return failure
+			},
+		}),
+		WithInterceptors(TimingInterceptor(func(call *Call, d time.Duration, err error) {
+			method, duration, gotErr = call.Method, d, err
+		})),
+	)
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Prompts: &types.PromptsCapability{}}
+
+	_, _ = client.ListPrompts()
+
+	if method != "prompts/list" || duration < 10*time.Millisecond || !errors.Is(gotErr, failure) {
+		t.Errorf("Unexpected timing observation: %s %v %v", method, duration, gotErr)
+	}
+}
+
+func TestLoggingInterceptor(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	fail := false
+	client := NewClient(
+		WithTransport(&MockTransport{
+			callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+				if fail {
+					return errors.New("unavailable")
+				}
+				return nil
+			},
+		}),
+		WithInterceptors(LoggingInterceptor(logger)),
+	)
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Resources: &types.ResourcesCapability{}}
+
+	_, _ = client.ListResources()
+	fail = true
+	_, _ = client.ReadResource("file:///secret.txt")
+
+	output := buf.String()
+	if !strings.Contains(output, "level=DEBUG msg=\"mcp call\" direction=outgoing method=resources/list") {
+		t.Errorf("Expected debug record for successful call, got:\n%s", output)
+	}
+	if !strings.Contains(output, "level=ERROR") || !strings.Contains(output, "error=unavailable") {
+		t.Errorf("Expected error record for failed call, got:\n%s", output)
+	}
+	if strings.Contains(output, "secret") {
+		t.Error("Params should not be logged")
+	}
+}
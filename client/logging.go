@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// ErrLoggingNotSupported is returned by SetLogLevel when the server does not
+// advertise the logging capability
+var ErrLoggingNotSupported = errors.New("server does not support logging")
+
+// LogSink receives log messages sent by the server with notifications/message
+type LogSink func(msg types.LoggingMessageNotification)
+
+// SetLogLevel asks the server to send log messages at level and above.
+// It returns ErrLoggingNotSupported without sending a request if the server
+// does not advertise the logging capability.
+func (c *Client) SetLogLevel(ctx context.Context, level types.LoggingLevel) error {
+	if !c.HasLogging() {
+		return ErrLoggingNotSupported
+	}
+
+	var params types.SetLevelRequest
+	params.Method = "logging/setLevel"
+	params.Params.Level = level
+
+	var result struct{}
+	return c.call(ctx, &result, params.Method, params.Params)
+}
+
+// handleLogMessage decodes a notifications/message notification and passes it to the log sink
+func (c *Client) handleLogMessage(params interface{}) {
+	if c.config.LogSink == nil {
+		return
+	}
+
+	var msg types.LoggingMessageNotification
+	msg.Method = "notifications/message"
+	if err := decodeParams(params, &msg.Params); err != nil {
+		return
+	}
+
+	c.config.LogSink(msg)
+}
+
+// SlogLevel maps an MCP logging level to a slog level.
+// The syslog levels without a slog equivalent are placed between and above the
+// standard slog levels: notice sits between info and warning, and critical,
+// alert and emergency sit above error in increasing order.
+func SlogLevel(level types.LoggingLevel) slog.Level {
+	switch level {
+	case types.LoggingLevelDebug:
+		return slog.LevelDebug
+	case types.LoggingLevelInfo:
+		return slog.LevelInfo
+	case types.LoggingLevelNotice:
+		return slog.LevelInfo + 2
+	case types.LoggingLevelWarning:
+		return slog.LevelWarn
+	case types.LoggingLevelError:
+		return slog.LevelError
+	case types.LoggingLevelCritical:
+		return slog.LevelError + 4
+	case types.LoggingLevelAlert:
+		return slog.LevelError + 8
+	case types.LoggingLevelEmergency:
+		return slog.LevelError + 12
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// SlogSink returns a LogSink that forwards server log messages to a slog.Handler.
+// String data becomes the record message; any other data is attached as the
+// "data" attribute. The server's logger name is attached as "logger".
+func SlogSink(handler slog.Handler) LogSink {
+	return func(msg types.LoggingMessageNotification) {
+		ctx := context.Background()
+		level := SlogLevel(msg.Params.Level)
+		if !handler.Enabled(ctx, level) {
+			return
+		}
+
+		message, isString := msg.Params.Data.(string)
+		if !isString {
+			message = "server log message"
+		}
+
+		record := slog.NewRecord(time.Now(), level, message, 0)
+		if msg.Params.Logger != "" {
+			record.AddAttrs(slog.String("logger", msg.Params.Logger))
+		}
+		if !isString && msg.Params.Data != nil {
+			record.AddAttrs(slog.Any("data", msg.Params.Data))
+		}
+
+		_ = handler.Handle(ctx, record)
+	}
+}
+
+// decodeParams converts generic notification or request params into a typed value
+func decodeParams(params interface{}, v interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal params: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode params: %w", err)
+	}
+	return nil
+}
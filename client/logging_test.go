@@ -0,0 +1,163 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// NotifyingTransport is a MockTransport that can deliver server notifications
+type NotifyingTransport struct {
+	MockTransport
+	handler func(method string, params interface{})
+}
+
+func (n *NotifyingTransport) SetNotificationHandler(handler func(method string, params interface{})) {
+	n.handler = handler
+}
+
+// notify delivers a notification the way a transport would, with params decoded from JSON
+func (n *NotifyingTransport) notify(method, params string) {
+	var decoded interface{}
+	_ = json.Unmarshal([]byte(params), &decoded)
+	n.handler(method, decoded)
+}
+
+func TestSetLogLevel(t *testing.T) {
+	var gotMethod string
+	var gotParams interface{}
+	mockTransport := &MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			gotMethod = method
+			gotParams = params[0]
+			return nil
+		},
+	}
+
+	client := NewClient(WithTransport(mockTransport))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Logging: &types.LoggingCapability{}}
+
+	if err := client.SetLogLevel(context.Background(), types.LoggingLevelWarning); err != nil {
+		t.Fatalf("SetLogLevel failed: %v", err)
+	}
+
+	if gotMethod != "logging/setLevel" {
+		t.Errorf("Expected method 'logging/setLevel', got %q", gotMethod)
+	}
+
+	data, _ := json.Marshal(gotParams)
+	if string(data) != `{"level":"warning"}` {
+		t.Errorf("Unexpected params: %s", data)
+	}
+}
+
+func TestSetLogLevelWithoutCapability(t *testing.T) {
+	called := false
+	client := NewClient(WithTransport(&MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			called = true
+			return nil
+		},
+	}))
+	defer client.Close()
+
+	if err := client.SetLogLevel(context.Background(), types.LoggingLevelDebug); !errors.Is(err, ErrLoggingNotSupported) {
+		t.Fatalf("SetLogLevel returned %v, expected %v", err, ErrLoggingNotSupported)
+	}
+	if called {
+		t.Error("No request should be sent when logging is not supported")
+	}
+}
+
+func TestLogSinkReceivesMessages(t *testing.T) {
+	transport := &NotifyingTransport{}
+
+	var received []types.LoggingMessageNotification
+	client := NewClient(
+		WithTransport(transport),
+		WithLogSink(func(msg types.LoggingMessageNotification) {
+			received = append(received, msg)
+		}),
+	)
+	defer client.Close()
+
+	if transport.handler == nil {
+		t.Fatal("Client did not register a notification handler")
+	}
+
+	transport.notify("notifications/message", `{"level": "error", "logger": "db", "data": {"query": "SELECT 1"}}`)
+	transport.notify("notifications/progress", `{"progressToken": 1, "progress": 10}`)
+
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 log message, got %d", len(received))
+	}
+
+	msg := received[0]
+	if msg.Method != "notifications/message" || msg.Params.Level != types.LoggingLevelError || msg.Params.Logger != "db" {
+		t.Errorf("Unexpected log message: %+v", msg)
+	}
+	if data, ok := msg.Params.Data.(map[string]interface{}); !ok || data["query"] != "SELECT 1" {
+		t.Errorf("Unexpected log data: %v", msg.Params.Data)
+	}
+}
+
+func TestSlogLevelMapping(t *testing.T) {
+	levels := []types.LoggingLevel{
+		types.LoggingLevelDebug,
+		types.LoggingLevelInfo,
+		types.LoggingLevelNotice,
+		types.LoggingLevelWarning,
+		types.LoggingLevelError,
+		types.LoggingLevelCritical,
+		types.LoggingLevelAlert,
+		types.LoggingLevelEmergency,
+	}
+
+	for i := 1; i < len(levels); i++ {
+		if SlogLevel(levels[i]) <= SlogLevel(levels[i-1]) {
+			t.Errorf("Expected %s to map above %s", levels[i], levels[i-1])
+		}
+	}
+
+	if SlogLevel(types.LoggingLevelWarning) != slog.LevelWarn {
+		t.Errorf("Expected warning to map to slog.LevelWarn, got %v", SlogLevel(types.LoggingLevelWarning))
+	}
+	if SlogLevel(types.LoggingLevelError) != slog.LevelError {
+		t.Errorf("Expected error to map to slog.LevelError, got %v", SlogLevel(types.LoggingLevelError))
+	}
+}
+
+func TestSlogSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := SlogSink(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	newMsg := func(level types.LoggingLevel, logger string, data interface{}) types.LoggingMessageNotification {
+		var msg types.LoggingMessageNotification
+		msg.Params.Level = level
+		msg.Params.Logger = logger
+		msg.Params.Data = data
+		return msg
+	}
+
+	sink(newMsg(types.LoggingLevelDebug, "", "hidden"))
+	sink(newMsg(types.LoggingLevelWarning, "fs", "disk almost full"))
+	sink(newMsg(types.LoggingLevelError, "", map[string]interface{}{"code": 42}))
+
+	output := buf.String()
+	if strings.Contains(output, "hidden") {
+		t.Error("Messages below the handler level should be dropped")
+	}
+	if !strings.Contains(output, `level=WARN msg="disk almost full" logger=fs`) {
+		t.Errorf("Expected warning record, got:\n%s", output)
+	}
+	if !strings.Contains(output, "level=ERROR") || !strings.Contains(output, "data=map[code:42]") {
+		t.Errorf("Expected error record with data attribute, got:\n%s", output)
+	}
+}
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// ToolCall describes a tools/call request awaiting approval by a ToolPolicy
+type ToolCall struct {
+	Name      string
+	Tool      *types.Tool // Definition from tools/list, nil if the server does not list the tool
+	Arguments map[string]interface{}
+}
+
+// ToolPolicy decides whether a tool call may proceed. It returns nil to
+// approve the call and an error to deny it. A policy may change the call by
+// modifying or replacing call.Arguments before approving it.
+type ToolPolicy func(ctx context.Context, call *ToolCall) error
+
+// ToolDeniedError is returned when a ToolPolicy denies a tool call
+type ToolDeniedError struct {
+	Tool   string
+	Reason string
+	Err    error // Error returned by the policy, if it was not a *ToolDeniedError
+}
+
+// Error implements the error interface
+func (e *ToolDeniedError) Error() string {
+	return fmt.Sprintf("tool %q denied: %s", e.Tool, e.Reason)
+}
+
+// Unwrap returns the error returned by the policy
+func (e *ToolDeniedError) Unwrap() error {
+	return e.Err
+}
+
+// WithToolPolicy adds policies that run before every tools/call, in the order
+// given. The first policy to deny a call stops it.
+func WithToolPolicy(policies ...ToolPolicy) Option {
+	return func(c *Config) {
+		c.ToolPolicies = append(c.ToolPolicies, policies...)
+	}
+}
+
+// applyToolPolicies runs the configured policies and returns the approved arguments
+func (c *Client) applyToolPolicies(ctx context.Context, name string, arguments map[string]interface{}) (map[string]interface{}, error) {
+	if len(c.config.ToolPolicies) == 0 {
+		return arguments, nil
+	}
+
+	tool, err := c.lookupTool(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	call := &ToolCall{Name: name, Tool: tool, Arguments: arguments}
+	for _, policy := range c.config.ToolPolicies {
+		if err := policy(ctx, call); err != nil {
+			if denied, ok := err.(*ToolDeniedError); ok {
+				return nil, denied
+			}
+			return nil, &ToolDeniedError{Tool: name, Reason: err.Error(), Err: err}
+		}
+	}
+
+	return call.Arguments, nil
+}
+
+// destructiveReason returns why a tool must be treated as destructive, or ""
+// if it is not. The spec defaults destructiveHint to true, so tools that are
+// not listed or have no annotations are treated as destructive unless they
+// are marked read-only.
+func destructiveReason(tool *types.Tool) string {
+	switch {
+	case tool == nil:
+		return "tool is not listed by the server"
+	case tool.Annotations == nil:
+		return "tool has no annotations and may be destructive"
+	case tool.Annotations.ReadOnlyHint:
+		return ""
+	case tool.Annotations.DestructiveHint:
+		return "tool is destructive"
+	}
+	return ""
+}
+
+// DenyDestructive returns a policy that denies tools annotated with
+// destructiveHint, tools without annotations and tools the server does not list
+func DenyDestructive() ToolPolicy {
+	return func(ctx context.Context, call *ToolCall) error {
+		if reason := destructiveReason(call.Tool); reason != "" {
+			return &ToolDeniedError{Tool: call.Name, Reason: reason}
+		}
+		return nil
+	}
+}
+
+// ConfirmDestructive returns a policy that asks confirm before running tools
+// that DenyDestructive would deny, for example by prompting a human. The call
+// is denied if confirm returns false or an error.
+func ConfirmDestructive(confirm func(ctx context.Context, call *ToolCall) (bool, error)) ToolPolicy {
+	return func(ctx context.Context, call *ToolCall) error {
+		if destructiveReason(call.Tool) == "" {
+			return nil
+		}
+
+		approved, err := confirm(ctx, call)
+		if err != nil {
+			return &ToolDeniedError{Tool: call.Name, Reason: "confirmation failed: " + err.Error(), Err: err}
+		}
+		if !approved {
+			return &ToolDeniedError{Tool: call.Name, Reason: "not confirmed"}
+		}
+		return nil
+	}
+}
+
+// AllowTools returns a policy that only permits tools whose names match one
+// of the given glob patterns, using the syntax of path.Match
+func AllowTools(patterns ...string) ToolPolicy {
+	return func(ctx context.Context, call *ToolCall) error {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, call.Name); matched {
+				return nil
+			}
+		}
+		return &ToolDeniedError{Tool: call.Name, Reason: "tool is not in the allowlist"}
+	}
+}
+
+// DenyTools returns a policy that rejects tools whose names match one of the
+// given glob patterns, using the syntax of path.Match
+func DenyTools(patterns ...string) ToolPolicy {
+	return func(ctx context.Context, call *ToolCall) error {
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, call.Name); matched {
+				return &ToolDeniedError{Tool: call.Name, Reason: "tool is in the denylist"}
+			}
+		}
+		return nil
+	}
+}
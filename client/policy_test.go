@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// policyClient returns a client whose server lists destructive, read-only and
+// unannotated tools on two pages, recording the arguments of every tools/call that reaches the transport
+func policyClient(sent *[]map[string]interface{}, policies ...ToolPolicy) *Client {
+	client := NewClient(
+		WithTransport(&MockTransport{
+			callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+				switch method {
+				case "tools/list":
+					if data, _ := json.Marshal(params); strings.Contains(string(data), `"cursor":"2"`) {
+						return json.Unmarshal([]byte(`{"tools": [
+							{"name": "list_files", "inputSchema": {"type": "object"}, "annotations": {"readOnlyHint": true}}
+						]}`), result)
+					}
+					return json.Unmarshal([]byte(`{"tools": [
+						{"name": "delete_file", "inputSchema": {"type": "object"}, "annotations": {"destructiveHint": true}},
+						{"name": "read_file", "inputSchema": {"type": "object"}, "annotations": {"readOnlyHint": true}},
+						{"name": "admin_reset", "inputSchema": {"type": "object"}}
+					], "nextCursor": "2"}`), result)
+				case "tools/call":
+					*sent = append(*sent, params[0].(*types.CallToolParams).Arguments)
+				}
+				return nil
+			},
+		}),
+		WithToolPolicy(policies...),
+	)
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+	return client
+}
+
+func TestDenyDestructive(t *testing.T) {
+	var sent []map[string]interface{}
+	client := policyClient(&sent, DenyDestructive())
+	defer client.Close()
+
+	_, err := client.CallTool("delete_file", map[string]interface{}{"path": "/etc/hosts"})
+
+	var denied *ToolDeniedError
+	if !errors.As(err, &denied) {
+		t.Fatalf("Expected ToolDeniedError, got %v", err)
+	}
+	if denied.Tool != "delete_file" || denied.Error() != `tool "delete_file" denied: tool is destructive` {
+		t.Errorf("Unexpected denial: %v", denied)
+	}
+
+	if _, err := client.CallTool("read_file", nil); err != nil {
+		t.Errorf("Read-only tool should be allowed, got %v", err)
+	}
+	if _, err := client.CallTool("list_files", nil); err != nil {
+		t.Errorf("Read-only tool on the second page should be allowed, got %v", err)
+	}
+
+	// Without annotations the spec's default destructiveHint applies
+	for name, reason := range map[string]string{
+		"admin_reset": "tool has no annotations and may be destructive",
+		"unknown":     "tool is not listed by the server",
+	} {
+		if _, err := client.CallTool(name, nil); !errors.As(err, &denied) || denied.Reason != reason {
+			t.Errorf("%s: expected denial %q, got %v", name, reason, err)
+		}
+	}
+	if len(sent) != 2 {
+		t.Errorf("Expected only the allowed calls to be sent, got %d", len(sent))
+	}
+}
+
+func TestConfirmDestructive(t *testing.T) {
+	var sent []map[string]interface{}
+	var asked []string
+	approve := false
+	client := policyClient(&sent, ConfirmDestructive(func(ctx context.Context, call *ToolCall) (bool, error) {
+		asked = append(asked, call.Name)
+		return approve, nil
+	}))
+	defer client.Close()
+
+	if _, err := client.CallTool("delete_file", nil); err == nil {
+		t.Error("Expected unconfirmed call to be denied")
+	}
+	approve = true
+	if _, err := client.CallTool("delete_file", nil); err != nil {
+		t.Errorf("Expected confirmed call to proceed, got %v", err)
+	}
+	if _, err := client.CallTool("read_file", nil); err != nil {
+		t.Errorf("Expected non-destructive call to proceed, got %v", err)
+	}
+
+	if len(asked) != 2 || len(sent) != 2 {
+		t.Errorf("Expected 2 confirmations and 2 calls, got %v and %d", asked, len(sent))
+	}
+}
+
+func TestAllowAndDenyTools(t *testing.T) {
+	var sent []map[string]interface{}
+	client := policyClient(&sent, AllowTools("read_*", "delete_file"), DenyTools("delete_*"))
+	defer client.Close()
+
+	if _, err := client.CallTool("read_file", nil); err != nil {
+		t.Errorf("Expected read_file to match the allowlist, got %v", err)
+	}
+
+	var denied *ToolDeniedError
+	if _, err := client.CallTool("admin_reset", nil); !errors.As(err, &denied) || denied.Reason != "tool is not in the allowlist" {
+		t.Errorf("Expected admin_reset to be denied by the allowlist, got %v", err)
+	}
+	if _, err := client.CallTool("delete_file", nil); !errors.As(err, &denied) || denied.Reason != "tool is in the denylist" {
+		t.Errorf("Expected delete_file to be denied by the denylist, got %v", err)
+	}
+}
+
+func TestToolPolicyRewritesArguments(t *testing.T) {
+	var sent []map[string]interface{}
+	var seenTool *types.Tool
+	client := policyClient(&sent, func(ctx context.Context, call *ToolCall) error {
+		seenTool = call.Tool
+		call.Arguments = map[string]interface{}{"path": "/sandbox/" + call.Arguments["path"].(string)}
+		return nil
+	})
+	defer client.Close()
+
+	if _, err := client.CallTool("read_file", map[string]interface{}{"path": "notes.txt"}); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	if seenTool == nil || !seenTool.Annotations.ReadOnlyHint {
+		t.Errorf("Expected the policy to receive the tool definition, got %+v", seenTool)
+	}
+	if len(sent) != 1 || sent[0]["path"] != "/sandbox/notes.txt" {
+		t.Errorf("Expected rewritten arguments to be sent, got %v", sent)
+	}
+}
+
+func TestToolPolicyWrapsPlainErrors(t *testing.T) {
+	var sent []map[string]interface{}
+	quota := errors.New("quota exceeded")
+	client := policyClient(&sent, func(ctx context.Context, call *ToolCall) error {
+		return quota
+	})
+	defer client.Close()
+
+	_, err := client.CallTool("read_file", nil)
+
+	var denied *ToolDeniedError
+	if !errors.As(err, &denied) || denied.Reason != "quota exceeded" || !errors.Is(err, quota) {
+		t.Errorf("Expected policy error wrapped in ToolDeniedError, got %v", err)
+	}
+	if len(sent) != 0 {
+		t.Error("Denied call should not be sent")
+	}
+}
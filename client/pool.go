@@ -0,0 +1,590 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Convict3d/mcp-go/transport"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// ConflictPolicy decides which server owns an entry when several servers
+// expose the same namespaced name or resource URI
+type ConflictPolicy int
+
+const (
+	// ConflictFirstWins keeps the entry of the server added first
+	ConflictFirstWins ConflictPolicy = iota
+	// ConflictLastWins keeps the entry of the server added last
+	ConflictLastWins
+	// ConflictSkip drops conflicting entries from the merged catalog
+	ConflictSkip
+)
+
+// ErrNotFound is returned when no server in a pool provides the requested tool, prompt or resource
+var ErrNotFound = errors.New("not provided by any server")
+
+// DefaultPoolSeparator separates the server prefix from tool and prompt names
+const DefaultPoolSeparator = "__"
+
+// ServerHealth reports the reachability of a server in a Pool
+type ServerHealth struct {
+	Name      string
+	Healthy   bool
+	LastError error     // Most recent transport-level failure, nil when healthy
+	LastCheck time.Time // Time of the most recent request to the server
+}
+
+// PoolTool is a tool in the merged catalog of a Pool
+type PoolTool struct {
+	types.Tool        // Tool definition with the namespaced name
+	Server     string // Name of the server providing the tool
+	Original   string // Name of the tool on its server
+}
+
+// PoolPrompt is a prompt in the merged catalog of a Pool
+type PoolPrompt struct {
+	types.Prompt        // Prompt definition with the namespaced name
+	Server       string // Name of the server providing the prompt
+	Original     string // Name of the prompt on its server
+}
+
+// PoolResource is a resource in the merged catalog of a Pool
+type PoolResource struct {
+	types.Resource
+	Server string // Name of the server providing the resource
+}
+
+// PoolOption configures a Pool
+type PoolOption func(*Pool)
+
+// WithPoolSeparator sets the separator placed between a server's prefix and
+// the names of its tools and prompts. The default is DefaultPoolSeparator.
+func WithPoolSeparator(separator string) PoolOption {
+	return func(p *Pool) {
+		p.separator = separator
+	}
+}
+
+// WithConflictPolicy sets how conflicting names and URIs are resolved.
+// The default is ConflictFirstWins.
+func WithConflictPolicy(policy ConflictPolicy) PoolOption {
+	return func(p *Pool) {
+		p.conflicts = policy
+	}
+}
+
+// ServerOption configures a server added to a Pool
+type ServerOption func(*poolServer)
+
+// WithPrefix sets the prefix for the server's tool and prompt names.
+// The default prefix is the server name; an empty prefix keeps names unchanged.
+func WithPrefix(prefix string) ServerOption {
+	return func(s *poolServer) {
+		s.prefix = prefix
+	}
+}
+
+// poolServer is a client managed by a Pool
+type poolServer struct {
+	name   string
+	prefix string
+	client *Client
+
+	mu     sync.Mutex
+	health ServerHealth
+}
+
+// Pool aggregates several clients into one catalog of tools, resources and
+// prompts. Tool and prompt names are prefixed with their server's prefix, and
+// calls are routed to the server that owns the name. Servers that fail are
+// marked unhealthy and left out of the merged catalog while the others keep
+// serving.
+type Pool struct {
+	separator string
+	conflicts ConflictPolicy
+
+	mu      sync.RWMutex
+	servers []*poolServer
+
+	// Routing tables from the most recent listings
+	routesMu  sync.RWMutex
+	tools     map[string]PoolTool
+	prompts   map[string]PoolPrompt
+	resources map[string]PoolResource
+}
+
+// NewPool creates an empty pool
+func NewPool(opts ...PoolOption) *Pool {
+	p := &Pool{separator: DefaultPoolSeparator}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Add registers an initialized client under name
+func (p *Pool) Add(name string, c *Client, opts ...ServerOption) error {
+	server := &poolServer{
+		name:   name,
+		prefix: name,
+		client: c,
+		health: ServerHealth{Name: name, Healthy: true},
+	}
+	for _, opt := range opts {
+		opt(server)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, existing := range p.servers {
+		if existing.name == name {
+			return fmt.Errorf("server %q is already in the pool", name)
+		}
+	}
+	p.servers = append(p.servers, server)
+	return nil
+}
+
+// Remove removes the named server from the pool without closing its client.
+// It reports whether the server was found.
+func (p *Pool) Remove(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i, server := range p.servers {
+		if server.name == name {
+			p.servers = append(p.servers[:i], p.servers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Client returns the client registered under name, or nil
+func (p *Pool) Client(name string) *Client {
+	if server := p.server(name); server != nil {
+		return server.client
+	}
+	return nil
+}
+
+// Close closes every client in the pool and returns the first error
+func (p *Pool) Close() error {
+	var firstErr error
+	for _, server := range p.snapshot() {
+		if err := server.client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Health returns the health of every server, in the order they were added
+func (p *Pool) Health() []ServerHealth {
+	servers := p.snapshot()
+	health := make([]ServerHealth, 0, len(servers))
+	for _, server := range servers {
+		server.mu.Lock()
+		health = append(health, server.health)
+		server.mu.Unlock()
+	}
+	return health
+}
+
+// Check pings every server concurrently and updates their health
+func (p *Pool) Check(ctx context.Context) []ServerHealth {
+	p.each(ctx, func(ctx context.Context, server *poolServer) error {
+		return server.client.Ping(ctx)
+	})
+	return p.Health()
+}
+
+// Tools lists the tools of every server and returns the merged catalog.
+// Servers that fail are marked unhealthy and skipped; an error is returned
+// only if every server fails.
+func (p *Pool) Tools(ctx context.Context) ([]PoolTool, error) {
+	lists := make(map[*poolServer][]types.Tool)
+	var mu sync.Mutex
+
+	err := p.each(ctx, func(ctx context.Context, server *poolServer) error {
+		tools, err := server.client.listToolsContext(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		lists[server] = tools
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := newMerger[PoolTool](p.conflicts)
+	for _, server := range p.snapshot() {
+		for _, tool := range lists[server] {
+			entry := PoolTool{Tool: tool, Server: server.name, Original: tool.Name}
+			entry.Name = p.namespaced(server, tool.Name)
+			merged.add(entry.Name, entry)
+		}
+	}
+
+	p.routesMu.Lock()
+	p.tools = merged.index
+	p.routesMu.Unlock()
+
+	return merged.list(), nil
+}
+
+// Prompts lists the prompts of every server and returns the merged catalog.
+// Failing servers are handled as in Tools.
+func (p *Pool) Prompts(ctx context.Context) ([]PoolPrompt, error) {
+	lists := make(map[*poolServer][]types.Prompt)
+	var mu sync.Mutex
+
+	err := p.each(ctx, func(ctx context.Context, server *poolServer) error {
+		prompts, err := server.client.listPromptsContext(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		lists[server] = prompts
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := newMerger[PoolPrompt](p.conflicts)
+	for _, server := range p.snapshot() {
+		for _, prompt := range lists[server] {
+			entry := PoolPrompt{Prompt: prompt, Server: server.name, Original: prompt.Name}
+			entry.Name = p.namespaced(server, prompt.Name)
+			merged.add(entry.Name, entry)
+		}
+	}
+
+	p.routesMu.Lock()
+	p.prompts = merged.index
+	p.routesMu.Unlock()
+
+	return merged.list(), nil
+}
+
+// Resources lists the resources of every server and returns the merged
+// catalog. Resource URIs are not prefixed; conflicting URIs are resolved with
+// the pool's conflict policy. Failing servers are handled as in Tools.
+func (p *Pool) Resources(ctx context.Context) ([]PoolResource, error) {
+	lists := make(map[*poolServer][]types.Resource)
+	var mu sync.Mutex
+
+	err := p.each(ctx, func(ctx context.Context, server *poolServer) error {
+		resources, err := server.client.listResourcesContext(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		lists[server] = resources
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := newMerger[PoolResource](p.conflicts)
+	for _, server := range p.snapshot() {
+		for _, resource := range lists[server] {
+			merged.add(resource.URI, PoolResource{Resource: resource, Server: server.name})
+		}
+	}
+
+	p.routesMu.Lock()
+	p.resources = merged.index
+	p.routesMu.Unlock()
+
+	return merged.list(), nil
+}
+
+// CallTool calls a tool by its namespaced name on the server that owns it.
+// The tool catalog is listed first if the name is not known yet.
+func (p *Pool) CallTool(ctx context.Context, name string, arguments map[string]interface{}) (*types.CallToolResult, error) {
+	p.routesMu.RLock()
+	tool, ok := p.tools[name]
+	p.routesMu.RUnlock()
+
+	if !ok {
+		if _, err := p.Tools(ctx); err != nil {
+			return nil, err
+		}
+		p.routesMu.RLock()
+		tool, ok = p.tools[name]
+		p.routesMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("tool %q: %w", name, ErrNotFound)
+		}
+	}
+
+	server := p.server(tool.Server)
+	if server == nil {
+		return nil, fmt.Errorf("server %q is no longer in the pool", tool.Server)
+	}
+
+	result, err := server.client.CallToolContext(ctx, tool.Original, arguments)
+	server.record(err)
+	return result, err
+}
+
+// GetPrompt gets a prompt by its namespaced name from the server that owns it.
+// The prompt catalog is listed first if the name is not known yet.
+func (p *Pool) GetPrompt(ctx context.Context, name string, arguments map[string]string) (*types.GetPromptResult, error) {
+	p.routesMu.RLock()
+	prompt, ok := p.prompts[name]
+	p.routesMu.RUnlock()
+
+	if !ok {
+		if _, err := p.Prompts(ctx); err != nil {
+			return nil, err
+		}
+		p.routesMu.RLock()
+		prompt, ok = p.prompts[name]
+		p.routesMu.RUnlock()
+		if !ok {
+			return nil, fmt.Errorf("prompt %q: %w", name, ErrNotFound)
+		}
+	}
+
+	server := p.server(prompt.Server)
+	if server == nil {
+		return nil, fmt.Errorf("server %q is no longer in the pool", prompt.Server)
+	}
+
+	result, err := server.client.GetPromptContext(ctx, prompt.Original, arguments)
+	server.record(err)
+	return result, err
+}
+
+// ReadResource reads a resource from the server that lists its URI. The
+// resource catalog is listed first if the URI is not known yet. URIs that are
+// not listed are matched against each healthy server's resource templates.
+func (p *Pool) ReadResource(ctx context.Context, uri string) (*types.ReadResourceResult, error) {
+	p.routesMu.RLock()
+	resource, ok := p.resources[uri]
+	p.routesMu.RUnlock()
+
+	if !ok {
+		if _, err := p.Resources(ctx); err != nil {
+			return nil, err
+		}
+		p.routesMu.RLock()
+		resource, ok = p.resources[uri]
+		p.routesMu.RUnlock()
+	}
+
+	var server *poolServer
+	if ok {
+		server = p.server(resource.Server)
+	} else {
+		server = p.templateOwner(ctx, uri)
+	}
+	if server == nil {
+		return nil, fmt.Errorf("resource %q: %w", uri, ErrNotFound)
+	}
+
+	result, err := server.client.ReadResourceContext(ctx, uri)
+	server.record(err)
+	return result, err
+}
+
+// templateOwner returns the first healthy server with a resource template matching uri
+func (p *Pool) templateOwner(ctx context.Context, uri string) *poolServer {
+	for _, server := range p.snapshot() {
+		if !server.healthy() {
+			continue
+		}
+		templates, err := server.client.listResourceTemplatesContext(ctx)
+		server.record(err)
+		if err != nil {
+			continue
+		}
+		if _, _, ok := MatchResourceTemplate(templates, uri); ok {
+			return server
+		}
+	}
+	return nil
+}
+
+// each runs fn for every server concurrently, recording the outcome in the
+// server's health. It returns an error only if every server failed.
+func (p *Pool) each(ctx context.Context, fn func(ctx context.Context, server *poolServer) error) error {
+	servers := p.snapshot()
+	if len(servers) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(servers))
+	var wg sync.WaitGroup
+	for i, server := range servers {
+		wg.Add(1)
+		go func(i int, server *poolServer) {
+			defer wg.Done()
+			errs[i] = fn(ctx, server)
+			server.record(errs[i])
+		}(i, server)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("all servers failed: %w", errors.Join(errs...))
+}
+
+// namespaced returns name with the server's prefix applied
+func (p *Pool) namespaced(server *poolServer, name string) string {
+	if server.prefix == "" {
+		return name
+	}
+	return server.prefix + p.separator + name
+}
+
+func (p *Pool) server(name string) *poolServer {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, server := range p.servers {
+		if server.name == name {
+			return server
+		}
+	}
+	return nil
+}
+
+func (p *Pool) snapshot() []*poolServer {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]*poolServer{}, p.servers...)
+}
+
+// record updates the server's health after a request. Only transport-level
+// failures mark a server unhealthy; errors returned by the server do not.
+func (s *poolServer) record(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.health.LastCheck = time.Now()
+	if err != nil && !isTransportFailure(err) {
+		return
+	}
+	s.health.Healthy = err == nil
+	s.health.LastError = err
+}
+
+func (s *poolServer) healthy() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.health.Healthy
+}
+
+// isTransportFailure reports whether err means the server could not be reached
+func isTransportFailure(err error) bool {
+	return IsRetryable(err) || errors.Is(err, transport.ErrClosed) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// merger builds a merged catalog, resolving key conflicts with a ConflictPolicy
+type merger[T any] struct {
+	policy     ConflictPolicy
+	index      map[string]T
+	order      []string
+	conflicted map[string]bool
+}
+
+func newMerger[T any](policy ConflictPolicy) *merger[T] {
+	return &merger[T]{policy: policy, index: make(map[string]T), conflicted: make(map[string]bool)}
+}
+
+func (m *merger[T]) add(key string, entry T) {
+	if m.conflicted[key] {
+		return
+	}
+
+	if _, exists := m.index[key]; !exists {
+		m.index[key] = entry
+		m.order = append(m.order, key)
+		return
+	}
+
+	switch m.policy {
+	case ConflictLastWins:
+		m.index[key] = entry
+	case ConflictSkip:
+		delete(m.index, key)
+		m.conflicted[key] = true
+	}
+}
+
+// list returns the merged entries in the order their keys were first seen
+func (m *merger[T]) list() []T {
+	entries := make([]T, 0, len(m.index))
+	for _, key := range m.order {
+		if entry, ok := m.index[key]; ok {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// listToolsContext lists all tools with ctx, from the catalog when it is enabled
+func (c *Client) listToolsContext(ctx context.Context) ([]types.Tool, error) {
+	if c.catalog != nil {
+		return c.catalog.Tools(ctx)
+	}
+	if !c.HasTools() {
+		return nil, nil
+	}
+
+	tools, err := listAll[types.Tool](ctx, c, "tools/list", "tools")
+	if err != nil {
+		return nil, err
+	}
+	c.cacheTools(tools)
+	return tools, nil
+}
+
+// listPromptsContext lists all prompts with ctx, from the catalog when it is enabled
+func (c *Client) listPromptsContext(ctx context.Context) ([]types.Prompt, error) {
+	if c.catalog != nil {
+		return c.catalog.Prompts(ctx)
+	}
+	if !c.HasPrompts() {
+		return nil, nil
+	}
+	return listAll[types.Prompt](ctx, c, "prompts/list", "prompts")
+}
+
+// listResourcesContext lists all resources with ctx, from the catalog when it is enabled
+func (c *Client) listResourcesContext(ctx context.Context) ([]types.Resource, error) {
+	if c.catalog != nil {
+		return c.catalog.Resources(ctx)
+	}
+	if !c.HasResources() {
+		return nil, nil
+	}
+	return listAll[types.Resource](ctx, c, "resources/list", "resources")
+}
+
+// listResourceTemplatesContext lists all resource templates with ctx, from the catalog when it is enabled
+func (c *Client) listResourceTemplatesContext(ctx context.Context) ([]types.ResourceTemplate, error) {
+	if c.catalog != nil {
+		return c.catalog.ResourceTemplates(ctx)
+	}
+	if !c.HasResources() {
+		return nil, nil
+	}
+	return listAll[types.ResourceTemplate](ctx, c, "resources/templates/list", "resourceTemplates")
+}
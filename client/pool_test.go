@@ -0,0 +1,292 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// fakeServer answers list and call requests for one server in a pool test
+type fakeServer struct {
+	mu        sync.Mutex
+	tools     []string
+	prompts   []string
+	resources []string
+	templates []string
+	down      bool
+	called    []string // Tool names and resource URIs requested
+}
+
+func (f *fakeServer) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+func (f *fakeServer) client() *Client {
+	c := NewClient(WithTransport(&MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if f.down {
+				return fmt.Errorf("dial: %w", syscall.ECONNREFUSED)
+			}
+
+			var list interface{}
+			switch method {
+			case "tools/list":
+				var tools []types.Tool
+				for _, name := range f.tools {
+					tools = append(tools, types.Tool{BaseMetadata: types.BaseMetadata{Name: name}})
+				}
+				list = map[string]interface{}{"tools": tools}
+			case "prompts/list":
+				var prompts []types.Prompt
+				for _, name := range f.prompts {
+					prompts = append(prompts, types.Prompt{BaseMetadata: types.BaseMetadata{Name: name}})
+				}
+				list = map[string]interface{}{"prompts": prompts}
+			case "resources/list":
+				var resources []types.Resource
+				for _, uri := range f.resources {
+					resources = append(resources, types.Resource{URI: uri})
+				}
+				list = map[string]interface{}{"resources": resources}
+			case "resources/templates/list":
+				var templates []types.ResourceTemplate
+				for _, tmpl := range f.templates {
+					templates = append(templates, types.ResourceTemplate{URITemplate: tmpl})
+				}
+				list = map[string]interface{}{"resourceTemplates": templates}
+			case "tools/call":
+				f.called = append(f.called, params[0].(*types.CallToolParams).Name)
+				return nil
+			case "prompts/get":
+				f.called = append(f.called, params[0].(*types.GetPromptParams).Name)
+				return nil
+			case "resources/read":
+				f.called = append(f.called, params[0].(*types.ReadResourceParams).URI)
+				return nil
+			default:
+				return nil
+			}
+
+			data, _ := json.Marshal(list)
+			return json.Unmarshal(data, result)
+		},
+	}))
+	c.capabilities = &types.ServerCapabilities{
+		Tools:     &types.ToolsCapability{},
+		Prompts:   &types.PromptsCapability{},
+		Resources: &types.ResourcesCapability{},
+	}
+	return c
+}
+
+func toolNames(tools []PoolTool) []string {
+	var names []string
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestPoolMergesAndRoutesTools(t *testing.T) {
+	github := &fakeServer{tools: []string{"search", "create_issue"}}
+	files := &fakeServer{tools: []string{"search", "read"}}
+
+	pool := NewPool()
+	defer pool.Close()
+	if err := pool.Add("github", github.client()); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := pool.Add("fs", files.client()); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := pool.Add("fs", files.client()); err == nil {
+		t.Error("Expected error for a duplicate server name")
+	}
+
+	tools, err := pool.Tools(context.Background())
+	if err != nil {
+		t.Fatalf("Tools failed: %v", err)
+	}
+
+	expected := fmt.Sprint([]string{"github__search", "github__create_issue", "fs__search", "fs__read"})
+	if got := fmt.Sprint(toolNames(tools)); got != expected {
+		t.Errorf("Tools = %s, expected %s", got, expected)
+	}
+	if tools[2].Server != "fs" || tools[2].Original != "search" {
+		t.Errorf("Unexpected tool ownership: %+v", tools[2])
+	}
+
+	if _, err := pool.CallTool(context.Background(), "fs__search", nil); err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if len(files.called) != 1 || files.called[0] != "search" || len(github.called) != 0 {
+		t.Errorf("Expected call routed to fs as 'search', got fs=%v github=%v", files.called, github.called)
+	}
+
+	if _, err := pool.CallTool(context.Background(), "missing", nil); err == nil {
+		t.Error("Expected error for an unknown tool")
+	}
+}
+
+func TestPoolConflictPolicies(t *testing.T) {
+	tests := []struct {
+		policy   ConflictPolicy
+		expected string
+		owner    string
+	}{
+		{ConflictFirstWins, "[search only_a only_b]", "a"},
+		{ConflictLastWins, "[search only_a only_b]", "b"},
+		{ConflictSkip, "[only_a only_b]", ""},
+	}
+
+	for _, tt := range tests {
+		a := &fakeServer{tools: []string{"search", "only_a"}}
+		b := &fakeServer{tools: []string{"search", "only_b"}}
+
+		pool := NewPool(WithConflictPolicy(tt.policy))
+		pool.Add("a", a.client(), WithPrefix(""))
+		pool.Add("b", b.client(), WithPrefix(""))
+
+		tools, err := pool.Tools(context.Background())
+		if err != nil {
+			t.Fatalf("Tools failed: %v", err)
+		}
+		if got := fmt.Sprint(toolNames(tools)); got != tt.expected {
+			t.Errorf("policy %d: Tools = %s, expected %s", tt.policy, got, tt.expected)
+		}
+		for _, tool := range tools {
+			if tool.Name == "search" && tool.Server != tt.owner {
+				t.Errorf("policy %d: search owned by %q, expected %q", tt.policy, tool.Server, tt.owner)
+			}
+		}
+	}
+}
+
+func TestPoolCustomPrefixAndSeparator(t *testing.T) {
+	server := &fakeServer{tools: []string{"run"}, prompts: []string{"review"}}
+
+	pool := NewPool(WithPoolSeparator("."))
+	pool.Add("code-server", server.client(), WithPrefix("code"))
+
+	tools, _ := pool.Tools(context.Background())
+	if len(tools) != 1 || tools[0].Name != "code.run" {
+		t.Errorf("Unexpected tools: %v", toolNames(tools))
+	}
+
+	prompts, _ := pool.Prompts(context.Background())
+	if len(prompts) != 1 || prompts[0].Name != "code.review" {
+		t.Errorf("Unexpected prompts: %+v", prompts)
+	}
+
+	if _, err := pool.GetPrompt(context.Background(), "code.review", nil); err != nil {
+		t.Fatalf("GetPrompt failed: %v", err)
+	}
+	if len(server.called) != 1 || server.called[0] != "review" {
+		t.Errorf("Expected prompt routed by original name, got %v", server.called)
+	}
+}
+
+func TestPoolSurvivesServerFailure(t *testing.T) {
+	healthy := &fakeServer{tools: []string{"ok"}}
+	broken := &fakeServer{tools: []string{"fails"}, down: true}
+
+	pool := NewPool()
+	pool.Add("healthy", healthy.client())
+	pool.Add("broken", broken.client())
+
+	tools, err := pool.Tools(context.Background())
+	if err != nil {
+		t.Fatalf("Tools should succeed while one server is reachable: %v", err)
+	}
+	if fmt.Sprint(toolNames(tools)) != "[healthy__ok]" {
+		t.Errorf("Expected only reachable tools, got %v", toolNames(tools))
+	}
+
+	health := pool.Health()
+	if len(health) != 2 || !health[0].Healthy || health[1].Healthy {
+		t.Fatalf("Unexpected health: %+v", health)
+	}
+	if !errors.Is(health[1].LastError, syscall.ECONNREFUSED) {
+		t.Errorf("Expected the failure to be recorded, got %v", health[1].LastError)
+	}
+
+	// The server recovers
+	broken.setDown(false)
+	health = pool.Check(context.Background())
+	if !health[1].Healthy || health[1].LastError != nil {
+		t.Errorf("Expected server to be healthy after recovery, got %+v", health[1])
+	}
+
+	healthy.setDown(true)
+	broken.setDown(true)
+	if _, err := pool.Tools(context.Background()); err == nil {
+		t.Error("Expected an error when every server fails")
+	}
+}
+
+func TestPoolReadResource(t *testing.T) {
+	docs := &fakeServer{resources: []string{"file:///README.md"}}
+	weather := &fakeServer{templates: []string{"weather://{city}"}}
+
+	pool := NewPool()
+	pool.Add("docs", docs.client())
+	pool.Add("weather", weather.client())
+
+	resources, err := pool.Resources(context.Background())
+	if err != nil || len(resources) != 1 || resources[0].Server != "docs" {
+		t.Fatalf("Unexpected resources: %+v, %v", resources, err)
+	}
+
+	if _, err := pool.ReadResource(context.Background(), "file:///README.md"); err != nil {
+		t.Errorf("ReadResource failed: %v", err)
+	}
+	if _, err := pool.ReadResource(context.Background(), "weather://Oslo"); err != nil {
+		t.Errorf("ReadResource through a template failed: %v", err)
+	}
+	if _, err := pool.ReadResource(context.Background(), "db://users"); err == nil {
+		t.Error("Expected error for a resource no server provides")
+	}
+
+	if len(docs.called) != 1 || len(weather.called) != 1 || weather.called[0] != "weather://Oslo" {
+		t.Errorf("Unexpected routing: docs=%v weather=%v", docs.called, weather.called)
+	}
+}
+
+func TestPoolReadResourceBeforeListing(t *testing.T) {
+	docs := &fakeServer{resources: []string{"file:///README.md"}}
+	pool := NewPool()
+	pool.Add("docs", docs.client())
+
+	if _, err := pool.ReadResource(context.Background(), "file:///README.md"); err != nil {
+		t.Fatalf("ReadResource before Resources failed: %v", err)
+	}
+	if len(docs.called) != 1 || docs.called[0] != "file:///README.md" {
+		t.Errorf("Unexpected routing: %v", docs.called)
+	}
+}
+
+func TestPoolRemove(t *testing.T) {
+	server := &fakeServer{tools: []string{"run"}}
+	pool := NewPool()
+	pool.Add("one", server.client())
+
+	if pool.Client("one") == nil {
+		t.Fatal("Expected the client to be registered")
+	}
+	if !pool.Remove("one") || pool.Remove("one") {
+		t.Error("Remove should report whether the server was found")
+	}
+	if pool.Client("one") != nil {
+		t.Error("Removed server should no longer be available")
+	}
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// RequestHandler answers a request sent by the server to the client.
+// Params are the decoded JSON params of the request; the returned value is
+// sent back as the result.
+type RequestHandler func(ctx context.Context, params interface{}) (interface{}, error)
+
+// WithRequestHandler registers the handler for requests the server sends with
+// method. The transport must be able to deliver server requests; see
+// transport.RequestReceiver. Registering a handler for "ping" replaces the
+// built-in one.
+func WithRequestHandler(method string, handler RequestHandler) Option {
+	return func(c *Config) {
+		if c.RequestHandlers == nil {
+			c.RequestHandlers = make(map[string]RequestHandler)
+		}
+		c.RequestHandlers[method] = handler
+	}
+}
+
+// handleRequest passes a request received from the server through the
+// interceptor chain to the registered request handler
+func (c *Client) handleRequest(method string, params interface{}) (interface{}, error) {
+	call := &Call{
+		Direction: Incoming,
+		Method:    method,
+		Params:    params,
+	}
+
+	err := c.chain(c.ctx, call, c.dispatchRequest)
+	if err != nil {
+		return nil, err
+	}
+	return call.Result, nil
+}
+
+// dispatchRequest invokes the handler registered for the request's method
+func (c *Client) dispatchRequest(ctx context.Context, call *Call) error {
+	handler, ok := c.config.RequestHandlers[call.Method]
+	if !ok {
+		if call.Method != "ping" {
+			return fmt.Errorf("method not found: %s", call.Method)
+		}
+		handler = func(ctx context.Context, params interface{}) (interface{}, error) {
+			return struct{}{}, nil
+		}
+	}
+
+	result, err := handler(ctx, call.Params)
+	if err != nil {
+		return err
+	}
+	call.Result = result
+	return nil
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Convict3d/mcp-go/transport"
+	"github.com/Convict3d/mcp-go/types"
+	"github.com/ybbus/jsonrpc/v3"
+)
+
+// RetryPolicy controls how failed requests are retried.
+// Only idempotent operations are retried: list, read and get requests, ping,
+// and tools/call for tools annotated with readOnlyHint or idempotentHint, or
+// for calls made with a context returned by AllowRetry.
+type RetryPolicy struct {
+	MaxAttempts    int           // Total attempts including the first, defaults to 3
+	InitialBackoff time.Duration // Delay before the first retry, defaults to 100ms
+	MaxBackoff     time.Duration // Upper bound for the delay, defaults to 5s
+	Multiplier     float64       // Growth factor of the delay between retries, defaults to 2
+	Jitter         float64       // Random variation as a fraction of the delay (0 to 1), defaults to 0.2
+
+	// Retryable reports whether an error is transient. Defaults to IsRetryable.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy returns a policy with three attempts and exponential
+// backoff starting at 100ms
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		Retryable:      IsRetryable,
+	}
+}
+
+// WithRetryPolicy enables retries of failed idempotent requests.
+// Zero fields of policy are filled in from DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Config) {
+		defaults := DefaultRetryPolicy()
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = defaults.MaxAttempts
+		}
+		if policy.InitialBackoff <= 0 {
+			policy.InitialBackoff = defaults.InitialBackoff
+		}
+		if policy.MaxBackoff <= 0 {
+			policy.MaxBackoff = defaults.MaxBackoff
+		}
+		if policy.Multiplier < 1 {
+			policy.Multiplier = defaults.Multiplier
+		}
+		if policy.Jitter < 0 || policy.Jitter > 1 {
+			policy.Jitter = defaults.Jitter
+		}
+		if policy.Retryable == nil {
+			policy.Retryable = defaults.Retryable
+		}
+		c.RetryPolicy = &policy
+	}
+}
+
+// retryKey is the context key set by AllowRetry
+type retryKey struct{}
+
+// AllowRetry returns a context that marks calls made with it as safe to retry
+// under the client's retry policy, including tools/call for tools without
+// idempotency annotations
+func AllowRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryKey{}, true)
+}
+
+// IsRetryable reports whether err is a transient failure worth retrying:
+// timeouts, connection resets and refusals, broken pipes, unexpected EOFs and
+// HTTP 429 and 5xx responses. Context cancellation and JSON-RPC errors
+// returned by the server are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var httpErr *jsonrpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code == 429 || httpErr.Code >= 500
+	}
+
+	if errors.Is(err, transport.ErrTimeout) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// backoff returns the delay before retry number n, counting from 1
+func (p *RetryPolicy) backoff(n int) time.Duration {
+	delay := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(n-1))
+	if delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay *= 1 - p.Jitter + 2*p.Jitter*rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// withRetry runs attempt until it succeeds, fails with an error that cannot be
+// retried, or the policy's attempts are used up
+func (c *Client) withRetry(ctx context.Context, method string, params interface{}, attempt func() error) error {
+	policy := c.config.RetryPolicy
+	if policy == nil {
+		return attempt()
+	}
+
+	for n := 1; ; n++ {
+		err := attempt()
+		if err == nil || n >= policy.MaxAttempts || !policy.Retryable(err) || !c.isIdempotent(ctx, method, params) {
+			return err
+		}
+
+		timer := time.NewTimer(policy.backoff(n))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+	}
+}
+
+// isIdempotent reports whether a request may be sent again after a failure
+func (c *Client) isIdempotent(ctx context.Context, method string, params interface{}) bool {
+	if allowed, _ := ctx.Value(retryKey{}).(bool); allowed {
+		return true
+	}
+
+	switch {
+	case method == "ping",
+		strings.HasSuffix(method, "/list"),
+		strings.HasSuffix(method, "/read"),
+		strings.HasSuffix(method, "/get"):
+		return true
+	case method == "tools/call":
+		call, ok := params.(*types.CallToolParams)
+		if !ok {
+			return false
+		}
+		tool := c.cachedTool(call.Name)
+		return tool != nil && tool.Annotations != nil &&
+			(tool.Annotations.ReadOnlyHint || tool.Annotations.IdempotentHint)
+	default:
+		return false
+	}
+}
+
+// cachedTool returns a tool definition that is already cached, without
+// listing tools from the server
+func (c *Client) cachedTool(name string) *types.Tool {
+	if c.catalog != nil {
+		tool, ok := c.catalog.tools.peek(name)
+		if !ok {
+			return nil
+		}
+		return &tool
+	}
+
+	c.toolsMu.RLock()
+	defer c.toolsMu.RUnlock()
+	tool, ok := c.tools[name]
+	if !ok {
+		return nil
+	}
+	return &tool
+}
+
+// Ping checks that the server is responsive
+func (c *Client) Ping(ctx context.Context) error {
+	var result struct{}
+	return c.call(ctx, &result, "ping", nil)
+}
@@ -0,0 +1,221 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/Convict3d/mcp-go/transport"
+	"github.com/Convict3d/mcp-go/types"
+	"github.com/ybbus/jsonrpc/v3"
+)
+
+// fastRetry is a retry policy with negligible backoff for tests
+var fastRetry = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+// flakyTransport fails each method with err for the first failures calls
+func flakyTransport(failures int, err error, calls map[string]int) *MockTransport {
+	return &MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			calls[method]++
+			if calls[method] <= failures {
+				return err
+			}
+			if method == "tools/list" {
+				return json.Unmarshal([]byte(`{"tools": [
+					{"name": "lookup", "inputSchema": {"type": "object"}, "annotations": {"readOnlyHint": true}},
+					{"name": "send", "inputSchema": {"type": "object"}}
+				]}`), result)
+			}
+			return nil
+		},
+	}
+}
+
+func TestRetryListOperations(t *testing.T) {
+	calls := make(map[string]int)
+	reset := fmt.Errorf("read: %w", syscall.ECONNRESET)
+	client := NewClient(WithTransport(flakyTransport(2, reset, calls)), WithRetryPolicy(fastRetry))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	tools, err := client.ListTools()
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(tools) != 2 || calls["tools/list"] != 3 {
+		t.Errorf("Expected success on the third attempt, got %d tools after %d calls", len(tools), calls["tools/list"])
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	calls := make(map[string]int)
+	client := NewClient(WithTransport(flakyTransport(10, transport.ErrTimeout, calls)), WithRetryPolicy(fastRetry))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Resources: &types.ResourcesCapability{}}
+
+	_, err := client.ReadResource("file:///a.txt")
+	if !errors.Is(err, transport.ErrTimeout) {
+		t.Errorf("Expected the last error to be returned, got %v", err)
+	}
+	if calls["resources/read"] != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls["resources/read"])
+	}
+}
+
+func TestRetrySkipsPermanentErrors(t *testing.T) {
+	calls := make(map[string]int)
+	rpcErr := &jsonrpc.RPCError{Code: -32602, Message: "invalid params"}
+	client := NewClient(WithTransport(flakyTransport(1, rpcErr, calls)), WithRetryPolicy(fastRetry))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Prompts: &types.PromptsCapability{}}
+
+	if _, err := client.GetPrompt("greet", nil); err == nil {
+		t.Error("Expected the server error to be returned")
+	}
+	if calls["prompts/get"] != 1 {
+		t.Errorf("Expected no retry for a JSON-RPC error, got %d calls", calls["prompts/get"])
+	}
+}
+
+func TestRetryToolCalls(t *testing.T) {
+	reset := fmt.Errorf("write: %w", syscall.EPIPE)
+
+	newClient := func(calls map[string]int) *Client {
+		client := NewClient(WithTransport(flakyTransport(1, reset, calls)), WithRetryPolicy(fastRetry))
+		client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+		return client
+	}
+
+	t.Run("not idempotent", func(t *testing.T) {
+		calls := make(map[string]int)
+		client := newClient(calls)
+		defer client.Close()
+
+		if _, err := client.CallTool("send", nil); err == nil {
+			t.Error("Expected the failure to be returned")
+		}
+		if calls["tools/call"] != 1 {
+			t.Errorf("Expected no retry, got %d calls", calls["tools/call"])
+		}
+	})
+
+	t.Run("read only annotation", func(t *testing.T) {
+		calls := make(map[string]int)
+		client := newClient(calls)
+		defer client.Close()
+
+		if _, err := client.ListTools(); err != nil {
+			t.Fatalf("ListTools failed: %v", err)
+		}
+		if _, err := client.CallTool("lookup", nil); err != nil {
+			t.Errorf("Expected the read-only tool call to be retried, got %v", err)
+		}
+		if calls["tools/call"] != 2 {
+			t.Errorf("Expected 2 attempts, got %d", calls["tools/call"])
+		}
+	})
+
+	t.Run("caller opt in", func(t *testing.T) {
+		calls := make(map[string]int)
+		client := newClient(calls)
+		defer client.Close()
+
+		if _, err := client.CallToolContext(AllowRetry(context.Background()), "send", nil); err != nil {
+			t.Errorf("Expected the opted-in call to be retried, got %v", err)
+		}
+		if calls["tools/call"] != 2 {
+			t.Errorf("Expected 2 attempts, got %d", calls["tools/call"])
+		}
+	})
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	calls := make(map[string]int)
+	client := NewClient(
+		WithTransport(flakyTransport(10, io.ErrUnexpectedEOF, calls)),
+		WithRetryPolicy(RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour}),
+	)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := client.Ping(ctx); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected the attempt error, got %v", err)
+	}
+	if time.Since(start) > time.Second || calls["ping"] != 1 {
+		t.Errorf("Expected cancellation to stop retries, got %d calls", calls["ping"])
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"canceled", context.Canceled, false},
+		{"deadline", fmt.Errorf("call: %w", context.DeadlineExceeded), false},
+		{"transport timeout", transport.ErrTimeout, true},
+		{"connection reset", fmt.Errorf("read: %w", syscall.ECONNRESET), true},
+		{"connection refused", syscall.ECONNREFUSED, true},
+		{"broken pipe", fmt.Errorf("failed to send request: %w", syscall.EPIPE), true},
+		{"unexpected eof", io.ErrUnexpectedEOF, true},
+		{"http 503", &jsonrpc.HTTPError{Code: 503}, true},
+		{"http 429", &jsonrpc.HTTPError{Code: 429}, true},
+		{"http 400", &jsonrpc.HTTPError{Code: 400}, false},
+		{"rpc error", &jsonrpc.RPCError{Code: -32601, Message: "not found"}, false},
+		{"closed", transport.ErrClosed, false},
+	}
+
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.expected {
+			t.Errorf("%s: IsRetryable() = %v, expected %v", tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Multiplier: 2}
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for i, want := range expected {
+		if got := policy.backoff(i + 1); got != want {
+			t.Errorf("backoff(%d) = %v, expected %v", i+1, got, want)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := policy.backoff(1); got < 50*time.Millisecond || got > 150*time.Millisecond {
+			t.Fatalf("Jittered backoff %v outside the expected range", got)
+		}
+	}
+}
+
+func TestPing(t *testing.T) {
+	var gotMethod string
+	var gotParams int
+	client := NewClient(WithTransport(&MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			gotMethod, gotParams = method, len(params)
+			return nil
+		},
+	}))
+	defer client.Close()
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+	if gotMethod != "ping" || gotParams != 0 {
+		t.Errorf("Expected ping without params, got %q with %d params", gotMethod, gotParams)
+	}
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Convict3d/mcp-go/types"
+	"github.com/Convict3d/mcp-go/uritemplate"
+)
+
+// ListResourceTemplates retrieves all resource templates from the server, following pagination cursors
+func (c *Client) ListResourceTemplates() ([]types.ResourceTemplate, error) {
+	if !c.HasResources() {
+		return nil, nil
+	}
+
+	var templates []types.ResourceTemplate
+	var cursor *types.Cursor
+	for {
+		page, next, err := c.ListResourceTemplatesPage(c.ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, page...)
+
+		if next == nil || *next == "" {
+			return templates, nil
+		}
+		cursor = next
+	}
+}
+
+// ListResourceTemplatesPage retrieves a single page of resource templates.
+// Pass a nil cursor for the first page; the returned cursor is nil on the last page.
+func (c *Client) ListResourceTemplatesPage(ctx context.Context, cursor *types.Cursor) ([]types.ResourceTemplate, *types.Cursor, error) {
+	if !c.HasResources() {
+		return nil, nil, nil
+	}
+
+	params := struct {
+		Cursor *types.Cursor `json:"cursor,omitempty"`
+	}{
+		Cursor: cursor,
+	}
+
+	var result types.ListResourceTemplatesResult
+	err := c.call(ctx, &result, "resources/templates/list", params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return result.ResourceTemplates, result.NextCursor, nil
+}
+
+// ReadResourceTemplate expands an RFC 6570 URI template with vars and reads the resulting resource
+func (c *Client) ReadResourceTemplate(ctx context.Context, uriTemplate string, vars map[string]interface{}) (*types.ReadResourceResult, error) {
+	uri, err := uritemplate.Expand(uriTemplate, vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand resource template: %w", err)
+	}
+
+	return c.ReadResourceContext(ctx, uri)
+}
+
+// MatchResourceTemplate finds the first template whose URI template matches uri
+// and returns it together with the extracted variables.
+// Templates that fail to parse are skipped.
+func MatchResourceTemplate(templates []types.ResourceTemplate, uri string) (*types.ResourceTemplate, map[string]string, bool) {
+	for i := range templates {
+		tmpl, err := uritemplate.Parse(templates[i].URITemplate)
+		if err != nil {
+			continue
+		}
+		if vars, ok := tmpl.Match(uri); ok {
+			return &templates[i], vars, true
+		}
+	}
+	return nil, nil, false
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+func TestListResourceTemplatesPagination(t *testing.T) {
+	pages := map[string]string{
+		"":      `{"resourceTemplates": [{"name": "file", "uriTemplate": "file:///{+path}"}], "nextCursor": "page2"}`,
+		"page2": `{"resourceTemplates": [{"name": "weather", "uriTemplate": "weather://{city}/forecast{?days}"}]}`,
+	}
+
+	var cursors []string
+	client := NewClient(WithTransport(&MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			if method != "resources/templates/list" {
+				t.Fatalf("Unexpected method %q", method)
+			}
+
+			data, _ := json.Marshal(params[0])
+			var decoded struct {
+				Cursor string `json:"cursor"`
+			}
+			_ = json.Unmarshal(data, &decoded)
+			cursors = append(cursors, decoded.Cursor)
+
+			return json.Unmarshal([]byte(pages[decoded.Cursor]), result)
+		},
+	}))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Resources: &types.ResourcesCapability{}}
+
+	templates, err := client.ListResourceTemplates()
+	if err != nil {
+		t.Fatalf("ListResourceTemplates failed: %v", err)
+	}
+
+	if len(templates) != 2 || templates[0].Name != "file" || templates[1].Name != "weather" {
+		t.Errorf("Unexpected templates: %+v", templates)
+	}
+	if len(cursors) != 2 || cursors[0] != "" || cursors[1] != "page2" {
+		t.Errorf("Unexpected cursors sent: %v", cursors)
+	}
+}
+
+func TestListResourceTemplatesWithoutCapability(t *testing.T) {
+	client := NewClient(WithTransport(&MockTransport{}))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{}
+
+	templates, err := client.ListResourceTemplates()
+	if err != nil || templates != nil {
+		t.Errorf("Expected nil templates and error, got %v, %v", templates, err)
+	}
+}
+
+func TestReadResourceTemplate(t *testing.T) {
+	var gotURI string
+	client := NewClient(WithTransport(&MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			data, _ := json.Marshal(params[0])
+			var decoded struct {
+				URI string `json:"uri"`
+			}
+			_ = json.Unmarshal(data, &decoded)
+			gotURI = decoded.URI
+			return nil
+		},
+	}))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Resources: &types.ResourcesCapability{}}
+
+	_, err := client.ReadResourceTemplate(context.Background(), "weather://{city}/forecast{?days,units}",
+		map[string]interface{}{"city": "New York", "days": 3})
+	if err != nil {
+		t.Fatalf("ReadResourceTemplate failed: %v", err)
+	}
+
+	if gotURI != "weather://New%20York/forecast?days=3" {
+		t.Errorf("Unexpected URI read: %q", gotURI)
+	}
+
+	if _, err := client.ReadResourceTemplate(context.Background(), "{unclosed", nil); err == nil {
+		t.Error("Expected error for an invalid template")
+	}
+}
+
+func TestMatchResourceTemplate(t *testing.T) {
+	templates := []types.ResourceTemplate{
+		{BaseMetadata: types.BaseMetadata{Name: "broken"}, URITemplate: "{unclosed"},
+		{BaseMetadata: types.BaseMetadata{Name: "file"}, URITemplate: "file:///{+path}"},
+		{BaseMetadata: types.BaseMetadata{Name: "weather"}, URITemplate: "weather://{city}/forecast{?days}"},
+	}
+
+	tmpl, vars, ok := MatchResourceTemplate(templates, "weather://Paris/forecast?days=2")
+	if !ok {
+		t.Fatal("Expected a matching template")
+	}
+	if tmpl.Name != "weather" || vars["city"] != "Paris" || vars["days"] != "2" {
+		t.Errorf("Unexpected match: %s %v", tmpl.Name, vars)
+	}
+
+	if _, _, ok := MatchResourceTemplate(templates, "db://users"); ok {
+		t.Error("Expected no template to match")
+	}
+}
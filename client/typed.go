@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// ToolError is returned by CallTyped when a tool reports an error result (isError: true)
+type ToolError struct {
+	Tool    string                // Name of the tool that failed
+	Content []types.TextContent   // Text content describing the failure
+	Result  *types.CallToolResult // Full result as returned by the server
+}
+
+// Error implements the error interface
+func (e *ToolError) Error() string {
+	if text := e.Text(); text != "" {
+		return fmt.Sprintf("tool %q failed: %s", e.Tool, text)
+	}
+	return fmt.Sprintf("tool %q failed", e.Tool)
+}
+
+// Text returns the text content of the error result joined by newlines
+func (e *ToolError) Text() string {
+	texts := make([]string, 0, len(e.Content))
+	for _, tc := range e.Content {
+		texts = append(texts, tc.Text)
+	}
+	return strings.Join(texts, "\n")
+}
+
+// CallTyped calls a tool with a Go value as arguments and decodes the result into Out.
+//
+// The input is marshaled to JSON and must encode to a JSON object (or null for
+// no arguments). The output is decoded from the result's structuredContent,
+// falling back to the first text content block that holds valid JSON for Out.
+// Results flagged with isError are returned as a *ToolError.
+func CallTyped[In, Out any](ctx context.Context, c *Client, name string, in In) (Out, error) {
+	var out Out
+
+	result, err := CallTypedRaw(ctx, c, name, in)
+	if err != nil {
+		return out, err
+	}
+
+	return decodeToolOutput[Out](name, result)
+}
+
+// CallTypedRaw calls a tool with a Go value as arguments and returns the raw result.
+// Use it for tools whose output is not JSON; CallTyped builds on it.
+// Results flagged with isError are returned as a *ToolError.
+func CallTypedRaw[In any](ctx context.Context, c *Client, name string, in In) (*types.CallToolResult, error) {
+	arguments, err := toArguments(in)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode arguments for tool %q: %w", name, err)
+	}
+
+	result, err := c.CallToolContext(ctx, name, arguments)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, fmt.Errorf("server does not support tools")
+	}
+
+	if result.IsError {
+		return nil, &ToolError{
+			Tool:    name,
+			Content: result.GetTextContent(),
+			Result:  result,
+		}
+	}
+
+	return result, nil
+}
+
+// toArguments converts a Go value into a tools/call arguments map
+func toArguments(in interface{}) (map[string]interface{}, error) {
+	if args, ok := in.(map[string]interface{}); ok {
+		return args, nil
+	}
+
+	data, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var arguments map[string]interface{}
+	if err := json.Unmarshal(data, &arguments); err != nil {
+		return nil, fmt.Errorf("arguments must encode to a JSON object, got %s", data)
+	}
+	return arguments, nil
+}
+
+// decodeToolOutput decodes structured content, or a JSON text block as a fallback, into Out
+func decodeToolOutput[Out any](name string, result *types.CallToolResult) (Out, error) {
+	if result.StructuredContent != nil {
+		out, err := types.DecodeStructuredContent[Out](result)
+		if err != nil {
+			return out, fmt.Errorf("tool %q: %w", name, err)
+		}
+		return out, nil
+	}
+
+	var out Out
+	for _, text := range result.GetTextStrings() {
+		var candidate Out
+		if err := json.Unmarshal([]byte(text), &candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return out, fmt.Errorf("tool %q returned no JSON output: %w", name, types.ErrNoStructuredContent)
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+type weatherInput struct {
+	City  string `json:"city"`
+	Units string `json:"units,omitempty"`
+}
+
+type weatherOutput struct {
+	Temperature float64 `json:"temperature"`
+	Conditions  string  `json:"conditions"`
+}
+
+// newTypedTransport returns a mock that records tools/call arguments and replies with result
+func newTypedTransport(result types.CallToolResult, gotArgs *map[string]interface{}) *MockTransport {
+	return &MockTransport{
+		callFunc: func(ctx context.Context, res interface{}, method string, params ...interface{}) error {
+			if method != "tools/call" {
+				return nil
+			}
+			if gotArgs != nil {
+				args, err := toArguments(params[0])
+				if err != nil {
+					return err
+				}
+				*gotArgs, _ = args["arguments"].(map[string]interface{})
+			}
+			*res.(*types.CallToolResult) = result
+			return nil
+		},
+	}
+}
+
+func TestCallTypedStructuredContent(t *testing.T) {
+	var gotArgs map[string]interface{}
+	mockTransport := newTypedTransport(types.CallToolResult{
+		StructuredContent: map[string]interface{}{"temperature": 18.5, "conditions": "cloudy"},
+	}, &gotArgs)
+
+	client := NewClient(WithTransport(mockTransport))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	out, err := CallTyped[weatherInput, weatherOutput](context.Background(), client, "get_weather", weatherInput{City: "Berlin"})
+	if err != nil {
+		t.Fatalf("CallTyped failed: %v", err)
+	}
+
+	if out.Temperature != 18.5 || out.Conditions != "cloudy" {
+		t.Errorf("Unexpected output: %+v", out)
+	}
+
+	if gotArgs["city"] != "Berlin" {
+		t.Errorf("Expected city argument 'Berlin', got %v", gotArgs["city"])
+	}
+	if _, ok := gotArgs["units"]; ok {
+		t.Error("Expected omitempty field to be left out of the arguments")
+	}
+}
+
+func TestCallTypedTextFallback(t *testing.T) {
+	mockTransport := newTypedTransport(types.CallToolResult{
+		Content: []interface{}{
+			map[string]interface{}{"type": "text", "text": "Here is the weather:"},
+			map[string]interface{}{"type": "text", "text": `{"temperature": 30, "conditions": "sunny"}`},
+		},
+	}, nil)
+
+	client := NewClient(WithTransport(mockTransport))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	out, err := CallTyped[weatherInput, weatherOutput](context.Background(), client, "get_weather", weatherInput{City: "Rome"})
+	if err != nil {
+		t.Fatalf("CallTyped failed: %v", err)
+	}
+
+	if out.Temperature != 30 || out.Conditions != "sunny" {
+		t.Errorf("Unexpected output: %+v", out)
+	}
+}
+
+func TestCallTypedNoJSONOutput(t *testing.T) {
+	mockTransport := newTypedTransport(types.CallToolResult{
+		Content: []interface{}{
+			map[string]interface{}{"type": "text", "text": "sunny"},
+		},
+	}, nil)
+
+	client := NewClient(WithTransport(mockTransport))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	_, err := CallTyped[weatherInput, weatherOutput](context.Background(), client, "get_weather", weatherInput{})
+	if !errors.Is(err, types.ErrNoStructuredContent) {
+		t.Errorf("Expected ErrNoStructuredContent, got %v", err)
+	}
+}
+
+func TestCallTypedToolError(t *testing.T) {
+	mockTransport := newTypedTransport(types.CallToolResult{
+		IsError: true,
+		Content: []interface{}{
+			map[string]interface{}{"type": "text", "text": "unknown city"},
+		},
+	}, nil)
+
+	client := NewClient(WithTransport(mockTransport))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	_, err := CallTyped[weatherInput, weatherOutput](context.Background(), client, "get_weather", weatherInput{City: "Atlantis"})
+
+	var toolErr *ToolError
+	if !errors.As(err, &toolErr) {
+		t.Fatalf("Expected *ToolError, got %T: %v", err, err)
+	}
+
+	if toolErr.Tool != "get_weather" {
+		t.Errorf("Expected tool name 'get_weather', got %q", toolErr.Tool)
+	}
+	if toolErr.Text() != "unknown city" {
+		t.Errorf("Expected error text 'unknown city', got %q", toolErr.Text())
+	}
+	if toolErr.Error() != `tool "get_weather" failed: unknown city` {
+		t.Errorf("Unexpected error message: %s", toolErr.Error())
+	}
+}
+
+func TestCallTypedInvalidArguments(t *testing.T) {
+	client := NewClient(WithTransport(newTypedTransport(types.CallToolResult{}, nil)))
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	_, err := CallTyped[[]string, weatherOutput](context.Background(), client, "get_weather", []string{"Paris"})
+	if err == nil {
+		t.Fatal("Expected error for non-object arguments")
+	}
+}
+
+func TestCallTypedWithoutToolsCapability(t *testing.T) {
+	client := NewClient(WithTransport(&MockTransport{}))
+	defer client.Close()
+
+	_, err := CallTyped[weatherInput, weatherOutput](context.Background(), client, "get_weather", weatherInput{})
+	if err == nil {
+		t.Fatal("Expected error when the server does not support tools")
+	}
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Convict3d/mcp-go/schema"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// cacheTools replaces the cached tool definitions with the given list
+func (c *Client) cacheTools(tools []types.Tool) {
+	cache := make(map[string]types.Tool, len(tools))
+	for _, tool := range tools {
+		cache[tool.Name] = tool
+	}
+
+	c.toolsMu.Lock()
+	c.tools = cache
+	c.toolsMu.Unlock()
+}
+
+// loadTools lists every page of tools with ctx and caches the complete list
+func (c *Client) loadTools(ctx context.Context) error {
+	if !c.HasTools() {
+		return nil
+	}
+
+	var tools []types.Tool
+	var cursor *types.Cursor
+	for {
+		params := struct {
+			Cursor *types.Cursor `json:"cursor,omitempty"`
+		}{
+			Cursor: cursor,
+		}
+
+		var result types.ListToolsResult
+		if err := c.call(ctx, &result, "tools/list", params); err != nil {
+			return err
+		}
+		tools = append(tools, result.Tools...)
+
+		if result.NextCursor == nil || *result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+
+	c.cacheTools(tools)
+	return nil
+}
+
+// lookupTool returns the cached definition of a tool, listing every page of
+// tools with ctx first if the cache has not been populated yet. It returns nil
+// for unknown tools. When the catalog is enabled it is used as the source of
+// tool definitions.
+func (c *Client) lookupTool(ctx context.Context, name string) (*types.Tool, error) {
+	if c.catalog != nil {
+		tool, err := c.catalog.Tool(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		return tool, nil
+	}
+
+	c.toolsMu.RLock()
+	cache := c.tools
+	c.toolsMu.RUnlock()
+
+	if cache == nil {
+		if err := c.loadTools(ctx); err != nil {
+			return nil, fmt.Errorf("failed to list tools: %w", err)
+		}
+		c.toolsMu.RLock()
+		cache = c.tools
+		c.toolsMu.RUnlock()
+	}
+
+	tool, ok := cache[name]
+	if !ok {
+		return nil, nil
+	}
+	return &tool, nil
+}
+
+// ValidateToolArguments checks arguments against the inputSchema of the named tool.
+// Tool definitions come from the cached tools/list result, which is fetched with
+// every page on first use. Unknown tools are not validated and are left for the
+// server to reject.
+// Violations are returned as schema.ValidationErrors wrapped with the tool name.
+func (c *Client) ValidateToolArguments(name string, arguments map[string]interface{}) error {
+	return c.validateToolArguments(c.ctx, name, arguments)
+}
+
+// validateToolArguments is ValidateToolArguments with the context of a call
+func (c *Client) validateToolArguments(ctx context.Context, name string, arguments map[string]interface{}) error {
+	tool, err := c.lookupTool(ctx, name)
+	if err != nil {
+		return err
+	}
+	if tool == nil {
+		return nil
+	}
+
+	// A missing arguments map is validated as an empty object
+	var value interface{} = arguments
+	if arguments == nil {
+		value = map[string]interface{}{}
+	}
+
+	if err := schema.Validate(tool.InputSchema, value); err != nil {
+		return fmt.Errorf("invalid arguments for tool %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// validateStructuredContent checks a tool result against the tool's outputSchema.
+// Error results and tools without an outputSchema are not validated.
+func (c *Client) validateStructuredContent(ctx context.Context, name string, result *types.CallToolResult) error {
+	if result.IsError {
+		return nil
+	}
+
+	tool, err := c.lookupTool(ctx, name)
+	if err != nil {
+		return err
+	}
+	if tool == nil || tool.OutputSchema == nil {
+		return nil
+	}
+
+	if result.StructuredContent == nil {
+		return fmt.Errorf("tool %q declares an outputSchema but returned no structured content", name)
+	}
+
+	if err := schema.Validate(tool.OutputSchema, result.StructuredContent); err != nil {
+		return fmt.Errorf("tool %q returned invalid structured content: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Convict3d/mcp-go/schema"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// newWeatherTransport returns a mock serving a single tool with an outputSchema
+func newWeatherTransport(structured map[string]interface{}, listCalls *int) *MockTransport {
+	weatherTool := types.Tool{
+		BaseMetadata: types.BaseMetadata{Name: "get_weather"},
+		InputSchema:  types.ToolInputSchema{Type: "object"},
+		OutputSchema: &types.ToolOutputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"temperature": map[string]interface{}{"type": "number"},
+				"conditions":  map[string]interface{}{"type": "string"},
+			},
+			Required: []string{"temperature", "conditions"},
+		},
+	}
+
+	return &MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			switch method {
+			case "tools/list":
+				if listCalls != nil {
+					*listCalls++
+				}
+				result.(*types.ListToolsResult).Tools = []types.Tool{weatherTool}
+			case "tools/call":
+				result.(*types.CallToolResult).StructuredContent = structured
+			}
+			return nil
+		},
+	}
+}
+
+func TestCallToolOutputValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		structured map[string]interface{}
+		wantErr    string
+	}{
+		{
+			name:       "valid structured content",
+			structured: map[string]interface{}{"temperature": 21.5, "conditions": "sunny"},
+		},
+		{
+			name:       "wrong field type",
+			structured: map[string]interface{}{"temperature": "warm", "conditions": "sunny"},
+			wantErr:    `field "temperature": expected number, got string`,
+		},
+		{
+			name:       "missing required field",
+			structured: map[string]interface{}{"temperature": 21.5},
+			wantErr:    `field "conditions": required field is missing`,
+		},
+		{
+			name:       "missing structured content",
+			structured: nil,
+			wantErr:    "returned no structured content",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(
+				WithTransport(newWeatherTransport(tt.structured, nil)),
+				WithOutputValidation(),
+			)
+			defer client.Close()
+
+			client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+			result, err := client.CallTool("get_weather", map[string]interface{}{"city": "Paris"})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("CallTool failed: %v", err)
+				}
+				if result == nil {
+					t.Fatal("CallTool returned nil result")
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Expected error containing %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestCallToolOutputValidationErrorsAreTyped(t *testing.T) {
+	client := NewClient(
+		WithTransport(newWeatherTransport(map[string]interface{}{"temperature": true, "conditions": "sunny"}, nil)),
+		WithOutputValidation(),
+	)
+	defer client.Close()
+
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	_, err := client.CallTool("get_weather", nil)
+
+	var verrs schema.ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("Expected schema.ValidationErrors, got %T: %v", err, err)
+	}
+	if len(verrs) != 1 || verrs[0].Path != "temperature" {
+		t.Errorf("Unexpected validation errors: %v", verrs)
+	}
+}
+
+func TestCallToolOutputValidationUsesCachedTools(t *testing.T) {
+	listCalls := 0
+	client := NewClient(
+		WithTransport(newWeatherTransport(map[string]interface{}{"temperature": 1.0, "conditions": "fog"}, &listCalls)),
+		WithOutputValidation(),
+	)
+	defer client.Close()
+
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.CallTool("get_weather", nil); err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+	}
+
+	if listCalls != 1 {
+		t.Errorf("Expected tools/list to be called once, got %d", listCalls)
+	}
+}
+
+func TestCallToolWithoutOutputValidation(t *testing.T) {
+	listCalls := 0
+	client := NewClient(WithTransport(newWeatherTransport(map[string]interface{}{"temperature": "warm"}, &listCalls)))
+	defer client.Close()
+
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	result, err := client.CallTool("get_weather", nil)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+
+	if result.StructuredContent["temperature"] != "warm" {
+		t.Errorf("Expected structured content to be returned unchanged, got %v", result.StructuredContent)
+	}
+
+	if listCalls != 0 {
+		t.Errorf("Expected no tools/list call without validation, got %d", listCalls)
+	}
+}
+
+func TestCallToolArgumentValidation(t *testing.T) {
+	calculator := types.Tool{
+		BaseMetadata: types.BaseMetadata{Name: "calculator"},
+		InputSchema: types.ToolInputSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"operation": map[string]interface{}{
+					"type": "string",
+					"enum": []interface{}{"add", "subtract", "multiply", "divide"},
+				},
+				"a": map[string]interface{}{"$ref": "#/$defs/operand"},
+				"b": map[string]interface{}{"$ref": "#/$defs/operand"},
+			},
+			Required: []string{"operation", "a", "b"},
+			Defs: map[string]interface{}{
+				"operand": map[string]interface{}{"type": "number", "minimum": -1000, "maximum": 1000},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		arguments map[string]interface{}
+		wantPaths []string
+	}{
+		{
+			name:      "valid arguments",
+			arguments: map[string]interface{}{"operation": "add", "a": 1, "b": 2},
+		},
+		{
+			name:      "missing and out of range",
+			arguments: map[string]interface{}{"operation": "add", "a": 5000},
+			wantPaths: []string{"b", "a"},
+		},
+		{
+			name:      "bad enum",
+			arguments: map[string]interface{}{"operation": "power", "a": 1, "b": 2},
+			wantPaths: []string{"operation"},
+		},
+		{
+			name:      "nil arguments",
+			arguments: nil,
+			wantPaths: []string{"operation", "a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			mockTransport := &MockTransport{
+				callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+					switch method {
+					case "tools/list":
+						result.(*types.ListToolsResult).Tools = []types.Tool{calculator}
+					case "tools/call":
+						calls++
+					}
+					return nil
+				},
+			}
+
+			client := NewClient(WithTransport(mockTransport), WithArgumentValidation())
+			defer client.Close()
+
+			client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+			_, err := client.CallTool("calculator", tt.arguments)
+			if len(tt.wantPaths) == 0 {
+				if err != nil {
+					t.Fatalf("CallTool failed: %v", err)
+				}
+				if calls != 1 {
+					t.Errorf("Expected tools/call to be sent once, got %d", calls)
+				}
+				return
+			}
+
+			if calls != 0 {
+				t.Error("Invalid arguments should not be sent to the server")
+			}
+
+			var verrs schema.ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("Expected schema.ValidationErrors, got %v", err)
+			}
+			if len(verrs) != len(tt.wantPaths) {
+				t.Fatalf("Expected %d errors, got %v", len(tt.wantPaths), err)
+			}
+			for i, path := range tt.wantPaths {
+				if verrs[i].Path != path {
+					t.Errorf("Error %d: expected path %q, got %q", i, path, verrs[i].Path)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateToolArgumentsUnknownTool(t *testing.T) {
+	mockTransport := &MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			return nil
+		},
+	}
+
+	client := NewClient(WithTransport(mockTransport))
+	defer client.Close()
+
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	if err := client.ValidateToolArguments("missing", map[string]interface{}{"x": 1}); err != nil {
+		t.Errorf("Expected unknown tools to be left to the server, got %v", err)
+	}
+}
+
+func TestCallToolValidationListsEveryPage(t *testing.T) {
+	type ctxKey struct{}
+	second := types.Cursor("page-2")
+	var cursors []string
+	mockTransport := &MockTransport{
+		callFunc: func(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+			switch method {
+			case "tools/list":
+				if ctx.Value(ctxKey{}) != "call" {
+					t.Errorf("tools/list did not get the context of the call")
+				}
+				var page types.PaginatedRequest
+				if len(params) > 0 {
+					data, _ := json.Marshal(params[0])
+					json.Unmarshal(data, &page)
+				}
+				var cursor string
+				if page.Cursor != nil {
+					cursor = string(*page.Cursor)
+				}
+				cursors = append(cursors, cursor)
+
+				list := result.(*types.ListToolsResult)
+				if cursor == "" {
+					list.Tools = []types.Tool{{BaseMetadata: types.BaseMetadata{Name: "first"}, InputSchema: types.ToolInputSchema{Type: "object"}}}
+					list.NextCursor = &second
+					return nil
+				}
+				list.Tools = []types.Tool{{
+					BaseMetadata: types.BaseMetadata{Name: "greet"},
+					InputSchema:  types.ToolInputSchema{Type: "object", Required: []string{"name"}},
+				}}
+			case "tools/call":
+				t.Error("Invalid arguments reached the server")
+			}
+			return nil
+		},
+	}
+
+	client := NewClient(WithTransport(mockTransport), WithArgumentValidation())
+	defer client.Close()
+	client.capabilities = &types.ServerCapabilities{Tools: &types.ToolsCapability{}}
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "call")
+	_, err := client.CallToolContext(ctx, "greet", map[string]interface{}{})
+	var verrs schema.ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("Expected validation errors for a tool on the second page, got %v", err)
+	}
+	if strings.Join(cursors, ",") != ",page-2" {
+		t.Errorf("Listed pages with cursors %q", cursors)
+	}
+}
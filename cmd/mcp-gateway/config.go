@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// Config is the gateway configuration file
+type Config struct {
+	// Separator placed between a backend's prefix and its tool and prompt names
+	Separator string `json:"separator,omitempty"`
+
+	// Timeout for connecting to each backend, as a Go duration string
+	Timeout string `json:"timeout,omitempty"`
+
+	Backends map[string]BackendConfig `json:"backends"`
+}
+
+// BackendConfig describes one backend server. Exactly one of Command and URL must be set.
+type BackendConfig struct {
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Prefix for tool and prompt names, defaults to the backend name.
+	// Set it to "-" to expose names unchanged.
+	Prefix string `json:"prefix,omitempty"`
+
+	// Allow and Deny filter tools by name with path.Match glob patterns.
+	// A tool is exposed if it matches Allow (or Allow is empty) and does not match Deny.
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// loadConfig reads and validates a configuration file
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(data)
+}
+
+// parseConfig decodes and validates a configuration
+func parseConfig(data []byte) (*Config, error) {
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	if len(config.Backends) == 0 {
+		return nil, fmt.Errorf("invalid config: no backends")
+	}
+	if config.Timeout != "" {
+		if _, err := time.ParseDuration(config.Timeout); err != nil {
+			return nil, fmt.Errorf("invalid config: timeout: %w", err)
+		}
+	}
+
+	for _, name := range config.backendNames() {
+		backend := config.Backends[name]
+		switch {
+		case backend.Command == "" && backend.URL == "":
+			return nil, fmt.Errorf("invalid config: backend %q needs a command or url", name)
+		case backend.Command != "" && backend.URL != "":
+			return nil, fmt.Errorf("invalid config: backend %q has both a command and a url", name)
+		}
+	}
+
+	return &config, nil
+}
+
+// backendNames returns the backend names in sorted order
+func (c *Config) backendNames() []string {
+	names := make([]string, 0, len(c.Backends))
+	for name := range c.Backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// timeout returns the configured connect timeout, defaulting to 30 seconds
+func (c *Config) timeout() time.Duration {
+	if d, err := time.ParseDuration(c.Timeout); err == nil && d > 0 {
+		return d
+	}
+	return 30 * time.Second
+}
+
+// prefix returns the name prefix of a backend
+func (b BackendConfig) prefix(name string) string {
+	switch b.Prefix {
+	case "":
+		return name
+	case "-":
+		return ""
+	default:
+		return b.Prefix
+	}
+}
@@ -0,0 +1,604 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Convict3d/mcp-go/client"
+	"github.com/Convict3d/mcp-go/server"
+	"github.com/Convict3d/mcp-go/transport"
+	"github.com/Convict3d/mcp-go/transport/http"
+	"github.com/Convict3d/mcp-go/transport/stdio"
+	"github.com/Convict3d/mcp-go/types"
+	"github.com/ybbus/jsonrpc/v3"
+)
+
+// gatewayVersion is reported as the server version during initialization
+const gatewayVersion = "1.0.0"
+
+// forwardedRequests lists the server requests that backends may send to the
+// upstream client
+var forwardedRequests = []string{"sampling/createMessage", "elicitation/create", "roots/list"}
+
+// Gateway exposes several backend servers as a single MCP server. The tools,
+// resources, resource templates and prompts of the backends are registered
+// with a server.Server, which serves the upstream clients, and kept in sync
+// as the backends' lists change.
+type Gateway struct {
+	pool    *client.Pool
+	server  *server.Server
+	logger  *log.Logger
+	filters map[string][]client.ToolPolicy // Allow and deny policies by backend name
+
+	mu        sync.Mutex
+	forwards  map[string][]*forward // Calls in flight to each backend, by backend name
+	relays    map[string][]*relay   // Requests of each backend relayed upstream, by backend name
+	nextToken int64                 // Source of the progress tokens sent to backends
+
+	// Entries registered with the server, by name or URI, guarded by syncMu
+	syncMu    sync.Mutex
+	tools     map[string]types.Tool
+	resources map[string]types.Resource
+	templates map[string]types.ResourceTemplate
+	prompts   map[string]types.Prompt
+}
+
+// forward is a call to a backend made on behalf of an upstream request
+type forward struct {
+	ctx   context.Context // Context of the upstream request
+	token string          // Progress token sent to the backend, if the upstream client asked for progress
+}
+
+// relay is a request from a backend relayed to an upstream client
+type relay struct {
+	cancel context.CancelCauseFunc
+}
+
+// newGateway creates a gateway without backends
+func newGateway(separator string, logger *log.Logger) *Gateway {
+	if separator == "" {
+		separator = client.DefaultPoolSeparator
+	}
+	g := &Gateway{
+		pool:     client.NewPool(client.WithPoolSeparator(separator)),
+		logger:   logger,
+		filters:  make(map[string][]client.ToolPolicy),
+		forwards: make(map[string][]*forward),
+		relays:   make(map[string][]*relay),
+	}
+	g.server = server.New("mcp-gateway", gatewayVersion,
+		server.WithLogger(slog.New(slog.NewTextHandler(logger.Writer(), nil))),
+		server.WithCapabilities(g.capabilities),
+	)
+	return g
+}
+
+// connect starts every configured backend. Backends that fail to start are
+// logged and skipped; an error is returned only if none could be started.
+func (g *Gateway) connect(config *Config) error {
+	connected := 0
+	for _, name := range config.backendNames() {
+		backend := config.Backends[name]
+		t, err := newBackendTransport(backend, config.timeout())
+		if err != nil {
+			g.logger.Printf("backend %s: %v", name, err)
+			continue
+		}
+		if err := g.addBackend(name, backend, t); err != nil {
+			t.Close()
+			g.logger.Printf("backend %s: %v", name, err)
+			continue
+		}
+		connected++
+	}
+
+	if connected == 0 {
+		return fmt.Errorf("no backend could be connected")
+	}
+	return nil
+}
+
+// newBackendTransport creates the transport for a backend
+func newBackendTransport(backend BackendConfig, timeout time.Duration) (transport.Transport, error) {
+	if backend.URL != "" {
+		opts := []http.Option{http.WithTimeout(timeout)}
+		for key, value := range backend.Headers {
+			opts = append(opts, http.WithHeader(key, value))
+		}
+		return http.NewHTTPTransport(backend.URL, opts...), nil
+	}
+
+	opts := []stdio.Option{stdio.WithTimeout(timeout)}
+	if len(backend.Env) > 0 {
+		env := os.Environ()
+		for key, value := range backend.Env {
+			env = append(env, key+"="+value)
+		}
+		opts = append(opts, stdio.WithEnv(env))
+	}
+	return stdio.NewTransport(backend.Command, backend.Args, opts...)
+}
+
+// addBackend initializes a client for the backend over t, adds it to the pool
+// and registers its tools, resources and prompts with the server
+func (g *Gateway) addBackend(name string, backend BackendConfig, t transport.Transport) error {
+	var filters []client.ToolPolicy
+	if len(backend.Allow) > 0 {
+		filters = append(filters, client.AllowTools(backend.Allow...))
+	}
+	if len(backend.Deny) > 0 {
+		filters = append(filters, client.DenyTools(backend.Deny...))
+	}
+
+	// Log messages of the backend go to the upstream clients at the level
+	// each of them set with logging/setLevel
+	logs := server.NewLogHandler(g.server,
+		server.WithLoggerName(name),
+		server.WithLogFallback(slog.NewTextHandler(g.logger.Writer(), nil)),
+	)
+
+	opts := []client.Option{
+		client.WithTransport(t),
+		client.WithClientInfo("mcp-gateway", gatewayVersion),
+		client.WithClientCapabilities(types.ClientCapabilities{
+			Sampling:    &types.SamplingCapability{},
+			Roots:       &types.RootsCapability{},
+			Elicitation: &types.ElicitationCapability{},
+		}),
+		client.WithInterceptors(g.trackForwards(name), g.routeNotifications(name)),
+		client.WithToolPolicy(filters...),
+		client.WithCatalog(),
+		client.WithLogSink(client.SlogSink(logs)),
+	}
+	for _, method := range forwardedRequests {
+		opts = append(opts, client.WithRequestHandler(method, g.forwardRequest(name, method)))
+	}
+
+	c := client.NewClient(opts...)
+	if err := c.Initialize(types.LatestProtocolVersion); err != nil {
+		return fmt.Errorf("initialize failed: %w", err)
+	}
+
+	// Clients choose their own levels, so the backend sends every message
+	if c.HasLogging() {
+		if err := c.SetLogLevel(context.Background(), types.LoggingLevelDebug); err != nil {
+			g.logger.Printf("backend %s: setting log level: %v", name, err)
+		}
+	}
+
+	g.mu.Lock()
+	g.filters[name] = filters
+	g.mu.Unlock()
+
+	if err := g.pool.Add(name, c, client.WithPrefix(backend.prefix(name))); err != nil {
+		return err
+	}
+
+	catalog := c.Catalog()
+	catalog.OnToolsChanged(func(client.ListChange[types.Tool]) { g.resync() })
+	catalog.OnResourcesChanged(func(client.ListChange[types.Resource]) { g.resync() })
+	catalog.OnResourceTemplatesChanged(func(client.ListChange[types.ResourceTemplate]) { g.resync() })
+	catalog.OnPromptsChanged(func(client.ListChange[types.Prompt]) { g.resync() })
+	return g.sync(context.Background())
+}
+
+// Close closes every backend
+func (g *Gateway) Close() error {
+	return g.pool.Close()
+}
+
+// toolAllowed reports whether a backend's filters expose the named tool
+func (g *Gateway) toolAllowed(ctx context.Context, backend, name string) bool {
+	g.mu.Lock()
+	filters := g.filters[backend]
+	g.mu.Unlock()
+
+	for _, filter := range filters {
+		if filter(ctx, &client.ToolCall{Name: name}) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// capabilities returns the union of the capabilities of the backends,
+// limited to the features the gateway forwards. Subscriptions and
+// completions are not forwarded.
+func (g *Gateway) capabilities() types.ServerCapabilities {
+	var caps types.ServerCapabilities
+	for _, health := range g.pool.Health() {
+		backend := g.pool.Client(health.Name).GetCapabilities()
+		if backend == nil {
+			continue
+		}
+		if backend.Tools != nil {
+			if caps.Tools == nil {
+				caps.Tools = &types.ToolsCapability{}
+			}
+			caps.Tools.ListChanged = caps.Tools.ListChanged || backend.Tools.ListChanged
+		}
+		if backend.Resources != nil {
+			if caps.Resources == nil {
+				caps.Resources = &types.ResourcesCapability{}
+			}
+			caps.Resources.ListChanged = caps.Resources.ListChanged || backend.Resources.ListChanged
+		}
+		if backend.Prompts != nil {
+			if caps.Prompts == nil {
+				caps.Prompts = &types.PromptsCapability{}
+			}
+			caps.Prompts.ListChanged = caps.Prompts.ListChanged || backend.Prompts.ListChanged
+		}
+		if backend.Logging != nil {
+			caps.Logging = &types.LoggingCapability{}
+		}
+	}
+	return caps
+}
+
+// resync registers the current lists of the backends after one of them changed
+func (g *Gateway) resync() {
+	if err := g.sync(context.Background()); err != nil {
+		g.logger.Printf("updating the lists of the backends: %v", err)
+	}
+}
+
+// sync registers the tools that pass the backends' filters, the resources,
+// the resource templates and the prompts of every backend with the server,
+// and removes the entries the backends no longer offer. The server tells
+// upstream clients that a list changed.
+func (g *Gateway) sync(ctx context.Context) error {
+	g.syncMu.Lock()
+	defer g.syncMu.Unlock()
+
+	poolTools, err := g.pool.Tools(ctx)
+	if err != nil {
+		return fmt.Errorf("listing tools: %w", err)
+	}
+	tools := make(map[string]types.Tool)
+	for _, tool := range poolTools {
+		if g.toolAllowed(ctx, tool.Server, tool.Original) {
+			tools[tool.Name] = tool.Tool
+		}
+	}
+	mirror(g.tools, tools, func(tool types.Tool) {
+		g.server.AddTool(tool, g.callTool)
+	}, g.server.RemoveTools)
+	g.tools = tools
+
+	poolResources, err := g.pool.Resources(ctx)
+	if err != nil {
+		return fmt.Errorf("listing resources: %w", err)
+	}
+	resources := make(map[string]types.Resource)
+	for _, resource := range poolResources {
+		resources[resource.URI] = resource.Resource
+	}
+	mirror(g.resources, resources, func(resource types.Resource) {
+		g.server.AddResource(resource, g.readResource)
+	}, g.server.RemoveResources)
+	g.resources = resources
+
+	templates := make(map[string]types.ResourceTemplate)
+	for _, template := range g.listResourceTemplates(ctx) {
+		templates[template.URITemplate] = template
+	}
+	mirror(g.templates, templates, func(template types.ResourceTemplate) {
+		if err := g.server.AddResourceTemplate(template, g.readResource); err != nil {
+			g.logger.Printf("skipping resource template: %v", err)
+		}
+	}, g.server.RemoveResourceTemplates)
+	g.templates = templates
+
+	poolPrompts, err := g.pool.Prompts(ctx)
+	if err != nil {
+		return fmt.Errorf("listing prompts: %w", err)
+	}
+	prompts := make(map[string]types.Prompt)
+	for _, prompt := range poolPrompts {
+		prompts[prompt.Name] = prompt.Prompt
+	}
+	mirror(g.prompts, prompts, func(prompt types.Prompt) {
+		g.server.AddPrompt(prompt, g.getPrompt)
+	}, g.server.RemovePrompts)
+	g.prompts = prompts
+
+	return nil
+}
+
+// mirror registers the entries of current that are new or changed since
+// registered with add, and unregisters the ones that are gone with remove
+func mirror[T any](registered, current map[string]T, add func(T), remove func(keys ...string)) {
+	var stale []string
+	for key := range registered {
+		if _, ok := current[key]; !ok {
+			stale = append(stale, key)
+		}
+	}
+	if len(stale) > 0 {
+		remove(stale...)
+	}
+
+	for key, entry := range current {
+		if previous, ok := registered[key]; !ok || !reflect.DeepEqual(previous, entry) {
+			add(entry)
+		}
+	}
+}
+
+// listResourceTemplates returns the resource templates of every healthy backend
+func (g *Gateway) listResourceTemplates(ctx context.Context) []types.ResourceTemplate {
+	var result []types.ResourceTemplate
+	for _, health := range g.pool.Health() {
+		if !health.Healthy {
+			continue
+		}
+		templates, err := g.pool.Client(health.Name).Catalog().ResourceTemplates(ctx)
+		if err != nil {
+			g.logger.Printf("backend %s: listing resource templates: %v", health.Name, err)
+			continue
+		}
+		result = append(result, templates...)
+	}
+	return result
+}
+
+// callTool forwards a tool call to the backend offering the tool
+func (g *Gateway) callTool(ctx context.Context, req *server.ToolRequest) (*types.CallToolResult, error) {
+	result, err := g.pool.CallTool(ctx, req.Name, req.Arguments)
+	if err != nil {
+		return nil, backendError(err)
+	}
+	return result, nil
+}
+
+// readResource forwards a resource read to the backend offering the resource
+func (g *Gateway) readResource(ctx context.Context, req *server.ResourceRequest) (*types.ReadResourceResult, error) {
+	result, err := g.pool.ReadResource(ctx, req.URI)
+	if err != nil {
+		return nil, backendError(err)
+	}
+	return result, nil
+}
+
+// getPrompt forwards a prompt request to the backend offering the prompt
+func (g *Gateway) getPrompt(ctx context.Context, req *server.PromptRequest) (*types.GetPromptResult, error) {
+	result, err := g.pool.GetPrompt(ctx, req.Name, req.Arguments)
+	if err != nil {
+		return nil, backendError(err)
+	}
+	return result, nil
+}
+
+// trackForwards returns a backend interceptor that records the calls made to
+// the backend on behalf of upstream requests while they are in flight. Tool
+// calls of upstream requests that asked for progress carry a progress token of
+// the gateway, since tokens of different clients may collide.
+func (g *Gateway) trackForwards(backend string) client.Interceptor {
+	return func(ctx context.Context, call *client.Call, next client.Handler) error {
+		req := server.RequestFromContext(ctx)
+		if call.Direction != client.Outgoing || call.Notification || req == nil {
+			return next(ctx, call)
+		}
+
+		f := &forward{ctx: ctx}
+		g.mu.Lock()
+		if params, ok := call.Params.(*types.CallToolParams); ok && req.Meta["progressToken"] != nil {
+			g.nextToken++
+			f.token = fmt.Sprintf("gateway-%d", g.nextToken)
+
+			forwarded := *params
+			forwarded.Meta = types.Meta{}
+			for key, value := range params.Meta {
+				forwarded.Meta[key] = value
+			}
+			forwarded.Meta["progressToken"] = f.token
+			call.Params = &forwarded
+		}
+		g.forwards[backend] = append(g.forwards[backend], f)
+		g.mu.Unlock()
+
+		defer func() {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			forwards := g.forwards[backend]
+			for i, other := range forwards {
+				if other == f {
+					g.forwards[backend] = append(forwards[:i:i], forwards[i+1:]...)
+					break
+				}
+			}
+		}()
+		return next(ctx, call)
+	}
+}
+
+// origin returns the context of the upstream request that caused a request
+// from the backend. Backends send such requests while handling a call, so the
+// origin is the upstream request with a call in flight to the backend. The
+// origin is unknown when no call is in flight or when calls of several
+// upstream sessions are.
+func (g *Gateway) origin(backend string) (context.Context, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	forwards := g.forwards[backend]
+	if len(forwards) == 0 {
+		return nil, fmt.Errorf("no upstream request is waiting on backend %s", backend)
+	}
+	sess := server.SessionFromContext(forwards[0].ctx)
+	for _, f := range forwards[1:] {
+		if server.SessionFromContext(f.ctx) != sess {
+			return nil, fmt.Errorf("requests of several upstream clients are waiting on backend %s", backend)
+		}
+	}
+	return forwards[len(forwards)-1].ctx, nil
+}
+
+// routeNotifications returns a backend interceptor that relays progress and
+// cancellation notifications of the backend to the upstream client they
+// concern. Other notifications are not relayed: the server announces list
+// changes once the backend's lists are registered again, and log messages go
+// through the backend's log sink.
+func (g *Gateway) routeNotifications(backend string) client.Interceptor {
+	return func(ctx context.Context, call *client.Call, next client.Handler) error {
+		if call.Direction == client.Incoming && call.Notification {
+			switch call.Method {
+			case "notifications/progress":
+				g.relayProgress(backend, call.Params)
+			case "notifications/cancelled":
+				g.relayCancelled(backend, call.Params)
+			}
+		}
+		return next(ctx, call)
+	}
+}
+
+// relayProgress reports the progress of a backend call to the upstream
+// request it was made for, found by the gateway's progress token
+func (g *Gateway) relayProgress(backend string, params interface{}) {
+	var progress struct {
+		ProgressToken interface{} `json:"progressToken"`
+		Progress      float64     `json:"progress"`
+		Total         float64     `json:"total"`
+		Message       string      `json:"message"`
+	}
+	if err := decodeParams(params, &progress); err != nil {
+		g.logger.Printf("backend %s: progress: %v", backend, err)
+		return
+	}
+
+	var origin context.Context
+	g.mu.Lock()
+	for _, f := range g.forwards[backend] {
+		if f.token != "" && f.token == progress.ProgressToken {
+			origin = f.ctx
+			break
+		}
+	}
+	g.mu.Unlock()
+	if origin == nil {
+		return
+	}
+
+	req := server.RequestFromContext(origin)
+	if err := req.ReportProgress(origin, progress.Progress, progress.Total, progress.Message); err != nil {
+		g.logger.Printf("backend %s: relaying progress: %v", backend, err)
+	}
+}
+
+// relayCancelled cancels the relayed request a backend no longer waits for.
+// The server then tells the upstream client with the id the client knows.
+// Transports do not expose the ids of backend requests, so the request is
+// only known while it is the backend's single relayed request in flight.
+func (g *Gateway) relayCancelled(backend string, params interface{}) {
+	var cancelled struct {
+		Reason string `json:"reason"`
+	}
+	if err := decodeParams(params, &cancelled); err != nil {
+		g.logger.Printf("backend %s: cancellation: %v", backend, err)
+		return
+	}
+
+	g.mu.Lock()
+	relays := g.relays[backend]
+	g.mu.Unlock()
+	if len(relays) != 1 {
+		return
+	}
+
+	cause := errors.New("cancelled by the backend")
+	if cancelled.Reason != "" {
+		cause = fmt.Errorf("cancelled by the backend: %s", cancelled.Reason)
+	}
+	relays[0].cancel(cause)
+}
+
+// forwardRequest returns a request handler for the backend that relays the
+// request to the upstream client whose request caused it and returns its
+// result. Requests whose origin is unknown are rejected.
+func (g *Gateway) forwardRequest(backend, method string) client.RequestHandler {
+	return func(_ context.Context, params interface{}) (interface{}, error) {
+		// The request goes out with the context of the upstream request, so
+		// that it is cancelled with it and sent on the stream of that request
+		origin, err := g.origin(backend)
+		if err != nil {
+			return nil, err
+		}
+		sess := server.SessionFromContext(origin)
+
+		ctx, cancel := context.WithCancelCause(origin)
+		r := &relay{cancel: cancel}
+		g.mu.Lock()
+		g.relays[backend] = append(g.relays[backend], r)
+		g.mu.Unlock()
+
+		defer func() {
+			cancel(nil)
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			relays := g.relays[backend]
+			for i, other := range relays {
+				if other == r {
+					g.relays[backend] = append(relays[:i:i], relays[i+1:]...)
+					break
+				}
+			}
+		}()
+
+		switch method {
+		case "sampling/createMessage":
+			var req types.CreateMessageRequest
+			if err := decodeParams(params, &req.Params); err != nil {
+				return nil, err
+			}
+			return sess.CreateMessage(ctx, &req)
+		case "elicitation/create":
+			var req types.ElicitRequest
+			if err := decodeParams(params, &req.Params); err != nil {
+				return nil, err
+			}
+			return sess.Elicit(ctx, &req)
+		default:
+			return sess.ListRoots(ctx)
+		}
+	}
+}
+
+// decodeParams converts the decoded JSON params of a backend request into v
+func decodeParams(params interface{}, v interface{}) error {
+	if params == nil {
+		return nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid params: %w", err)
+	}
+	return nil
+}
+
+// backendError converts an error from the pool into the error returned to the
+// upstream client, keeping the JSON-RPC errors returned by backends
+func backendError(err error) error {
+	var rpcErr *jsonrpc.RPCError
+	if errors.As(err, &rpcErr) {
+		return &server.Error{Code: rpcErr.Code, Message: rpcErr.Message, Data: rpcErr.Data}
+	}
+
+	var denied *client.ToolDeniedError
+	if errors.As(err, &denied) || errors.Is(err, client.ErrNotFound) {
+		return server.NewError(server.CodeInvalidParams, "%v", err)
+	}
+	return err
+}
@@ -0,0 +1,622 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Convict3d/mcp-go/client"
+	"github.com/Convict3d/mcp-go/server"
+	mcphttp "github.com/Convict3d/mcp-go/transport/http"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// fakeBackend is an in-process backend transport serving a fixed set of tools.
+// The "sample" tool asks the client for a completion with sampling/createMessage,
+// the "progress" tool reports progress for the call's progress token and the
+// "wait" tool returns once release is closed.
+type fakeBackend struct {
+	tools   []string
+	caps    *types.ServerCapabilities // Defaults to tools, resources and prompts
+	release chan struct{}
+
+	mu       sync.Mutex
+	called   []string
+	notify   func(method string, params interface{})
+	requests func(method string, params interface{}) (interface{}, error)
+}
+
+func (b *fakeBackend) Call(ctx context.Context, result interface{}, method string, params ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	names := b.tools
+	b.mu.Unlock()
+
+	var response interface{}
+	switch method {
+	case "initialize":
+		caps := types.ServerCapabilities{
+			Tools:     &types.ToolsCapability{},
+			Resources: &types.ResourcesCapability{},
+			Prompts:   &types.PromptsCapability{},
+		}
+		if b.caps != nil {
+			caps = *b.caps
+		}
+		response = types.InitializeResult{ProtocolVersion: types.LatestProtocolVersion, Capabilities: caps}
+	case "tools/list":
+		tools := []types.Tool{}
+		for _, name := range names {
+			tools = append(tools, types.Tool{BaseMetadata: types.BaseMetadata{Name: name}})
+		}
+		response = types.ListToolsResult{Tools: tools}
+	case "tools/call":
+		call := params[0].(*types.CallToolParams)
+		name := call.Name
+		b.mu.Lock()
+		b.called = append(b.called, name)
+		requests := b.requests
+		notify := b.notify
+		b.mu.Unlock()
+
+		text := "called " + name
+		if name == "progress" {
+			notify("notifications/progress", map[string]interface{}{"progressToken": call.Meta["progressToken"], "progress": 1, "total": 2})
+		}
+		if name == "wait" {
+			<-b.release
+		}
+		if name == "sample" {
+			reply, err := requests("sampling/createMessage", map[string]interface{}{"maxTokens": 10})
+			if err != nil {
+				return err
+			}
+			data, _ := json.Marshal(reply)
+			text = string(data)
+		}
+		response = types.CallToolResult{Content: []interface{}{types.TextContent{Type: "text", Text: text}}}
+	case "resources/templates/list":
+		templates := []types.ResourceTemplate{}
+		for _, name := range names {
+			templates = append(templates, types.ResourceTemplate{BaseMetadata: types.BaseMetadata{Name: name}, URITemplate: "test://" + name + "/{id}"})
+		}
+		response = types.ListResourceTemplatesResult{ResourceTemplates: templates}
+	default:
+		response = struct{}{}
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, result)
+}
+
+func (b *fakeBackend) CallRaw(ctx context.Context, method string, params interface{}) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := b.Call(ctx, &result, method, params)
+	return result, err
+}
+
+func (b *fakeBackend) GetSessionID() string { return "" }
+
+func (b *fakeBackend) Close() error { return nil }
+
+func (b *fakeBackend) SetNotificationHandler(handler func(method string, params interface{})) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.notify = handler
+}
+
+func (b *fakeBackend) SetRequestHandler(handler func(method string, params interface{}) (interface{}, error)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.requests = handler
+}
+
+func (b *fakeBackend) calls() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.called...)
+}
+
+// newTestGateway creates a gateway with a "github" backend and an "fs" backend
+// exposed with the prefix "files" that denies delete_* tools
+func newTestGateway(t *testing.T) (*Gateway, *fakeBackend, *fakeBackend) {
+	t.Helper()
+
+	github := &fakeBackend{tools: []string{"search", "sample"}}
+	files := &fakeBackend{tools: []string{"read", "delete_all"}}
+
+	g := newGateway("", log.New(io.Discard, "", 0))
+	if err := g.addBackend("github", BackendConfig{Command: "github"}, github); err != nil {
+		t.Fatalf("addBackend failed: %v", err)
+	}
+	fs := BackendConfig{Command: "fs", Prefix: "files", Deny: []string{"delete_*"}}
+	if err := g.addBackend("fs", fs, files); err != nil {
+		t.Fatalf("addBackend failed: %v", err)
+	}
+	t.Cleanup(func() { g.Close() })
+
+	return g, github, files
+}
+
+// response is a JSON-RPC response to the upstream client
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *server.Error   `json:"error"`
+}
+
+// newTestSession opens an upstream session on the gateway's server for a
+// client declaring sampling and completes the initialize handshake. Messages
+// the server sends on its own are passed to send.
+func newTestSession(t *testing.T, g *Gateway, send server.SendFunc) *server.Session {
+	t.Helper()
+	if send == nil {
+		send = func(context.Context, []byte) error { return nil }
+	}
+	sess := g.server.NewSession(send)
+	t.Cleanup(func() { sess.Close() })
+
+	response := request(t, sess, "initialize", map[string]interface{}{
+		"protocolVersion": types.LatestProtocolVersion,
+		"capabilities":    map[string]interface{}{"sampling": map[string]interface{}{}},
+	})
+	if response.Error != nil {
+		t.Fatalf("initialize failed: %v", response.Error)
+	}
+	sess.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	return sess
+}
+
+// request sends a request to the session and returns the response
+func request(t *testing.T, sess *server.Session, method string, params interface{}) *response {
+	t.Helper()
+
+	msg := map[string]interface{}{"jsonrpc": types.JSONRPCVersion, "id": 1, "method": method}
+	if params != nil {
+		msg["params"] = params
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Failed to encode request: %v", err)
+	}
+	reply := sess.HandleMessage(context.Background(), data)
+	if reply == nil {
+		t.Fatalf("No response to %s", method)
+	}
+	var resp response
+	if err := json.Unmarshal(reply, &resp); err != nil {
+		t.Fatalf("Invalid response %s: %v", reply, err)
+	}
+	return &resp
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := parseConfig([]byte(`{
+		"timeout": "5s",
+		"backends": {
+			"fs": {"command": "mcp-fs", "args": ["/data"], "prefix": "-"},
+			"github": {"url": "http://localhost:9000/mcp"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("parseConfig failed: %v", err)
+	}
+
+	if got := fmt.Sprint(config.backendNames()); got != "[fs github]" {
+		t.Errorf("backendNames = %s", got)
+	}
+	if config.timeout() != 5*time.Second {
+		t.Errorf("timeout = %v, expected 5s", config.timeout())
+	}
+	if prefix := config.Backends["fs"].prefix("fs"); prefix != "" {
+		t.Errorf("Expected no prefix for \"-\", got %q", prefix)
+	}
+	if prefix := config.Backends["github"].prefix("github"); prefix != "github" {
+		t.Errorf("Expected the backend name as prefix, got %q", prefix)
+	}
+
+	invalid := map[string]string{
+		"no backends":     `{"backends": {}}`,
+		"neither":         `{"backends": {"a": {}}}`,
+		"both":            `{"backends": {"a": {"command": "x", "url": "http://x"}}}`,
+		"invalid timeout": `{"timeout": "soon", "backends": {"a": {"command": "x"}}}`,
+		"invalid json":    `{"backends":`,
+	}
+	for name, data := range invalid {
+		if _, err := parseConfig([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestGatewayTools(t *testing.T) {
+	g, github, files := newTestGateway(t)
+	sess := newTestSession(t, g, nil)
+
+	response := request(t, sess, "tools/list", nil)
+	if response.Error != nil {
+		t.Fatalf("tools/list failed: %v", response.Error)
+	}
+	var list types.ListToolsResult
+	if err := json.Unmarshal(response.Result, &list); err != nil {
+		t.Fatalf("Failed to decode tools: %v", err)
+	}
+	var names []string
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	if got := fmt.Sprint(names); got != "[files__read github__sample github__search]" {
+		t.Errorf("tools/list = %s", got)
+	}
+
+	response = request(t, sess, "tools/call", types.CallToolParams{Name: "files__read"})
+	if response.Error != nil {
+		t.Fatalf("tools/call failed: %v", response.Error)
+	}
+	if got := fmt.Sprint(files.calls()); got != "[read]" {
+		t.Errorf("fs calls = %s", got)
+	}
+	if len(github.calls()) != 0 {
+		t.Errorf("Unexpected github calls: %v", github.calls())
+	}
+
+	for _, name := range []string{"files__delete_all", "files__missing"} {
+		response = request(t, sess, "tools/call", types.CallToolParams{Name: name})
+		if response.Error == nil || response.Error.Code != server.CodeInvalidParams {
+			t.Errorf("%s: expected an invalid params error, got %+v", name, response.Error)
+		}
+	}
+	if got := fmt.Sprint(files.calls()); got != "[read]" {
+		t.Errorf("Denied tool reached the backend: %s", got)
+	}
+
+	response = request(t, sess, "unknown/method", nil)
+	if response.Error == nil || response.Error.Code != server.CodeMethodNotFound {
+		t.Errorf("Expected method not found, got %+v", response.Error)
+	}
+}
+
+func TestGatewayListChanged(t *testing.T) {
+	g, github, _ := newTestGateway(t)
+
+	notifications := make(chan string, 10)
+	sess := newTestSession(t, g, func(ctx context.Context, msg []byte) error {
+		var decoded struct {
+			Method string `json:"method"`
+		}
+		json.Unmarshal(msg, &decoded)
+		notifications <- decoded.Method
+		return nil
+	})
+
+	// The gateway registers the backend's new list and tells its clients
+	github.mu.Lock()
+	github.tools = append(github.tools, "commit")
+	github.mu.Unlock()
+	github.notify("notifications/tools/list_changed", nil)
+
+	select {
+	case method := <-notifications:
+		if method != "notifications/tools/list_changed" {
+			t.Errorf("Expected a list change, got %s", method)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("No list change was sent")
+	}
+	response := request(t, sess, "tools/call", types.CallToolParams{Name: "github__commit"})
+	if response.Error != nil {
+		t.Errorf("github__commit is not registered: %v", response.Error)
+	}
+}
+
+// samplingSession opens an upstream session that answers sampling requests
+// with its name
+func samplingSession(t *testing.T, g *Gateway, name string) *server.Session {
+	t.Helper()
+
+	var sess *server.Session
+	sess = newTestSession(t, g, func(ctx context.Context, msg []byte) error {
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		json.Unmarshal(msg, &req)
+		if req.Method == "sampling/createMessage" {
+			reply := `{"jsonrpc":"2.0","id":` + string(req.ID) + `,"result":{"role":"assistant","content":{"type":"text","text":"` + name + `"},"model":"test"}}`
+			go sess.HandleMessage(context.Background(), []byte(reply))
+		}
+		return nil
+	})
+	return sess
+}
+
+func TestGatewayForwardRequest(t *testing.T) {
+	g, _, _ := newTestGateway(t)
+	slow := &fakeBackend{tools: []string{"wait", "sample"}, release: make(chan struct{})}
+	if err := g.addBackend("slow", BackendConfig{Command: "slow"}, slow); err != nil {
+		t.Fatalf("addBackend failed: %v", err)
+	}
+	first := samplingSession(t, g, "first")
+	second := samplingSession(t, g, "second")
+
+	// Requests from a backend go to the session whose call caused them
+	for name, sess := range map[string]*server.Session{"first": first, "second": second} {
+		response := request(t, sess, "tools/call", types.CallToolParams{Name: "github__sample"})
+		if response.Error != nil {
+			t.Fatalf("tools/call failed: %v", response.Error)
+		}
+		if !strings.Contains(string(response.Result), `\"text\":\"`+name+`\"`) {
+			t.Errorf("%s: sampled by the wrong client: %s", name, response.Result)
+		}
+	}
+
+	// Requests without a call in flight have no origin
+	if _, err := g.forwardRequest("github", "roots/list")(context.Background(), nil); err == nil {
+		t.Error("Expected an error for a request without an origin")
+	}
+
+	// Requests are rejected while calls of several sessions are in flight
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		request(t, first, "tools/call", types.CallToolParams{Name: "slow__wait"})
+	}()
+	for deadline := time.Now().Add(2 * time.Second); ; {
+		g.mu.Lock()
+		waiting := len(g.forwards["slow"])
+		g.mu.Unlock()
+		if waiting == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("slow__wait did not reach the backend")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	response := request(t, second, "tools/call", types.CallToolParams{Name: "slow__sample"})
+	var result types.CallToolResult
+	json.Unmarshal(response.Result, &result)
+	if response.Error == nil && !result.IsError {
+		t.Errorf("Expected slow__sample to fail, got %s", response.Result)
+	}
+	close(slow.release)
+	<-done
+}
+
+func TestGatewayNotifications(t *testing.T) {
+	g, github, _ := newTestGateway(t)
+	jobs := &fakeBackend{tools: []string{"progress"}}
+	if err := g.addBackend("jobs", BackendConfig{Command: "jobs"}, jobs); err != nil {
+		t.Fatalf("addBackend failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	sent := make(map[string][]string)
+	record := func(name string) server.SendFunc {
+		return func(ctx context.Context, msg []byte) error {
+			mu.Lock()
+			sent[name] = append(sent[name], string(msg))
+			mu.Unlock()
+			if strings.Contains(string(msg), `"method":"sampling/createMessage"`) {
+				// The backend gives up on the request before the client answers
+				go github.notify("notifications/cancelled", map[string]interface{}{"requestId": 1, "reason": "too slow"})
+			}
+			return nil
+		}
+	}
+	first := newTestSession(t, g, record("first"))
+	newTestSession(t, g, record("second"))
+
+	// Progress of a backend call reaches the client that asked for it, with its token
+	response := request(t, first, "tools/call", map[string]interface{}{"name": "jobs__progress", "_meta": map[string]interface{}{"progressToken": "upstream"}})
+	if response.Error != nil {
+		t.Fatalf("tools/call failed: %v", response.Error)
+	}
+	mu.Lock()
+	if len(sent["first"]) != 1 || !strings.Contains(sent["first"][0], `"params":{"progressToken":"upstream","progress":1,"total":2}`) {
+		t.Errorf("first received %v", sent["first"])
+	}
+	mu.Unlock()
+
+	// A backend cancelling its request cancels the request sent to the client
+	request(t, first, "tools/call", types.CallToolParams{Name: "github__sample"})
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent["first"]) != 3 || !strings.Contains(sent["first"][2], `"method":"notifications/cancelled","params":{"requestId":1,"reason":"cancelled by the backend: too slow"}`) {
+		t.Errorf("first received %v", sent["first"])
+	}
+	if len(sent["second"]) != 0 {
+		t.Errorf("second received %v", sent["second"])
+	}
+}
+
+func TestGatewayCapabilities(t *testing.T) {
+	g, _, _ := newTestGateway(t)
+	sess := g.server.NewSession(func(context.Context, []byte) error { return nil })
+	defer sess.Close()
+
+	response := request(t, sess, "initialize", map[string]interface{}{"protocolVersion": types.LatestProtocolVersion})
+	var result types.InitializeResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatalf("Failed to decode initialize result: %v", err)
+	}
+	caps, _ := json.Marshal(result.Capabilities)
+	if string(caps) != `{"prompts":{},"resources":{},"tools":{}}` {
+		t.Errorf("capabilities = %s", caps)
+	}
+	response = request(t, sess, "logging/setLevel", map[string]interface{}{"level": "debug"})
+	if response.Error == nil || response.Error.Code != server.CodeMethodNotFound {
+		t.Errorf("Expected method not found without logging, got %+v", response.Error)
+	}
+
+	logs := &fakeBackend{caps: &types.ServerCapabilities{
+		Tools:     &types.ToolsCapability{ListChanged: true},
+		Logging:   &types.LoggingCapability{},
+		Resources: &types.ResourcesCapability{Subscribe: true},
+	}}
+	if err := g.addBackend("logs", BackendConfig{Command: "logs"}, logs); err != nil {
+		t.Fatalf("addBackend failed: %v", err)
+	}
+	caps, _ = json.Marshal(g.capabilities())
+	if string(caps) != `{"logging":{},"prompts":{},"resources":{},"tools":{"listChanged":true}}` {
+		t.Errorf("capabilities = %s", caps)
+	}
+	response = request(t, sess, "logging/setLevel", map[string]interface{}{"level": "debug"})
+	if response.Error != nil {
+		t.Errorf("logging/setLevel failed: %v", response.Error)
+	}
+}
+
+func TestGatewayResourceTemplates(t *testing.T) {
+	g, _, _ := newTestGateway(t)
+
+	sess := newTestSession(t, g, nil)
+	response := request(t, sess, "resources/templates/list", nil)
+	if response.Error != nil {
+		t.Fatalf("resources/templates/list failed: %v", response.Error)
+	}
+	var list types.ListResourceTemplatesResult
+	if err := json.Unmarshal(response.Result, &list); err != nil {
+		t.Fatalf("Failed to decode templates: %v", err)
+	}
+	if len(list.ResourceTemplates) != 4 {
+		t.Errorf("Expected 4 templates, got %v", list.ResourceTemplates)
+	}
+}
+
+func TestServeStreams(t *testing.T) {
+	g, _, _ := newTestGateway(t)
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- server.ServeStreams(context.Background(), g.server, inReader, outWriter)
+		outWriter.Close()
+	}()
+
+	lines := bufio.NewScanner(outReader)
+	send := func(msg string) {
+		if _, err := io.WriteString(inWriter, msg+"\n"); err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+	}
+	receive := func() map[string]interface{} {
+		if !lines.Scan() {
+			t.Fatalf("Output ended: %v", lines.Err())
+		}
+		var msg map[string]interface{}
+		if err := json.Unmarshal(lines.Bytes(), &msg); err != nil {
+			t.Fatalf("Invalid output %q: %v", lines.Text(), err)
+		}
+		return msg
+	}
+
+	send(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"sampling":{}}}}`)
+	msg := receive()
+	result, _ := msg["result"].(map[string]interface{})
+	if result["protocolVersion"] != "2025-06-18" {
+		t.Fatalf("Unexpected initialize response: %v", msg)
+	}
+	send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+
+	// Sampling requests from a backend are answered by the client
+	send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"github__sample"}}`)
+	msg = receive()
+	if msg["method"] != "sampling/createMessage" {
+		t.Fatalf("Expected a forwarded sampling request, got %v", msg)
+	}
+	id, _ := json.Marshal(msg["id"])
+	send(`{"jsonrpc":"2.0","id":` + string(id) + `,"result":{"role":"assistant","content":{"type":"text","text":"hi"},"model":"test"}}`)
+
+	msg = receive()
+	result, _ = msg["result"].(map[string]interface{})
+	content, _ := result["content"].([]interface{})
+	if len(content) != 1 || !strings.Contains(fmt.Sprint(content[0]), `"text":"hi"`) {
+		t.Errorf("Unexpected tool result: %v", msg)
+	}
+
+	inWriter.Close()
+	if err := <-done; err != nil {
+		t.Errorf("ServeStreams returned %v", err)
+	}
+}
+
+func TestServeHTTP(t *testing.T) {
+	g, _, _ := newTestGateway(t)
+
+	handler := server.NewHTTPHandler(g.server)
+	ts := httptest.NewServer(handler)
+	defer func() {
+		handler.Close()
+		ts.Close()
+	}()
+
+	c := client.NewClient(
+		client.WithTransport(mcphttp.NewHTTPTransport(ts.URL)),
+		client.WithClientCapabilities(types.ClientCapabilities{Sampling: &types.SamplingCapability{}}),
+		client.WithRequestHandler("sampling/createMessage", func(ctx context.Context, params interface{}) (interface{}, error) {
+			return map[string]interface{}{
+				"role":    "assistant",
+				"content": map[string]interface{}{"type": "text", "text": "sampled"},
+				"model":   "test",
+			}, nil
+		}),
+	)
+	defer c.Close()
+
+	if err := c.Initialize(types.LatestProtocolVersion); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if c.GetSessionID() == "" {
+		t.Error("Expected a session id")
+	}
+
+	tools, err := c.ListTools()
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(tools) != 3 {
+		t.Errorf("Expected 3 tools, got %d", len(tools))
+	}
+
+	result, err := c.CallTool("github__sample", nil)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if len(result.Content) != 1 || !strings.Contains(fmt.Sprint(result.Content[0]), "sampled") {
+		t.Errorf("Unexpected tool result: %+v", result.Content)
+	}
+
+	// Terminated sessions are gone
+	req, _ := http.NewRequest(http.MethodDelete, ts.URL, nil)
+	req.Header.Set("Mcp-Session-Id", c.GetSessionID())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("DELETE: status %d, expected 204", resp.StatusCode)
+	}
+	if _, err := c.ListTools(); err == nil {
+		t.Error("Expected an error after the session was terminated")
+	}
+}
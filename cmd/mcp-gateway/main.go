@@ -0,0 +1,90 @@
+// Command mcp-gateway exposes several MCP servers as a single server.
+//
+// Backends are listed in a JSON configuration file. Each backend is either a
+// command started over stdio or the URL of a Streamable HTTP server:
+//
+//	{
+//	  "separator": "__",
+//	  "backends": {
+//	    "github": {"url": "https://example.com/mcp", "headers": {"Authorization": "Bearer ..."}},
+//	    "fs": {"command": "mcp-fs", "args": ["/data"], "prefix": "files", "deny": ["delete_*"]}
+//	  }
+//	}
+//
+// Tools and prompts are exposed with their backend's prefix, as in
+// github__search and files__read. The gateway keeps its lists in sync with the
+// backends and tells the connected clients when they change. Log messages from
+// backends go to the clients at the level each client sets. Progress
+// notifications, and sampling, elicitation and roots requests from backends
+// are forwarded to the client whose request the backend is handling.
+//
+// The gateway serves clients over stdio by default, or over Streamable HTTP
+// with -http:
+//
+//	mcp-gateway -config gateway.json
+//	mcp-gateway -config gateway.json -http localhost:8080 -path /mcp
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Convict3d/mcp-go/server"
+)
+
+func main() {
+	configPath := flag.String("config", "mcp-gateway.json", "path to the configuration file")
+	httpAddr := flag.String("http", "", "serve Streamable HTTP on this address instead of stdio")
+	httpPath := flag.String("path", "/mcp", "endpoint path for Streamable HTTP")
+	flag.Parse()
+
+	// Stdout carries protocol messages in stdio mode, so logs go to stderr
+	logger := log.New(os.Stderr, "mcp-gateway: ", log.LstdFlags)
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	g := newGateway(config.Separator, logger)
+	if err := g.connect(config); err != nil {
+		logger.Fatal(err)
+	}
+	defer g.Close()
+
+	if *httpAddr == "" {
+		if err := server.ServeStdio(ctx, g.server); err != nil {
+			logger.Printf("stdio: %v", err)
+		}
+		return
+	}
+
+	handler := server.NewHTTPHandler(g.server)
+	defer handler.Close()
+
+	mux := http.NewServeMux()
+	mux.Handle(*httpPath, handler)
+	httpServer := &http.Server{Addr: *httpAddr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdownCtx)
+	}()
+
+	logger.Printf("serving on http://%s%s", *httpAddr, *httpPath)
+	if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Printf("http: %v", err)
+	}
+}
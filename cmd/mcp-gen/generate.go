@@ -0,0 +1,473 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// Options controls the generated code
+type Options struct {
+	Package    string // Package name of the generated file
+	ClientType string // Name of the generated wrapper type
+	Source     string // Human readable description of where the tools came from
+}
+
+// commonInitialisms are rendered in upper case inside Go identifiers
+var commonInitialisms = map[string]bool{
+	"API": true, "CPU": true, "CSS": true, "DNS": true, "HTML": true, "HTTP": true,
+	"HTTPS": true, "ID": true, "IP": true, "JSON": true, "SQL": true, "SSH": true,
+	"TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true, "URI": true,
+	"URL": true, "UUID": true, "XML": true,
+}
+
+// Generate renders Go source for typed wrappers around the given tools.
+// Output is deterministic: tools, properties and definitions are emitted in sorted order.
+func Generate(tools []types.Tool, opts Options) ([]byte, error) {
+	if opts.Package == "" {
+		opts.Package = "tools"
+	}
+	if opts.ClientType == "" {
+		opts.ClientType = "ToolsClient"
+	}
+
+	sorted := make([]types.Tool, len(tools))
+	copy(sorted, tools)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	g := &generator{
+		typeNames: map[string]bool{opts.ClientType: true},
+	}
+
+	methodNames := make(map[string]string)
+	for i := range sorted {
+		tool := &sorted[i]
+		method := goName(tool.Name)
+		if other, ok := methodNames[method]; ok {
+			return nil, fmt.Errorf("tools %q and %q both map to Go name %s", other, tool.Name, method)
+		}
+		methodNames[method] = tool.Name
+
+		if err := g.addTool(tool, method); err != nil {
+			return nil, fmt.Errorf("tool %q: %w", tool.Name, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	g.writeFile(&buf, opts)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated code: %w", err)
+	}
+	return src, nil
+}
+
+// generator accumulates type declarations and methods for a set of tools
+type generator struct {
+	typeNames map[string]bool
+	decls     []string
+	methods   []string
+	usesTypes bool
+}
+
+// schemaContext tracks the root schema of a tool so that $ref can be resolved
+type schemaContext struct {
+	prefix string
+	root   map[string]interface{}
+	refs   map[string]string
+}
+
+// addTool generates the input and output types and the wrapper method for a tool
+func (g *generator) addTool(tool *types.Tool, method string) error {
+	input, err := toSchemaMap(tool.InputSchema)
+	if err != nil {
+		return fmt.Errorf("invalid inputSchema: %w", err)
+	}
+
+	inCtx := &schemaContext{prefix: method, root: input, refs: make(map[string]string)}
+	inputType := ""
+	if props, _ := input["properties"].(map[string]interface{}); len(props) > 0 {
+		inputType = g.structType(inCtx, input, method+"Input", fmt.Sprintf("holds the arguments of the %q tool", tool.Name))
+	}
+
+	outputType := ""
+	if tool.OutputSchema != nil {
+		output, err := toSchemaMap(tool.OutputSchema)
+		if err != nil {
+			return fmt.Errorf("invalid outputSchema: %w", err)
+		}
+		outCtx := &schemaContext{prefix: method, root: output, refs: make(map[string]string)}
+		outputType = g.structType(outCtx, output, method+"Output", fmt.Sprintf("holds the structured result of the %q tool", tool.Name))
+	}
+
+	g.methods = append(g.methods, g.method(tool, method, inputType, outputType))
+	return nil
+}
+
+// method renders the wrapper method for a tool
+func (g *generator) method(tool *types.Tool, method, inputType, outputType string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "// %s calls the %q tool.\n", method, tool.Name)
+	if desc := toolDescription(tool); desc != "" {
+		b.WriteString("//\n")
+		writeComment(&b, "", desc)
+	}
+
+	params := "ctx context.Context"
+	in := "struct{}{}"
+	inType := "struct{}"
+	if inputType != "" {
+		params += ", in " + inputType
+		in = "in"
+		inType = inputType
+	}
+
+	if outputType == "" {
+		g.usesTypes = true
+		fmt.Fprintf(&b, "func (c *{{client}}) %s(%s) (*types.CallToolResult, error) {\n", method, params)
+		fmt.Fprintf(&b, "\treturn client.CallTypedRaw[%s](ctx, c.Client, %q, %s)\n", inType, tool.Name, in)
+		b.WriteString("}\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "func (c *{{client}}) %s(%s) (*%s, error) {\n", method, params, outputType)
+	fmt.Fprintf(&b, "\tout, err := client.CallTyped[%s, %s](ctx, c.Client, %q, %s)\n", inType, outputType, tool.Name, in)
+	b.WriteString("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	b.WriteString("\treturn &out, nil\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// structType emits a struct declaration for an object schema and returns its name
+func (g *generator) structType(ctx *schemaContext, s map[string]interface{}, hint, doc string) string {
+	name := g.reserveName(hint)
+	g.emitStruct(ctx, s, name, doc)
+	return name
+}
+
+// emitStruct renders the fields of an object schema as a named struct
+func (g *generator) emitStruct(ctx *schemaContext, s map[string]interface{}, name, doc string) {
+	required := make(map[string]bool)
+	if list, ok := s["required"].([]interface{}); ok {
+		for _, item := range list {
+			if str, ok := item.(string); ok {
+				required[str] = true
+			}
+		}
+	}
+
+	props, _ := s["properties"].(map[string]interface{})
+	propNames := make([]string, 0, len(props))
+	for prop := range props {
+		propNames = append(propNames, prop)
+	}
+	sort.Strings(propNames)
+
+	// Field declarations are rendered before nested types are emitted so that
+	// the parent struct appears ahead of its children in the output
+	var b strings.Builder
+	if doc == "" {
+		doc = "is generated from a JSON Schema object"
+	}
+	fmt.Fprintf(&b, "// %s %s\n", name, doc)
+	if desc, ok := s["description"].(string); ok && strings.TrimSpace(desc) != "" {
+		b.WriteString("//\n")
+		writeComment(&b, "", desc)
+	}
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+
+	index := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	fieldNames := make(map[string]bool)
+	for _, prop := range propNames {
+		propSchema, _ := props[prop].(map[string]interface{})
+
+		field := goName(prop)
+		for i := 2; fieldNames[field]; i++ {
+			field = goName(prop) + strconv.Itoa(i)
+		}
+		fieldNames[field] = true
+
+		fieldType := g.goType(ctx, propSchema, name+goName(prop))
+		tag := prop
+		if !required[prop] {
+			tag += ",omitempty"
+			if isStructType(fieldType) {
+				fieldType = "*" + fieldType
+			}
+		}
+
+		if desc := fieldDescription(propSchema); desc != "" {
+			writeComment(&b, "\t", desc)
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:%q`\n", field, fieldType, tag)
+	}
+	b.WriteString("}\n")
+
+	g.decls[index] = b.String()
+}
+
+// goType maps a schema node to a Go type expression, emitting named types as needed
+func (g *generator) goType(ctx *schemaContext, s map[string]interface{}, hint string) string {
+	if s == nil {
+		return "interface{}"
+	}
+
+	if ref, ok := s["$ref"].(string); ok {
+		return g.refType(ctx, ref)
+	}
+
+	switch schemaType(s) {
+	case "string":
+		return "string"
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "array":
+		items, _ := s["items"].(map[string]interface{})
+		if items == nil {
+			return "[]interface{}"
+		}
+		return "[]" + g.goType(ctx, items, hint+"Item")
+	case "object":
+		if props, _ := s["properties"].(map[string]interface{}); len(props) > 0 {
+			return g.structType(ctx, s, hint, "")
+		}
+		if additional, ok := s["additionalProperties"].(map[string]interface{}); ok {
+			return "map[string]" + g.goType(ctx, additional, hint+"Value")
+		}
+		return "map[string]interface{}"
+	default:
+		return "interface{}"
+	}
+}
+
+// refType returns the named type for a local $ref, generating it on first use
+func (g *generator) refType(ctx *schemaContext, ref string) string {
+	if name, ok := ctx.refs[ref]; ok {
+		return name
+	}
+
+	target := resolveRef(ctx.root, ref)
+	if target == nil {
+		return "interface{}"
+	}
+
+	tokens := strings.Split(ref, "/")
+	hint := ctx.prefix + goName(tokens[len(tokens)-1])
+
+	if schemaType(target) != "object" || len(mapOf(target["properties"])) == 0 {
+		// Non-struct definitions are inlined; a placeholder guards against cycles
+		ctx.refs[ref] = "interface{}"
+		typ := g.goType(ctx, target, hint)
+		ctx.refs[ref] = typ
+		return typ
+	}
+
+	name := g.reserveName(hint)
+	ctx.refs[ref] = name
+	g.emitStruct(ctx, target, name, "")
+	return name
+}
+
+// reserveName returns a unique type name based on hint
+func (g *generator) reserveName(hint string) string {
+	name := hint
+	for i := 2; g.typeNames[name]; i++ {
+		name = hint + strconv.Itoa(i)
+	}
+	g.typeNames[name] = true
+	return name
+}
+
+// writeFile renders the complete source file
+func (g *generator) writeFile(b *bytes.Buffer, opts Options) {
+	b.WriteString("// Code generated by mcp-gen. DO NOT EDIT.\n")
+	if opts.Source != "" {
+		fmt.Fprintf(b, "// Source: %s\n", opts.Source)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(b, "package %s\n\n", opts.Package)
+
+	b.WriteString("import (\n\t\"context\"\n\n\t\"github.com/Convict3d/mcp-go/client\"\n")
+	if g.usesTypes {
+		b.WriteString("\t\"github.com/Convict3d/mcp-go/types\"\n")
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(b, "// %s wraps a client.Client with typed methods for the server's tools\n", opts.ClientType)
+	fmt.Fprintf(b, "type %s struct {\n\t*client.Client\n}\n\n", opts.ClientType)
+	fmt.Fprintf(b, "// New%s returns typed tool bindings for an initialized client\n", opts.ClientType)
+	fmt.Fprintf(b, "func New%s(c *client.Client) *%s {\n\treturn &%s{Client: c}\n}\n", opts.ClientType, opts.ClientType, opts.ClientType)
+
+	for _, m := range g.methods {
+		b.WriteString("\n")
+		b.WriteString(strings.ReplaceAll(m, "{{client}}", opts.ClientType))
+	}
+
+	for _, decl := range g.decls {
+		b.WriteString("\n")
+		b.WriteString(decl)
+	}
+}
+
+// schemaType returns the single non-null JSON type of a schema node, inferring it when absent
+func schemaType(s map[string]interface{}) string {
+	switch t := s["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		var found string
+		for _, item := range t {
+			name, _ := item.(string)
+			if name == "null" {
+				continue
+			}
+			if found != "" {
+				return ""
+			}
+			found = name
+		}
+		return found
+	}
+
+	if _, ok := s["properties"]; ok {
+		return "object"
+	}
+	if _, ok := s["items"]; ok {
+		return "array"
+	}
+	if enum, ok := s["enum"].([]interface{}); ok && len(enum) > 0 {
+		for _, item := range enum {
+			if _, ok := item.(string); !ok {
+				return ""
+			}
+		}
+		return "string"
+	}
+	return ""
+}
+
+// resolveRef follows a local JSON pointer such as "#/$defs/address"
+func resolveRef(root map[string]interface{}, ref string) map[string]interface{} {
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+
+	var node interface{} = root
+	pointer := strings.TrimPrefix(strings.TrimPrefix(ref, "#"), "/")
+	if pointer != "" {
+		for _, token := range strings.Split(pointer, "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			node = mapOf(node)[token]
+		}
+	}
+	return mapOf(node)
+}
+
+// fieldDescription builds the doc comment for a struct field
+func fieldDescription(s map[string]interface{}) string {
+	desc, _ := s["description"].(string)
+
+	if enum, ok := s["enum"].([]interface{}); ok && len(enum) > 0 {
+		values := make([]string, 0, len(enum))
+		for _, item := range enum {
+			data, _ := json.Marshal(item)
+			values = append(values, string(data))
+		}
+		allowed := "One of: " + strings.Join(values, ", ")
+		if desc == "" {
+			return allowed
+		}
+		return desc + "\n" + allowed
+	}
+
+	return desc
+}
+
+// toolDescription returns the description of a tool from either location it may appear in
+func toolDescription(tool *types.Tool) string {
+	if tool.Description != "" {
+		return tool.Description
+	}
+	return tool.BaseMetadata.Description
+}
+
+// writeComment writes text as a line comment with the given indentation
+func writeComment(b *strings.Builder, indent, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" {
+			fmt.Fprintf(b, "%s//\n", indent)
+			continue
+		}
+		fmt.Fprintf(b, "%s// %s\n", indent, line)
+	}
+}
+
+// goName converts a tool or property name into an exported Go identifier
+func goName(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var b strings.Builder
+	for _, word := range words {
+		upper := strings.ToUpper(word)
+		if commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		b.WriteString(string(runes[1:]))
+	}
+
+	result := b.String()
+	if result == "" {
+		return "Field"
+	}
+	if unicode.IsDigit([]rune(result)[0]) {
+		return "X" + result
+	}
+	return result
+}
+
+// isStructType reports whether a generated type expression names a struct
+func isStructType(typ string) bool {
+	return !strings.HasPrefix(typ, "[]") && !strings.HasPrefix(typ, "map[") && !strings.HasPrefix(typ, "*") &&
+		typ != "string" && typ != "int64" && typ != "float64" && typ != "bool" && typ != "interface{}"
+}
+
+// toSchemaMap converts a schema struct into its generic JSON representation
+func toSchemaMap(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out map[string]interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// mapOf returns v as a JSON object, or nil if it is not one
+func mapOf(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
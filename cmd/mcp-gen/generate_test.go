@@ -0,0 +1,119 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestGenerateGolden(t *testing.T) {
+	tools, err := loadToolsFile(filepath.Join("testdata", "tools.json"))
+	if err != nil {
+		t.Fatalf("Failed to load tools: %v", err)
+	}
+
+	got, err := Generate(tools, Options{Package: "weather", Source: "testdata/tools.json"})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	golden := filepath.Join("testdata", "tools.golden")
+	if *update {
+		if err := os.WriteFile(golden, got, 0o644); err != nil {
+			t.Fatalf("Failed to update golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+
+	if string(got) != string(want) {
+		t.Errorf("Generated code does not match %s (run go test -update to refresh):\n%s", golden, got)
+	}
+}
+
+func TestGenerateDeterministic(t *testing.T) {
+	tools, err := loadToolsFile(filepath.Join("testdata", "tools.json"))
+	if err != nil {
+		t.Fatalf("Failed to load tools: %v", err)
+	}
+
+	first, err := Generate(tools, Options{Package: "weather"})
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	// Reverse the input order; output must not change
+	reversed := make([]types.Tool, len(tools))
+	for i, tool := range tools {
+		reversed[len(tools)-1-i] = tool
+	}
+
+	for i := 0; i < 5; i++ {
+		again, err := Generate(reversed, Options{Package: "weather"})
+		if err != nil {
+			t.Fatalf("Generate failed: %v", err)
+		}
+		if string(again) != string(first) {
+			t.Fatal("Generate output is not deterministic")
+		}
+	}
+}
+
+func TestGenerateNameCollision(t *testing.T) {
+	tools := []types.Tool{
+		{BaseMetadata: types.BaseMetadata{Name: "get_user"}, InputSchema: types.ToolInputSchema{Type: "object"}},
+		{BaseMetadata: types.BaseMetadata{Name: "get-user"}, InputSchema: types.ToolInputSchema{Type: "object"}},
+	}
+
+	_, err := Generate(tools, Options{})
+	if err == nil || !strings.Contains(err.Error(), "GetUser") {
+		t.Errorf("Expected name collision error, got %v", err)
+	}
+}
+
+func TestGoName(t *testing.T) {
+	tests := map[string]string{
+		"get_weather":   "GetWeather",
+		"create-user":   "CreateUser",
+		"user_id":       "UserID",
+		"browser.click": "BrowserClick",
+		"fetchURL":      "FetchURL",
+		"2fa_code":      "X2faCode",
+		"":              "Field",
+	}
+
+	for in, want := range tests {
+		if got := goName(in); got != want {
+			t.Errorf("goName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseToolsFormats(t *testing.T) {
+	tests := map[string]string{
+		"list result":      `{"tools": [{"name": "a", "inputSchema": {"type": "object"}}]}`,
+		"jsonrpc response": `{"jsonrpc": "2.0", "id": 1, "result": {"tools": [{"name": "a", "inputSchema": {"type": "object"}}]}}`,
+		"bare array":       `[{"name": "a", "inputSchema": {"type": "object"}}]`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			tools, err := parseTools([]byte(data))
+			if err != nil {
+				t.Fatalf("parseTools failed: %v", err)
+			}
+			if len(tools) != 1 || tools[0].Name != "a" {
+				t.Errorf("Unexpected tools: %+v", tools)
+			}
+		})
+	}
+}
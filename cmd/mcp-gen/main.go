@@ -0,0 +1,190 @@
+// Command mcp-gen generates typed Go clients for the tools of an MCP server.
+//
+// The tool list is read from a running server over HTTP or stdio, or from a
+// saved tools/list response:
+//
+//	mcp-gen -url http://localhost:9831/mcp -package weather -o weather_tools.go
+//	mcp-gen -stdio "npx @playwright/mcp" -package browser -o browser_tools.go
+//	mcp-gen -input tools.json -package weather -o weather_tools.go
+//
+// The -stdio command is split like a shell command line, so quote arguments
+// that contain spaces.
+//
+// For each tool the generator emits an input struct derived from its
+// inputSchema, an output struct derived from its outputSchema (when present)
+// and a typed method on a wrapper around client.Client. Output is sorted and
+// gofmt-formatted, so the same tool list always produces the same file.
+//
+// Use it from go:generate with a checked-in tools/list snapshot:
+//
+//	//go:generate go run github.com/Convict3d/mcp-go/cmd/mcp-gen -input tools.json -o tools_gen.go
+//
+// When run by go generate the package name defaults to $GOPACKAGE.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Convict3d/mcp-go/client"
+	"github.com/Convict3d/mcp-go/internal/cmdline"
+	"github.com/Convict3d/mcp-go/transport"
+	"github.com/Convict3d/mcp-go/transport/http"
+	"github.com/Convict3d/mcp-go/transport/stdio"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// headerFlags collects repeated -header "Name: value" flags
+type headerFlags map[string]string
+
+func (h headerFlags) String() string {
+	return fmt.Sprint(map[string]string(h))
+}
+
+func (h headerFlags) Set(value string) error {
+	name, val, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("header must be in the form 'Name: value'")
+	}
+	h[strings.TrimSpace(name)] = strings.TrimSpace(val)
+	return nil
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "mcp-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	headers := headerFlags{}
+
+	fs := flag.NewFlagSet("mcp-gen", flag.ContinueOnError)
+	input := fs.String("input", "", "read tools from a saved tools/list JSON file")
+	url := fs.String("url", "", "connect to an MCP server over HTTP")
+	command := fs.String("stdio", "", "launch an MCP server command and connect over stdio")
+	pkg := fs.String("package", os.Getenv("GOPACKAGE"), "package name of the generated file")
+	clientType := fs.String("type", "ToolsClient", "name of the generated client wrapper type")
+	output := fs.String("o", "", "output file (default: stdout)")
+	timeout := fs.Duration("timeout", 30*time.Second, "timeout for server requests")
+	fs.Var(headers, "header", "HTTP header 'Name: value' (repeatable)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	sources := 0
+	for _, s := range []string{*input, *url, *command} {
+		if s != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return errors.New("exactly one of -input, -url or -stdio is required")
+	}
+
+	var (
+		tools  []types.Tool
+		source string
+		err    error
+	)
+	switch {
+	case *input != "":
+		tools, err = loadToolsFile(*input)
+		source = *input
+	case *url != "":
+		tools, err = fetchTools(http.NewHTTPTransport(*url, http.WithTimeout(*timeout), http.WithCustomHeaders(headers)))
+		source = *url
+	default:
+		var fields []string
+		if fields, err = cmdline.Split(*command); err != nil {
+			return fmt.Errorf("-stdio: %w", err)
+		}
+		if len(fields) == 0 {
+			return errors.New("-stdio requires a command")
+		}
+		var t *stdio.Transport
+		t, err = stdio.NewTransport(fields[0], fields[1:], stdio.WithTimeout(*timeout))
+		if err == nil {
+			tools, err = fetchTools(t)
+		}
+		source = *command
+	}
+	if err != nil {
+		return err
+	}
+
+	src, err := Generate(tools, Options{
+		Package:    *pkg,
+		ClientType: *clientType,
+		Source:     source,
+	})
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0o644)
+}
+
+// fetchTools initializes a client over the transport and lists every page of
+// the server's tools
+func fetchTools(t transport.Transport) ([]types.Tool, error) {
+	c := client.NewClient(
+		client.WithTransport(t),
+		client.WithClientInfo("mcp-gen", "1.0.0"),
+		client.WithCatalog(),
+	)
+	defer c.Close()
+
+	if err := c.Initialize(types.LatestProtocolVersion); err != nil {
+		return nil, fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	tools, err := c.Catalog().Tools(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tools: %w", err)
+	}
+	return tools, nil
+}
+
+// loadToolsFile reads tools from a saved tools/list result, a full JSON-RPC
+// response wrapping one, or a bare array of tools
+func loadToolsFile(path string) ([]types.Tool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTools(data)
+}
+
+// parseTools decodes the supported tools/list file formats
+func parseTools(data []byte) ([]types.Tool, error) {
+	var tools []types.Tool
+	if err := json.Unmarshal(data, &tools); err == nil {
+		return tools, nil
+	}
+
+	var doc struct {
+		types.ListToolsResult
+		Result *types.ListToolsResult `json:"result"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse tools file: %w", err)
+	}
+
+	if doc.Result != nil {
+		return doc.Result.Tools, nil
+	}
+	return doc.Tools, nil
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	mcphttp "github.com/Convict3d/mcp-go/transport/http"
+)
+
+func TestFetchToolsPaginated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     interface{}            `json:"id"`
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.ID == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "initialize":
+			result = map[string]interface{}{
+				"protocolVersion": "2025-06-18",
+				"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+				"serverInfo":      map[string]interface{}{"name": "paged", "version": "1.0.0"},
+			}
+		case "tools/list":
+			tool := map[string]interface{}{"name": "first", "inputSchema": map[string]interface{}{"type": "object"}}
+			page := map[string]interface{}{"tools": []interface{}{tool}, "nextCursor": "2"}
+			if req.Params["cursor"] == "2" {
+				tool = map[string]interface{}{"name": "second", "inputSchema": map[string]interface{}{"type": "object"}}
+				page = map[string]interface{}{"tools": []interface{}{tool}}
+			}
+			result = page
+		default:
+			result = map[string]interface{}{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	defer server.Close()
+
+	tools, err := fetchTools(mcphttp.NewHTTPTransport(server.URL))
+	if err != nil {
+		t.Fatalf("fetchTools failed: %v", err)
+	}
+	if len(tools) != 2 || tools[0].Name != "first" || tools[1].Name != "second" {
+		t.Errorf("Tools = %+v, expected both pages", tools)
+	}
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/Convict3d/mcp-go/client"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// commandHelp lists the commands accepted on the command line and in the REPL
+const commandHelp = `  connect                                  show server info and capabilities
+  tools list                               list tools
+  tools call NAME [--arg k=v]... [--json ARGS]
+                                           call a tool
+  resources list                           list resources
+  resources read URI                       read a resource
+  prompts list                             list prompts
+  prompts get NAME [--arg k=v]...          get a prompt
+  ping                                     check that the server responds
+  log-level LEVEL                          set the server's log level
+`
+
+// errUsage reports a malformed command
+var errUsage = errors.New("invalid command, see help")
+
+// cli runs commands against a connected client
+type cli struct {
+	client *client.Client
+	out    io.Writer
+	format string
+
+	mu     sync.Mutex
+	notify func(text string) // Prints a server notification
+}
+
+// argFlags collects repeated --arg key=value flags
+type argFlags []string
+
+func (a *argFlags) String() string {
+	return strings.Join(*a, ",")
+}
+
+func (a *argFlags) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("argument must be in the form key=value")
+	}
+	*a = append(*a, value)
+	return nil
+}
+
+// execute runs one command
+func (c *cli) execute(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "connect":
+		return c.connect()
+	case "ping":
+		if err := c.client.Ping(ctx); err != nil {
+			return err
+		}
+		fmt.Fprintln(c.out, "ok")
+		return nil
+	case "log-level":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: log-level LEVEL")
+		}
+		err := c.client.SetLogLevel(ctx, types.LoggingLevel(args[1]))
+		if errors.Is(err, client.ErrLoggingNotSupported) {
+			return fmt.Errorf("cannot set the log level: %w", err)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.out, "log level set to %s\n", args[1])
+		return nil
+	case "tools":
+		return c.tools(ctx, args[1:])
+	case "resources":
+		return c.resources(ctx, args[1:])
+	case "prompts":
+		return c.prompts(ctx, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// connect prints the server info and capabilities
+func (c *cli) connect() error {
+	info := c.client.GetServerInfo()
+	caps := c.client.GetCapabilities()
+
+	if c.format == formatJSON {
+		return writeJSON(c.out, map[string]interface{}{
+			"serverInfo":   info,
+			"capabilities": caps,
+			"sessionId":    c.client.GetSessionID(),
+		})
+	}
+
+	var supported []string
+	for name, ok := range map[string]bool{
+		"tools":       c.client.HasTools(),
+		"resources":   c.client.HasResources(),
+		"prompts":     c.client.HasPrompts(),
+		"logging":     c.client.HasLogging(),
+		"completions": c.client.HasCompletions(),
+	} {
+		if ok {
+			supported = append(supported, name)
+		}
+	}
+
+	rows := [][]string{
+		{"server", info.Name + " " + info.Version},
+		{"capabilities", strings.Join(sortedStrings(supported), ", ")},
+	}
+	if id := c.client.GetSessionID(); id != "" {
+		rows = append(rows, []string{"session", id})
+	}
+	return writeTable(c.out, nil, rows)
+}
+
+// tools runs the tools subcommands
+func (c *cli) tools(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: tools list | tools call NAME [--arg k=v]... [--json ARGS]")
+	}
+
+	switch args[0] {
+	case "list":
+		tools, err := c.client.Catalog().Tools(ctx)
+		if err != nil {
+			return err
+		}
+		if c.format == formatJSON {
+			return writeJSON(c.out, tools)
+		}
+		rows := make([][]string, 0, len(tools))
+		for _, tool := range tools {
+			rows = append(rows, []string{tool.Name, summary(tool.Description)})
+		}
+		return writeTable(c.out, []string{"NAME", "DESCRIPTION"}, rows)
+
+	case "call":
+		var pairs argFlags
+		fs := flag.NewFlagSet("tools call", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Var(&pairs, "arg", "tool argument key=value (repeatable)")
+		rawJSON := fs.String("json", "", "tool arguments as a JSON object")
+		name, err := parseNamed(fs, args[1:], "tools call NAME [--arg k=v]... [--json ARGS]")
+		if err != nil {
+			return err
+		}
+
+		arguments, err := toolArguments(*rawJSON, pairs)
+		if err != nil {
+			return err
+		}
+
+		result, err := c.client.CallToolContext(ctx, name, arguments)
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			return errors.New("server does not support tools")
+		}
+		if c.format == formatJSON {
+			return writeJSON(c.out, result)
+		}
+		if err := writeContents(c.out, result.Content); err != nil {
+			return err
+		}
+		if result.StructuredContent != nil {
+			if err := writeJSON(c.out, result.StructuredContent); err != nil {
+				return err
+			}
+		}
+		if result.IsError {
+			return errors.New("tool returned an error")
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown tools command %q", args[0])
+	}
+}
+
+// resources runs the resources subcommands
+func (c *cli) resources(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: resources list | resources read URI")
+	}
+
+	switch args[0] {
+	case "list":
+		resources, err := c.client.Catalog().Resources(ctx)
+		if err != nil {
+			return err
+		}
+		if c.format == formatJSON {
+			return writeJSON(c.out, resources)
+		}
+		rows := make([][]string, 0, len(resources))
+		for _, resource := range resources {
+			rows = append(rows, []string{resource.URI, resource.Name, resource.MimeType})
+		}
+		return writeTable(c.out, []string{"URI", "NAME", "MIME TYPE"}, rows)
+
+	case "read":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: resources read URI")
+		}
+		result, err := c.client.ReadResourceContext(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			return errors.New("server does not support resources")
+		}
+		if c.format == formatJSON {
+			return writeJSON(c.out, result)
+		}
+		for _, contents := range result.Contents {
+			switch {
+			case contents.Text != "":
+				fmt.Fprintln(c.out, contents.Text)
+			case contents.Blob != "":
+				fmt.Fprintf(c.out, "[blob %s, %d bytes base64]\n", contents.MimeType, len(contents.Blob))
+			default:
+				fmt.Fprintf(c.out, "[empty %s]\n", contents.URI)
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown resources command %q", args[0])
+	}
+}
+
+// prompts runs the prompts subcommands
+func (c *cli) prompts(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: prompts list | prompts get NAME [--arg k=v]...")
+	}
+
+	switch args[0] {
+	case "list":
+		prompts, err := c.client.Catalog().Prompts(ctx)
+		if err != nil {
+			return err
+		}
+		if c.format == formatJSON {
+			return writeJSON(c.out, prompts)
+		}
+		rows := make([][]string, 0, len(prompts))
+		for _, prompt := range prompts {
+			var arguments []string
+			for _, arg := range prompt.Arguments {
+				if arg.Required {
+					arguments = append(arguments, arg.Name)
+				} else {
+					arguments = append(arguments, "["+arg.Name+"]")
+				}
+			}
+			rows = append(rows, []string{prompt.Name, strings.Join(arguments, " "), summary(prompt.Description)})
+		}
+		return writeTable(c.out, []string{"NAME", "ARGUMENTS", "DESCRIPTION"}, rows)
+
+	case "get":
+		var pairs argFlags
+		fs := flag.NewFlagSet("prompts get", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		fs.Var(&pairs, "arg", "prompt argument key=value (repeatable)")
+		name, err := parseNamed(fs, args[1:], "prompts get NAME [--arg k=v]...")
+		if err != nil {
+			return err
+		}
+
+		arguments := make(map[string]string, len(pairs))
+		for _, pair := range pairs {
+			key, value, _ := strings.Cut(pair, "=")
+			arguments[key] = value
+		}
+
+		result, err := c.client.GetPromptContext(ctx, name, arguments)
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			return errors.New("server does not support prompts")
+		}
+		if c.format == formatJSON {
+			return writeJSON(c.out, result)
+		}
+		if result.Description != "" {
+			fmt.Fprintln(c.out, result.Description)
+		}
+		for _, message := range result.Messages {
+			fmt.Fprintf(c.out, "[%s] ", message.Role)
+			if err := writeContents(c.out, []interface{}{message.Content}); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown prompts command %q", args[0])
+	}
+}
+
+// printNotifications is an interceptor that prints notifications from the server
+func (c *cli) printNotifications(ctx context.Context, call *client.Call, next client.Handler) error {
+	if call.Direction == client.Incoming && call.Notification {
+		var text string
+		if c.format == formatJSON {
+			data, _ := json.Marshal(map[string]interface{}{"method": call.Method, "params": call.Params})
+			text = string(data)
+		} else {
+			text = "notification: " + call.Method
+			if call.Params != nil {
+				data, _ := json.Marshal(call.Params)
+				text += " " + string(data)
+			}
+		}
+
+		c.mu.Lock()
+		notify := c.notify
+		c.mu.Unlock()
+		notify(text)
+	}
+	return next(ctx, call)
+}
+
+// setNotify replaces the function that prints notifications
+func (c *cli) setNotify(notify func(text string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify = notify
+}
+
+// parseNamed parses the flags of a command taking one name argument, which
+// may come before or after the flags
+func parseNamed(fs *flag.FlagSet, args []string, usage string) (string, error) {
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if name == "" && fs.NArg() > 0 {
+		name = fs.Arg(0)
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			return "", err
+		}
+	}
+	if name == "" || fs.NArg() > 0 {
+		return "", fmt.Errorf("usage: %s", usage)
+	}
+	return name, nil
+}
+
+// toolArguments builds tool arguments from a JSON object and key=value pairs.
+// Values that are valid JSON, such as numbers, booleans, arrays and objects,
+// are decoded; other values are passed as strings. Pairs override the JSON.
+func toolArguments(rawJSON string, pairs []string) (map[string]interface{}, error) {
+	arguments := map[string]interface{}{}
+	if rawJSON != "" {
+		if err := json.Unmarshal([]byte(rawJSON), &arguments); err != nil {
+			return nil, fmt.Errorf("--json must be a JSON object: %w", err)
+		}
+	}
+
+	for _, pair := range pairs {
+		key, value, _ := strings.Cut(pair, "=")
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+			arguments[key] = decoded
+		} else {
+			arguments[key] = value
+		}
+	}
+	return arguments, nil
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Control keys handled by the line editor
+const (
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyBackspace = 8
+	keyTab       = 9
+	keyCtrlU     = 21
+	keyCtrlW     = 23
+	keyEscape    = 27
+	keyDelete    = 127
+)
+
+// lineEditor reads lines from a terminal with history and tab completion.
+// When the input is not a terminal it reads plain lines without editing.
+type lineEditor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	prompt   string
+	complete func(line string) []string // Candidates for the last word of line
+	fd       int                        // Terminal file descriptor, -1 if not a terminal
+	editing  bool                       // Handle keys one at a time
+	history  []string
+
+	mu     sync.Mutex
+	buf    []rune
+	active bool // A line is being edited
+}
+
+// newLineEditor creates an editor that reads from in and echoes to out
+func newLineEditor(in io.Reader, out io.Writer, prompt string, complete func(line string) []string) *lineEditor {
+	e := &lineEditor{
+		in:       bufio.NewReader(in),
+		out:      out,
+		prompt:   prompt,
+		complete: complete,
+		fd:       -1,
+	}
+	if f, ok := in.(*os.File); ok && isTerminal(int(f.Fd())) {
+		e.fd = int(f.Fd())
+		e.editing = true
+	}
+	return e
+}
+
+// readLine reads one line. It returns io.EOF at the end of the input or when
+// Ctrl-D is pressed on an empty line.
+func (e *lineEditor) readLine() (string, error) {
+	if !e.editing {
+		return e.readPlain()
+	}
+
+	if e.fd >= 0 {
+		state, err := makeRaw(e.fd)
+		if err != nil {
+			return e.readPlain()
+		}
+		defer restore(e.fd, state)
+	}
+	return e.edit()
+}
+
+// readPlain reads a line without editing
+func (e *lineEditor) readPlain() (string, error) {
+	e.mu.Lock()
+	fmt.Fprint(e.out, e.prompt)
+	e.active = true
+	e.mu.Unlock()
+
+	line, err := e.in.ReadString('\n')
+
+	e.mu.Lock()
+	e.active = false
+	e.mu.Unlock()
+
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// edit reads keys until Enter
+func (e *lineEditor) edit() (string, error) {
+	e.mu.Lock()
+	e.buf = e.buf[:0]
+	e.active = true
+	fmt.Fprint(e.out, e.prompt)
+	e.mu.Unlock()
+
+	historyIndex := len(e.history)
+
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			e.mu.Lock()
+			e.active = false
+			e.mu.Unlock()
+			return "", err
+		}
+
+		e.mu.Lock()
+		switch r {
+		case '\r', '\n':
+			line := string(e.buf)
+			e.active = false
+			fmt.Fprint(e.out, "\n")
+			e.mu.Unlock()
+
+			if strings.TrimSpace(line) != "" && (len(e.history) == 0 || e.history[len(e.history)-1] != line) {
+				e.history = append(e.history, line)
+			}
+			return line, nil
+
+		case keyCtrlC:
+			e.buf = e.buf[:0]
+			fmt.Fprint(e.out, "^C\n"+e.prompt)
+
+		case keyCtrlD:
+			if len(e.buf) == 0 {
+				e.active = false
+				e.mu.Unlock()
+				return "", io.EOF
+			}
+
+		case keyBackspace, keyDelete:
+			if len(e.buf) > 0 {
+				e.buf = e.buf[:len(e.buf)-1]
+				e.redraw()
+			}
+
+		case keyCtrlU:
+			e.buf = e.buf[:0]
+			e.redraw()
+
+		case keyCtrlW:
+			line := strings.TrimRight(string(e.buf), " ")
+			e.buf = []rune(line[:strings.LastIndex(line, " ")+1])
+			e.redraw()
+
+		case keyTab:
+			line := string(e.buf)
+			e.mu.Unlock()
+			// Completion may wait for the server, which must be able to
+			// print notifications in the meantime
+			matches := e.complete(line)
+			e.mu.Lock()
+			e.applyCompletion(matches)
+
+		case keyEscape:
+			e.mu.Unlock()
+			key := e.readEscape()
+			e.mu.Lock()
+			switch {
+			case key == 'A' && historyIndex > 0:
+				historyIndex--
+				e.buf = []rune(e.history[historyIndex])
+				e.redraw()
+			case key == 'B' && historyIndex < len(e.history):
+				historyIndex++
+				e.buf = e.buf[:0]
+				if historyIndex < len(e.history) {
+					e.buf = []rune(e.history[historyIndex])
+				}
+				e.redraw()
+			}
+
+		default:
+			if r >= ' ' {
+				e.buf = append(e.buf, r)
+				fmt.Fprint(e.out, string(r))
+			}
+		}
+		e.mu.Unlock()
+	}
+}
+
+// readEscape reads the rest of an escape sequence and returns its final
+// byte, such as 'A' for the up arrow
+func (e *lineEditor) readEscape() byte {
+	b, err := e.in.ReadByte()
+	if err != nil || (b != '[' && b != 'O') {
+		return 0
+	}
+	for {
+		b, err := e.in.ReadByte()
+		if err != nil {
+			return 0
+		}
+		if b >= 0x40 && b <= 0x7e {
+			return b
+		}
+	}
+}
+
+// applyCompletion completes the last word from the candidates. A single
+// candidate is inserted with a trailing space; several candidates are
+// completed to their common prefix or listed. Callers must hold e.mu.
+func (e *lineEditor) applyCompletion(matches []string) {
+	line := string(e.buf)
+	word := line[strings.LastIndex(line, " ")+1:]
+
+	switch len(matches) {
+	case 0:
+		fmt.Fprint(e.out, "\a")
+	case 1:
+		e.insert(strings.TrimPrefix(matches[0], word) + " ")
+	default:
+		if common := commonPrefix(matches); len(common) > len(word) {
+			e.insert(strings.TrimPrefix(common, word))
+			return
+		}
+		fmt.Fprint(e.out, "\r\033[K"+strings.Join(matches, "  ")+"\n")
+		e.redraw()
+	}
+}
+
+// insert appends s to the line and echoes it. Callers must hold e.mu.
+func (e *lineEditor) insert(s string) {
+	e.buf = append(e.buf, []rune(s)...)
+	fmt.Fprint(e.out, s)
+}
+
+// redraw rewrites the prompt and the line. Callers must hold e.mu.
+func (e *lineEditor) redraw() {
+	fmt.Fprint(e.out, "\r\033[K"+e.prompt+string(e.buf))
+}
+
+// printAbove prints text on its own line, keeping the line being edited below it
+func (e *lineEditor) printAbove(text string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.active {
+		fmt.Fprintln(e.out, text)
+		return
+	}
+	if e.editing {
+		fmt.Fprint(e.out, "\r\033[K"+text+"\n")
+		e.redraw()
+		return
+	}
+	fmt.Fprint(e.out, "\n"+text+"\n"+e.prompt)
+}
+
+// commonPrefix returns the longest common prefix of words
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		for !strings.HasPrefix(word, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,192 @@
+// Command mcp is an interactive inspector for MCP servers.
+//
+// It connects to a server over Streamable HTTP, over stdio, or by name from
+// an mcpServers configuration file, and runs one command:
+//
+//	mcp -url http://localhost:8931/mcp tools list
+//	mcp -stdio "npx @playwright/mcp" tools call browser_navigate --arg url=https://example.com
+//	mcp -config claude_desktop_config.json -server github prompts get review --arg pr=42
+//
+// Without a command it starts a REPL with tab completion of commands, tool,
+// prompt and resource names:
+//
+//	mcp -url http://localhost:8931/mcp
+//	mcp> tools call browser_<TAB>
+//
+// Commands:
+//
+//	connect                                  show server info and capabilities
+//	tools list                               list tools
+//	tools call NAME [--arg k=v]... [--json ARGS]
+//	resources list                           list resources
+//	resources read URI                       read a resource
+//	prompts list                             list prompts
+//	prompts get NAME [--arg k=v]...          get a prompt
+//	ping                                     check that the server responds
+//	log-level LEVEL                          set the server's log level
+//
+// Output is a table by default, or indented JSON with -o json. Server
+// notifications are printed as they arrive.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/Convict3d/mcp-go/client"
+	"github.com/Convict3d/mcp-go/config"
+	"github.com/Convict3d/mcp-go/internal/cmdline"
+	"github.com/Convict3d/mcp-go/transport"
+	"github.com/Convict3d/mcp-go/transport/http"
+	"github.com/Convict3d/mcp-go/transport/stdio"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// version is reported as the client version during initialization
+const version = "1.0.0"
+
+// headerFlags collects repeated -header "Name: value" flags
+type headerFlags map[string]string
+
+func (h headerFlags) String() string {
+	return fmt.Sprint(map[string]string(h))
+}
+
+func (h headerFlags) Set(value string) error {
+	name, val, ok := strings.Cut(value, ":")
+	if !ok {
+		return fmt.Errorf("header must be in the form 'Name: value'")
+	}
+	h[strings.TrimSpace(name)] = strings.TrimSpace(val)
+	return nil
+}
+
+// target describes the server to connect to
+type target struct {
+	url        string
+	command    string
+	headers    headerFlags
+	configPath string
+	server     string
+	timeout    time.Duration
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "mcp: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	t := target{headers: headerFlags{}}
+
+	fs := flag.NewFlagSet("mcp", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&t.url, "url", "", "connect to an MCP server over Streamable HTTP")
+	fs.StringVar(&t.command, "stdio", "", "launch an MCP server command and connect over stdio")
+	fs.StringVar(&t.configPath, "config", "", "mcpServers configuration file")
+	fs.StringVar(&t.server, "server", "", "name of the server in the configuration file")
+	fs.DurationVar(&t.timeout, "timeout", 30*time.Second, "timeout for server requests")
+	fs.Var(t.headers, "header", "HTTP header 'Name: value' (repeatable)")
+	format := fs.String("o", formatTable, "output format: table or json")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: mcp [flags] [command]\n\nFlags:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(stderr, "\nCommands:\n%s", commandHelp)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != formatTable && *format != formatJSON {
+		return fmt.Errorf("unknown output format %q", *format)
+	}
+
+	cli := &cli{out: stdout, format: *format}
+	cli.notify = func(text string) { fmt.Fprintln(stderr, text) }
+
+	c, err := t.connect(client.WithInterceptors(cli.printNotifications))
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	cli.client = c
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if fs.NArg() == 0 {
+		stop()
+		return cli.repl(stdin, stdout)
+	}
+	return cli.execute(ctx, fs.Args())
+}
+
+// connect creates the transport for the target and an initialized client
+func (t *target) connect(opts ...client.Option) (*client.Client, error) {
+	tr, err := t.transport()
+	if err != nil {
+		return nil, err
+	}
+
+	opts = append(opts,
+		client.WithTransport(tr),
+		client.WithClientInfo("mcp", version),
+		client.WithTimeout(t.timeout),
+		client.WithCatalog(),
+	)
+	c := client.NewClient(opts...)
+	if err := c.Initialize(types.LatestProtocolVersion); err != nil {
+		tr.Close()
+		return nil, fmt.Errorf("initialize failed: %w", err)
+	}
+	return c, nil
+}
+
+// transport creates the transport selected by the flags
+func (t *target) transport() (transport.Transport, error) {
+	sources := 0
+	for _, s := range []string{t.url, t.command, t.configPath} {
+		if s != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, errors.New("exactly one of -url, -stdio or -config is required")
+	}
+
+	switch {
+	case t.configPath != "":
+		cfg, err := config.Load(t.configPath)
+		if err != nil {
+			return nil, err
+		}
+		if t.server == "" {
+			return nil, fmt.Errorf("-server is required with -config (servers: %s)", strings.Join(cfg.Names(), ", "))
+		}
+		server, ok := cfg.MCPServers[t.server]
+		if !ok {
+			return nil, fmt.Errorf("unknown server %q (servers: %s)", t.server, strings.Join(cfg.Names(), ", "))
+		}
+		return server.NewTransport(t.timeout)
+	case t.url != "":
+		return http.NewHTTPTransport(t.url, http.WithTimeout(t.timeout), http.WithCustomHeaders(t.headers)), nil
+	default:
+		fields, err := cmdline.Split(t.command)
+		if err != nil {
+			return nil, fmt.Errorf("-stdio: %w", err)
+		}
+		if len(fields) == 0 {
+			return nil, errors.New("-stdio requires a command")
+		}
+		return stdio.NewTransport(fields[0], fields[1:], stdio.WithTimeout(t.timeout))
+	}
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestServer starts an HTTP MCP server with echo tools, review and
+// summarize prompts and readme and logo resources. Every list has two pages.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     interface{}            `json:"id"`
+			Method string                 `json:"method"`
+			Params map[string]interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var result interface{}
+		switch req.Method {
+		case "initialize":
+			result = map[string]interface{}{
+				"protocolVersion": "2025-06-18",
+				"capabilities": map[string]interface{}{
+					"tools":     map[string]interface{}{},
+					"prompts":   map[string]interface{}{},
+					"resources": map[string]interface{}{},
+					"logging":   map[string]interface{}{},
+				},
+				"serverInfo": map[string]interface{}{"name": "test-server", "version": "2.0.0"},
+			}
+		case "tools/list":
+			result = page(req.Params, "tools",
+				map[string]interface{}{"name": "echo", "description": "Echo the text back\nwith details", "inputSchema": map[string]interface{}{"type": "object"}},
+				map[string]interface{}{"name": "echo_json", "description": "Echo the arguments as JSON", "inputSchema": map[string]interface{}{"type": "object"}},
+			)
+		case "tools/call":
+			arguments, _ := req.Params["arguments"].(map[string]interface{})
+			text := fmt.Sprint(arguments["text"])
+			if req.Params["name"] == "echo_json" {
+				data, _ := json.Marshal(arguments)
+				text = string(data)
+			}
+			result = map[string]interface{}{"content": []map[string]interface{}{{"type": "text", "text": text}}}
+		case "prompts/list":
+			result = page(req.Params, "prompts",
+				map[string]interface{}{"name": "review", "arguments": []map[string]interface{}{{"name": "pr", "required": true}, {"name": "style"}}},
+				map[string]interface{}{"name": "summarize"},
+			)
+		case "prompts/get":
+			arguments, _ := req.Params["arguments"].(map[string]interface{})
+			result = map[string]interface{}{"messages": []map[string]interface{}{
+				{"role": "user", "content": map[string]interface{}{"type": "text", "text": fmt.Sprintf("Review PR %v", arguments["pr"])}},
+			}}
+		case "resources/list":
+			result = page(req.Params, "resources",
+				map[string]interface{}{"uri": "file:///readme.md", "name": "readme", "mimeType": "text/markdown"},
+				map[string]interface{}{"uri": "https://example.com/logo.png", "name": "logo", "mimeType": "image/png"},
+			)
+		case "resources/read":
+			result = map[string]interface{}{"contents": []map[string]interface{}{
+				{"uri": req.Params["uri"], "text": "# Readme"},
+			}}
+		default:
+			result = map[string]interface{}{}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// page returns the first item of a list result with a cursor to the second,
+// or the second item when params carry that cursor
+func page(params map[string]interface{}, field string, first, second map[string]interface{}) map[string]interface{} {
+	if params["cursor"] == "2" {
+		return map[string]interface{}{field: []interface{}{second}}
+	}
+	return map[string]interface{}{field: []interface{}{first}, "nextCursor": "2"}
+}
+
+// runCommand runs the CLI against url and returns its output
+func runCommand(t *testing.T, url string, stdin string, args ...string) (string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	err := run(append([]string{"-url", url}, args...), strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestRunCommands(t *testing.T) {
+	server := newTestServer(t)
+
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"connect"}, []string{"test-server 2.0.0", "logging, prompts, resources, tools"}},
+		{[]string{"tools", "list"}, []string{"NAME", "echo", "Echo the text back", "echo_json"}},
+		{[]string{"tools", "call", "echo", "--arg", "text=hello"}, []string{"hello"}},
+		{[]string{"tools", "call", "--arg", "n=3", "echo_json", "--json", `{"a": true}`}, []string{`{"a":true,"n":3}`}},
+		{[]string{"resources", "list"}, []string{"file:///readme.md", "text/markdown", "https://example.com/logo.png"}},
+		{[]string{"resources", "read", "file:///readme.md"}, []string{"# Readme"}},
+		{[]string{"prompts", "list"}, []string{"review", "pr [style]", "summarize"}},
+		{[]string{"prompts", "get", "review", "--arg", "pr=42"}, []string{"[user] Review PR 42"}},
+		{[]string{"ping"}, []string{"ok"}},
+		{[]string{"log-level", "debug"}, []string{"log level set to debug"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			out, err := runCommand(t, server.URL, "", tt.args...)
+			if err != nil {
+				t.Fatalf("run failed: %v", err)
+			}
+			for _, s := range tt.expected {
+				if !strings.Contains(out, s) {
+					t.Errorf("Output %q does not contain %q", out, s)
+				}
+			}
+			if strings.Contains(out, "with details") {
+				t.Errorf("Table shows more than the first line of descriptions: %q", out)
+			}
+		})
+	}
+
+	for _, args := range [][]string{
+		{"unknown"},
+		{"tools"},
+		{"tools", "call"},
+		{"tools", "call", "echo", "--json", "[1]"},
+		{"tools", "call", "echo", "--arg", "novalue"},
+		{"resources", "read"},
+	} {
+		if _, err := runCommand(t, server.URL, "", args...); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestRunLogLevelWithoutLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     interface{} `json:"id"`
+			Method string      `json:"method"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if req.Method == "logging/setLevel" {
+			t.Error("logging/setLevel sent to a server without logging")
+		}
+		result := map[string]interface{}{}
+		if req.Method == "initialize" {
+			result = map[string]interface{}{
+				"protocolVersion": "2025-06-18",
+				"capabilities":    map[string]interface{}{},
+				"serverInfo":      map[string]interface{}{"name": "quiet-server", "version": "1.0.0"},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	defer server.Close()
+
+	out, err := runCommand(t, server.URL, "", "log-level", "debug")
+	if err == nil || !strings.Contains(err.Error(), "does not support logging") {
+		t.Errorf("run returned %v, expected a logging error", err)
+	}
+	if strings.Contains(out, "log level set") {
+		t.Errorf("Output %q reports a level change", out)
+	}
+}
+
+func TestRunJSONOutput(t *testing.T) {
+	server := newTestServer(t)
+
+	out, err := runCommand(t, server.URL, "", "-o", "json", "tools", "list")
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	var tools []map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &tools); err != nil {
+		t.Fatalf("Output is not JSON: %v\n%s", err, out)
+	}
+	if len(tools) != 2 || tools[0]["name"] != "echo" {
+		t.Errorf("Unexpected tools: %v", tools)
+	}
+
+	if _, err := runCommand(t, server.URL, "", "-o", "yaml", "ping"); err == nil {
+		t.Error("Expected an error for an unknown output format")
+	}
+}
+
+func TestRunTargets(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	for _, args := range [][]string{
+		{"ping"},
+		{"-url", "http://localhost", "-stdio", "server", "ping"},
+		{"-stdio", "'unterminated", "ping"},
+	} {
+		if err := run(args, strings.NewReader(""), &stdout, &stderr); err == nil {
+			t.Errorf("%v: expected an error", args)
+		}
+	}
+}
+
+func TestREPL(t *testing.T) {
+	server := newTestServer(t)
+
+	out, err := runCommand(t, server.URL, "tools call echo --arg 'text=two words'\nbogus\nhelp\nping\nexit\nping\n")
+	if err != nil {
+		t.Fatalf("REPL failed: %v", err)
+	}
+
+	for _, s := range []string{"Connected to test-server 2.0.0", "two words", `error: unknown command "bogus"`, "tools list", "ok"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("Output %q does not contain %q", out, s)
+		}
+	}
+	if strings.Count(out, "ok\n") != 1 {
+		t.Errorf("Expected the REPL to stop at exit: %q", out)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	server := newTestServer(t)
+
+	var stderr bytes.Buffer
+	target := target{url: server.URL, headers: headerFlags{}}
+	c, err := target.connect()
+	if err != nil {
+		t.Fatalf("connect failed: %v", err)
+	}
+	defer c.Close()
+	cli := &cli{client: c, out: &stderr, format: formatTable}
+
+	tests := map[string]string{
+		"":                 "connect exit help log-level ping prompts quit resources tools",
+		"to":               "tools",
+		"tools ":           "list call",
+		"tools c":          "call",
+		"tools call ":      "echo echo_json",
+		"tools call echo_": "echo_json",
+		"prompts get r":    "review",
+		"resources read f": "file:///readme.md",
+		"tools call echo ": "--arg --json",
+		"log-level w":      "warning",
+		"ping x":           "",
+	}
+	for line, expected := range tests {
+		if got := strings.Join(cli.complete(line), " "); got != expected {
+			t.Errorf("complete(%q) = %q, expected %q", line, got, expected)
+		}
+	}
+}
+
+func TestLineEditor(t *testing.T) {
+	complete := func(line string) []string {
+		words := strings.Fields(line)
+		var prefix string
+		if len(words) > 0 && !strings.HasSuffix(line, " ") {
+			prefix = words[len(words)-1]
+		}
+		var matches []string
+		for _, candidate := range []string{"tools", "echo", "echo_json"} {
+			if strings.HasPrefix(candidate, prefix) {
+				matches = append(matches, candidate)
+			}
+		}
+		return matches
+	}
+
+	input := "to\tec\t_\t\r" + // completion of a unique word, a common prefix and a unique word
+		"ping\x7f\x7f\x7f\x7fx\x15help\r" + // backspace and Ctrl-U
+		"\x1b[A\x1b[A\r" + // history
+		"one two\x17three\r" + // Ctrl-W
+		"\x04"
+	var out bytes.Buffer
+	editor := newLineEditor(strings.NewReader(input), &out, "> ", complete)
+	editor.editing = true
+
+	var lines []string
+	for {
+		line, err := editor.readLine()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("readLine failed: %v", err)
+		}
+		lines = append(lines, line)
+	}
+
+	expected := []string{"tools echo_json ", "help", "tools echo_json ", "one three"}
+	if fmt.Sprint(lines) != fmt.Sprint(expected) {
+		t.Errorf("Lines = %q, expected %q", lines, expected)
+	}
+}
+
+func TestToolArguments(t *testing.T) {
+	arguments, err := toolArguments(`{"a": 1, "b": "x"}`, []string{"b=y", "n=2.5", "flag=true", "list=[1,2]", "s=plain text", "empty="})
+	if err != nil {
+		t.Fatalf("toolArguments failed: %v", err)
+	}
+
+	data, _ := json.Marshal(arguments)
+	expected := `{"a":1,"b":"y","empty":"","flag":true,"list":[1,2],"n":2.5,"s":"plain text"}`
+	if string(data) != expected {
+		t.Errorf("toolArguments = %s, expected %s", data, expected)
+	}
+}
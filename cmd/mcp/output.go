@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+)
+
+// maxSummary is the longest description shown in tables
+const maxSummary = 80
+
+// writeJSON writes v as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeTable writes rows as aligned columns under an optional header
+func writeTable(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	if header != nil {
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+	}
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// writeContents writes content blocks for reading: text as is and other
+// content as a one-line placeholder
+func writeContents(w io.Writer, contents []interface{}) error {
+	for _, item := range contents {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return err
+		}
+		var content struct {
+			Type     string `json:"type"`
+			Text     string `json:"text"`
+			Data     string `json:"data"`
+			MimeType string `json:"mimeType"`
+			URI      string `json:"uri"`
+			Resource *struct {
+				URI string `json:"uri"`
+			} `json:"resource"`
+		}
+		if err := json.Unmarshal(data, &content); err != nil {
+			return err
+		}
+
+		switch content.Type {
+		case "text":
+			fmt.Fprintln(w, content.Text)
+		case "image", "audio":
+			fmt.Fprintf(w, "[%s %s, %d bytes base64]\n", content.Type, content.MimeType, len(content.Data))
+		case "resource_link":
+			fmt.Fprintf(w, "[resource link %s]\n", content.URI)
+		case "resource":
+			uri := ""
+			if content.Resource != nil {
+				uri = content.Resource.URI
+			}
+			fmt.Fprintf(w, "[resource %s]\n", uri)
+		default:
+			fmt.Fprintln(w, string(data))
+		}
+	}
+	return nil
+}
+
+// summary returns the first line of a description, shortened for tables
+func summary(description string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(description), "\n")
+	if runes := []rune(line); len(runes) > maxSummary {
+		line = string(runes[:maxSummary-3]) + "..."
+	}
+	return line
+}
+
+// sortedStrings sorts s in place and returns it
+func sortedStrings(s []string) []string {
+	sort.Strings(s)
+	return s
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Convict3d/mcp-go/internal/cmdline"
+)
+
+// replPrompt is shown before every REPL line
+const replPrompt = "mcp> "
+
+// completionTimeout bounds the catalog lookups made for tab completion
+const completionTimeout = 5 * time.Second
+
+// subcommands lists the words accepted after each command
+var subcommands = map[string][]string{
+	"tools":     {"list", "call"},
+	"resources": {"list", "read"},
+	"prompts":   {"list", "get"},
+	"log-level": {"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"},
+}
+
+// commands lists the words accepted first in the REPL
+var commands = []string{"connect", "exit", "help", "log-level", "ping", "prompts", "quit", "resources", "tools"}
+
+// repl reads commands from in until EOF or exit. Notifications are printed
+// above the line being edited.
+func (c *cli) repl(in io.Reader, out io.Writer) error {
+	editor := newLineEditor(in, out, replPrompt, c.complete)
+	c.setNotify(editor.printAbove)
+
+	fmt.Fprintf(out, "Connected to %s %s. Type help for commands.\n",
+		c.client.GetServerInfo().Name, c.client.GetServerInfo().Version)
+
+	for {
+		line, err := editor.readLine()
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(out)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		args, err := cmdline.Split(line)
+		if err != nil {
+			editor.printAbove("error: " + err.Error())
+			continue
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		switch args[0] {
+		case "exit", "quit":
+			return nil
+		case "help":
+			fmt.Fprint(out, commandHelp)
+			continue
+		}
+
+		// Interrupting a command cancels it without leaving the REPL
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		err = c.execute(ctx, args)
+		stop()
+		if err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+		}
+	}
+}
+
+// complete returns the candidates for the last word of line
+func (c *cli) complete(line string) []string {
+	words := strings.Fields(line)
+	if len(words) > 0 && !strings.HasSuffix(line, " ") {
+		words = words[:len(words)-1]
+	}
+
+	var candidates []string
+	switch {
+	case len(words) == 0:
+		candidates = commands
+	case len(words) == 1:
+		candidates = subcommands[words[0]]
+	case len(words) == 2:
+		ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+		defer cancel()
+		candidates = c.names(ctx, words[0]+" "+words[1])
+	case words[0] == "tools" || words[0] == "prompts":
+		candidates = []string{"--arg"}
+		if words[0] == "tools" {
+			candidates = append(candidates, "--json")
+		}
+	}
+
+	var prefix string
+	if fields := strings.Fields(line); len(fields) > len(words) {
+		prefix = fields[len(fields)-1]
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, prefix) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches
+}
+
+// names returns the tool, prompt or resource names a command takes, from the
+// client's catalog
+func (c *cli) names(ctx context.Context, command string) []string {
+	catalog := c.client.Catalog()
+	if catalog == nil {
+		return nil
+	}
+
+	var names []string
+	switch command {
+	case "tools call":
+		if !c.client.HasTools() {
+			return nil
+		}
+		tools, _ := catalog.Tools(ctx)
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+	case "prompts get":
+		if !c.client.HasPrompts() {
+			return nil
+		}
+		prompts, _ := catalog.Prompts(ctx)
+		for _, prompt := range prompts {
+			names = append(names, prompt.Name)
+		}
+	case "resources read":
+		if !c.client.HasResources() {
+			return nil
+		}
+		resources, _ := catalog.Resources(ctx)
+		for _, resource := range resources {
+			names = append(names, resource.URI)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/Convict3d/mcp-go/client"
+	"github.com/Convict3d/mcp-go/transport"
+	"github.com/Convict3d/mcp-go/transport/http"
+	"github.com/Convict3d/mcp-go/transport/stdio"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// Kind identifies how a server is reached
+type Kind string
+
+const (
+	// KindStdio servers are commands that speak MCP over stdin and stdout
+	KindStdio Kind = "stdio"
+	// KindHTTP servers are reached over Streamable HTTP
+	KindHTTP Kind = "http"
+)
+
+// Config is a configuration file in the mcpServers format
+type Config struct {
+	MCPServers map[string]Server `json:"mcpServers"`
+}
+
+// Server describes one MCP server. Exactly one of Command and URL must be set.
+type Server struct {
+	// Type optionally states the kind of server: "stdio", "http" or
+	// "streamable-http". It is inferred from Command and URL when empty.
+	Type string `json:"type,omitempty"`
+
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// Option configures loading and parsing
+type Option func(*options)
+
+type options struct {
+	lookup func(string) (string, bool)
+}
+
+// WithLookup sets the function used to resolve ${VAR} references.
+// The default is os.LookupEnv.
+func WithLookup(lookup func(name string) (string, bool)) Option {
+	return func(o *options) {
+		o.lookup = lookup
+	}
+}
+
+// Load reads, expands and validates a configuration file
+func Load(path string, opts ...Option) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	config, err := Parse(data, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Parse decodes a configuration, expands ${VAR} references and validates
+// every server. All invalid servers are reported in the returned error.
+func Parse(data []byte, opts ...Option) (*Config, error) {
+	o := &options{lookup: os.LookupEnv}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	if config.MCPServers == nil {
+		return nil, errors.New("invalid config: missing mcpServers")
+	}
+
+	var errs []error
+	for _, name := range config.Names() {
+		server, err := config.MCPServers[name].expand(o.lookup)
+		if err == nil {
+			err = server.Validate()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("server %q: %w", name, err))
+			continue
+		}
+		config.MCPServers[name] = server
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return &config, nil
+}
+
+// Names returns the server names in sorted order
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.MCPServers))
+	for name := range c.MCPServers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Connect creates an initialized client for the named server. See Server.Connect.
+func (c *Config) Connect(name string, opts ...client.Option) (*client.Client, error) {
+	server, ok := c.MCPServers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown server %q", name)
+	}
+
+	cl, err := server.Connect(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("server %q: %w", name, err)
+	}
+	return cl, nil
+}
+
+// Kind returns how the server is reached
+func (s Server) Kind() Kind {
+	switch s.Type {
+	case "http", "streamable-http":
+		return KindHTTP
+	case "stdio":
+		return KindStdio
+	}
+	if s.URL != "" {
+		return KindHTTP
+	}
+	return KindStdio
+}
+
+// Validate checks that the server entry is complete and consistent
+func (s Server) Validate() error {
+	switch s.Type {
+	case "", "stdio", "http", "streamable-http":
+	default:
+		return fmt.Errorf("unsupported type %q", s.Type)
+	}
+
+	switch {
+	case s.Command == "" && s.URL == "":
+		return errors.New("command or url is required")
+	case s.Command != "" && s.URL != "":
+		return errors.New("command and url are mutually exclusive")
+	}
+
+	if s.Kind() == KindHTTP {
+		if s.URL == "" {
+			return fmt.Errorf("type %q requires a url", s.Type)
+		}
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			return fmt.Errorf("invalid url: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid url %q: scheme must be http or https", s.URL)
+		}
+		if len(s.Args) > 0 || len(s.Env) > 0 {
+			return errors.New("args and env are only supported for stdio servers")
+		}
+		return nil
+	}
+
+	if s.Command == "" {
+		return fmt.Errorf("type %q requires a command", s.Type)
+	}
+	if len(s.Headers) > 0 {
+		return errors.New("headers are only supported for http servers")
+	}
+	return nil
+}
+
+// NewTransport creates the transport for the server. Stdio servers are started
+// immediately. A positive timeout sets the transport's request timeout.
+func (s Server) NewTransport(timeout time.Duration) (transport.Transport, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	if s.Kind() == KindHTTP {
+		var opts []http.Option
+		if timeout > 0 {
+			opts = append(opts, http.WithTimeout(timeout))
+		}
+		for key, value := range s.Headers {
+			opts = append(opts, http.WithHeader(key, value))
+		}
+		return http.NewHTTPTransport(s.URL, opts...), nil
+	}
+
+	var opts []stdio.Option
+	if timeout > 0 {
+		opts = append(opts, stdio.WithTimeout(timeout))
+	}
+	if len(s.Env) > 0 {
+		env := os.Environ()
+		for _, key := range sortedKeys(s.Env) {
+			env = append(env, key+"="+s.Env[key])
+		}
+		opts = append(opts, stdio.WithEnv(env))
+	}
+
+	t, err := stdio.NewTransport(s.Command, s.Args, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start %q: %w", s.Command, err)
+	}
+	return t, nil
+}
+
+// Connect creates the server's transport and a client using it, and performs
+// the initialize handshake. The transport uses the client's timeout, which
+// defaults to 30 seconds. The transport is closed if initialization fails.
+func (s Server) Connect(opts ...client.Option) (*client.Client, error) {
+	var config client.Config
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	t, err := s.NewTransport(config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := client.NewClient(append(opts, client.WithTransport(t))...)
+	if err := c.Initialize(types.LatestProtocolVersion); err != nil {
+		t.Close()
+		return nil, fmt.Errorf("initialize failed: %w", err)
+	}
+	return c, nil
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
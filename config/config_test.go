@@ -0,0 +1,234 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Convict3d/mcp-go/client"
+	mcphttp "github.com/Convict3d/mcp-go/transport/http"
+)
+
+// env returns a lookup function backed by vars
+func env(vars map[string]string) Option {
+	return WithLookup(func(name string) (string, bool) {
+		value, ok := vars[name]
+		return value, ok
+	})
+}
+
+func TestParse(t *testing.T) {
+	data := []byte(`{
+		"mcpServers": {
+			"filesystem": {
+				"command": "npx",
+				"args": ["-y", "server-filesystem", "${HOME}/data"],
+				"env": {"LOG_LEVEL": "${LOG_LEVEL:-info}"}
+			},
+			"github": {
+				"type": "http",
+				"url": "https://${HOST}/mcp",
+				"headers": {"Authorization": "Bearer ${TOKEN}"}
+			}
+		}
+	}`)
+
+	config, err := Parse(data, env(map[string]string{
+		"HOME":  "/home/dev",
+		"HOST":  "api.example.com",
+		"TOKEN": "secret",
+	}))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if got := strings.Join(config.Names(), ","); got != "filesystem,github" {
+		t.Errorf("Names = %s", got)
+	}
+
+	fs := config.MCPServers["filesystem"]
+	if fs.Kind() != KindStdio {
+		t.Errorf("Expected a stdio server, got %s", fs.Kind())
+	}
+	if fs.Args[2] != "/home/dev/data" {
+		t.Errorf("Args not expanded: %v", fs.Args)
+	}
+	if fs.Env["LOG_LEVEL"] != "info" {
+		t.Errorf("Default not applied: %v", fs.Env)
+	}
+
+	github := config.MCPServers["github"]
+	if github.Kind() != KindHTTP {
+		t.Errorf("Expected an http server, got %s", github.Kind())
+	}
+	if github.URL != "https://api.example.com/mcp" {
+		t.Errorf("URL not expanded: %s", github.URL)
+	}
+	if github.Headers["Authorization"] != "Bearer secret" {
+		t.Errorf("Headers not expanded: %v", github.Headers)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		contains []string
+	}{
+		{"invalid json", `{"mcpServers":`, []string{"invalid config"}},
+		{"missing mcpServers", `{"servers": {}}`, []string{"missing mcpServers"}},
+		{"empty entry", `{"mcpServers": {"a": {}}}`, []string{`server "a"`, "command or url is required"}},
+		{"command and url", `{"mcpServers": {"a": {"command": "x", "url": "http://x"}}}`, []string{"mutually exclusive"}},
+		{"bad scheme", `{"mcpServers": {"a": {"url": "ftp://x"}}}`, []string{"scheme must be http or https"}},
+		{"unknown type", `{"mcpServers": {"a": {"type": "ws", "url": "http://x"}}}`, []string{`unsupported type "ws"`}},
+		{"type mismatch", `{"mcpServers": {"a": {"type": "http", "command": "x"}}}`, []string{"requires a url"}},
+		{"headers on stdio", `{"mcpServers": {"a": {"command": "x", "headers": {"A": "b"}}}}`, []string{"only supported for http"}},
+		{"env on http", `{"mcpServers": {"a": {"url": "http://x", "env": {"A": "b"}}}}`, []string{"only supported for stdio"}},
+		{"missing variables", `{"mcpServers": {"a": {"command": "${CMD}", "args": ["${ARG}", "${CMD}"]}}}`, []string{"undefined environment variables: ARG, CMD"}},
+		{
+			"every server reported",
+			`{"mcpServers": {"a": {}, "b": {"url": "ftp://x"}}}`,
+			[]string{`server "a"`, `server "b"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data), env(nil))
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			for _, s := range tt.contains {
+				if !strings.Contains(err.Error(), s) {
+					t.Errorf("Error %q does not contain %q", err, s)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandEnv(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		value, ok := map[string]string{"USER": "dev", "EMPTY": ""}[name]
+		return value, ok
+	}
+
+	tests := map[string]string{
+		"plain":                "plain",
+		"${USER}":              "dev",
+		"a-${USER}-b":          "a-dev-b",
+		"${EMPTY}":             "",
+		"${EMPTY:-fallback}":   "fallback",
+		"${UNSET:-fallback}":   "fallback",
+		"${UNSET:-}":           "",
+		"${USER}/${USER}":      "dev/dev",
+		"$USER and ${":         "$USER and ${",
+		"${USER:-unused} done": "dev done",
+	}
+	for input, expected := range tests {
+		got, err := ExpandEnv(input, lookup)
+		if err != nil {
+			t.Errorf("ExpandEnv(%q) failed: %v", input, err)
+			continue
+		}
+		if got != expected {
+			t.Errorf("ExpandEnv(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+
+	if _, err := ExpandEnv("${UNSET}", lookup); err == nil {
+		t.Error("Expected an error for an unset variable")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"mcpServers": {"a": {"command": "${CMD}"}}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := Load(path, env(map[string]string{"CMD": "server"}))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if config.MCPServers["a"].Command != "server" {
+		t.Errorf("Unexpected command: %q", config.MCPServers["a"].Command)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestNewTransport(t *testing.T) {
+	server := Server{URL: "http://localhost:9000/mcp"}
+	tr, err := server.NewTransport(0)
+	if err != nil {
+		t.Fatalf("NewTransport failed: %v", err)
+	}
+	if _, ok := tr.(*mcphttp.HTTPTransport); !ok {
+		t.Errorf("Expected an HTTP transport, got %T", tr)
+	}
+
+	if _, err := (Server{}).NewTransport(0); err == nil {
+		t.Error("Expected an error for an invalid server")
+	}
+	if _, err := (Server{Command: "/nonexistent/mcp-server"}).NewTransport(0); err == nil {
+		t.Error("Expected an error for a missing command")
+	}
+}
+
+func TestConnect(t *testing.T) {
+	var authorization string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+
+		var req struct {
+			ID     interface{} `json:"id"`
+			Method string      `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result": map[string]interface{}{
+				"protocolVersion": "2025-06-18",
+				"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}},
+				"serverInfo":      map[string]interface{}{"name": "test-server", "version": "1.0.0"},
+			},
+		})
+	}))
+	defer ts.Close()
+
+	config, err := Parse([]byte(`{"mcpServers": {"remote": {"url": "`+ts.URL+`", "headers": {"Authorization": "Bearer ${TOKEN}"}}}}`),
+		env(map[string]string{"TOKEN": "secret"}))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	c, err := config.Connect("remote", client.WithClientInfo("config-test", "1.0.0"))
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer c.Close()
+
+	if c.GetServerInfo().Name != "test-server" {
+		t.Errorf("Unexpected server info: %+v", c.GetServerInfo())
+	}
+	if !c.HasTools() {
+		t.Error("Expected the tools capability")
+	}
+	if authorization != "Bearer secret" {
+		t.Errorf("Authorization header = %q", authorization)
+	}
+
+	if _, err := config.Connect("missing"); err == nil {
+		t.Error("Expected an error for an unknown server")
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Package config loads MCP server definitions from the "mcpServers" JSON format
+used by desktop MCP clients such as claude_desktop_config.json.
+
+A configuration file maps server names to either a command started over stdio
+or the URL of a Streamable HTTP server:
+
+	{
+	  "mcpServers": {
+	    "filesystem": {
+	      "command": "npx",
+	      "args": ["-y", "@modelcontextprotocol/server-filesystem", "${HOME}/data"],
+	      "env": {"LOG_LEVEL": "${LOG_LEVEL:-info}"}
+	    },
+	    "github": {
+	      "url": "https://api.example.com/mcp",
+	      "headers": {"Authorization": "Bearer ${GITHUB_TOKEN}"}
+	    }
+	  }
+	}
+
+# Loading
+
+Load reads, expands and validates a file:
+
+	cfg, err := config.Load("claude_desktop_config.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, name := range cfg.Names() {
+		fmt.Println(name, cfg.MCPServers[name].Kind())
+	}
+
+References of the form ${VAR} in commands, arguments, environment values, URLs
+and headers are replaced with environment variables. ${VAR:-default} uses the
+default when the variable is unset or empty. References to unset variables
+without a default are reported as errors. Use WithLookup to resolve variables
+from somewhere other than the process environment.
+
+# Connecting
+
+Each server entry builds its transport and an initialized client:
+
+	c, err := cfg.Connect("github",
+		client.WithClientInfo("my-app", "1.0.0"),
+		client.WithTimeout(30*time.Second),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer c.Close()
+
+Stdio servers inherit the environment of the current process, extended with
+the entry's env. HTTP servers receive the entry's headers on every request.
+*/
+package config
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// expand returns a copy of the server with ${VAR} references expanded in
+// every string field
+func (s Server) expand(lookup func(string) (string, bool)) (Server, error) {
+	e := &expander{lookup: lookup, missing: make(map[string]bool)}
+
+	expanded := Server{
+		Type:    s.Type,
+		Command: e.expand(s.Command),
+		URL:     e.expand(s.URL),
+	}
+	if s.Args != nil {
+		expanded.Args = make([]string, len(s.Args))
+		for i, arg := range s.Args {
+			expanded.Args[i] = e.expand(arg)
+		}
+	}
+	expanded.Env = e.expandMap(s.Env)
+	expanded.Headers = e.expandMap(s.Headers)
+
+	if err := e.err(); err != nil {
+		return Server{}, err
+	}
+	return expanded, nil
+}
+
+// ExpandEnv replaces ${VAR} and ${VAR:-default} references in s using lookup.
+// It returns an error naming every referenced variable that is unset and has
+// no default.
+func ExpandEnv(s string, lookup func(name string) (string, bool)) (string, error) {
+	e := &expander{lookup: lookup, missing: make(map[string]bool)}
+	expanded := e.expand(s)
+	if err := e.err(); err != nil {
+		return "", err
+	}
+	return expanded, nil
+}
+
+// expander expands references and collects the names of missing variables
+type expander struct {
+	lookup  func(string) (string, bool)
+	missing map[string]bool
+}
+
+func (e *expander) expand(s string) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			b.WriteString(s)
+			return b.String()
+		}
+		end := strings.IndexByte(s[start:], '}')
+		if end < 0 {
+			// An unterminated reference is kept literally
+			b.WriteString(s)
+			return b.String()
+		}
+		end += start
+
+		b.WriteString(s[:start])
+		b.WriteString(e.resolve(s[start+2 : end]))
+		s = s[end+1:]
+	}
+}
+
+// resolve returns the value of a reference of the form NAME or NAME:-default
+func (e *expander) resolve(ref string) string {
+	name, def, hasDefault := strings.Cut(ref, ":-")
+
+	value, ok := e.lookup(name)
+	if ok && value != "" {
+		return value
+	}
+	if hasDefault {
+		return def
+	}
+	if !ok {
+		e.missing[name] = true
+	}
+	return value
+}
+
+func (e *expander) expandMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	expanded := make(map[string]string, len(m))
+	for key, value := range m {
+		expanded[key] = e.expand(value)
+	}
+	return expanded
+}
+
+// err reports the missing variables, if any
+func (e *expander) err() error {
+	if len(e.missing) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(e.missing))
+	for name := range e.missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("undefined environment variables: %s", strings.Join(names, ", "))
+}
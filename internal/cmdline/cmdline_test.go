@@ -0,0 +1,35 @@
+package cmdline
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := map[string][]string{
+		`tools list`:                    {"tools", "list"},
+		`  tools   call  echo `:         {"tools", "call", "echo"},
+		`--arg 'text=two words'`:        {"--arg", "text=two words"},
+		`--json "{\"a\": 1}"`:           {"--json", `{"a": 1}`},
+		`--json '{"a": "b c"}'`:         {"--json", `{"a": "b c"}`},
+		`a\ b c`:                        {"a b", "c"},
+		`''`:                            {""},
+		`read "file:///My Documents/x"`: {"read", "file:///My Documents/x"},
+	}
+	for line, expected := range tests {
+		got, err := Split(line)
+		if err != nil {
+			t.Errorf("Split(%q) failed: %v", line, err)
+			continue
+		}
+		if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", expected) {
+			t.Errorf("Split(%q) = %q, expected %q", line, got, expected)
+		}
+	}
+
+	for _, line := range []string{`'open`, `"open`, `trailing\`} {
+		if _, err := Split(line); err == nil {
+			t.Errorf("Split(%q): expected an error", line)
+		}
+	}
+}
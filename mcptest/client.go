@@ -0,0 +1,367 @@
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Convict3d/mcp-go/client"
+	"github.com/Convict3d/mcp-go/schema"
+	"github.com/Convict3d/mcp-go/transport"
+	"github.com/Convict3d/mcp-go/transport/stdio"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// ClientTarget starts the client under test against the suite's fake server.
+// StartClient is called for every requirement with the streams of a fresh
+// connection: the client reads the server's newline-delimited JSON-RPC
+// messages from r and writes its own to w. It must initialize the connection,
+// answer the server's requests until ctx is done and then return.
+type ClientTarget interface {
+	StartClient(ctx context.Context, r io.ReadCloser, w io.WriteCloser) error
+}
+
+// ClientTargetFunc adapts a function to a ClientTarget
+type ClientTargetFunc func(ctx context.Context, r io.ReadCloser, w io.WriteCloser) error
+
+// StartClient calls f
+func (f ClientTargetFunc) StartClient(ctx context.Context, r io.ReadCloser, w io.WriteCloser) error {
+	return f(ctx, r, w)
+}
+
+// Client returns a target running a client of this library. newClient creates
+// the client with the given transport and the capabilities and request
+// handlers under test; the target initializes it and closes it once the
+// requirement has been checked.
+func Client(newClient func(t transport.Transport) *client.Client) ClientTarget {
+	return ClientTargetFunc(func(ctx context.Context, r io.ReadCloser, w io.WriteCloser) error {
+		t, err := stdio.NewTransportFromStreams(w, r, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create transport: %w", err)
+		}
+		c := newClient(t)
+		defer c.Close()
+
+		if err := c.Initialize(types.LatestProtocolVersion); err != nil {
+			return fmt.Errorf("initialize failed: %w", err)
+		}
+		<-ctx.Done()
+		return nil
+	})
+}
+
+// clientRequirement is a client Requirement with the check that verifies it
+type clientRequirement struct {
+	Requirement
+	check func(ctx context.Context, s *clientSession) outcome
+}
+
+// clientRequirements are checked in order by RunClient
+var clientRequirements = []clientRequirement{
+	{Requirement{"client/initialize", "initialize carries a released protocol version, client info and capabilities"}, checkClientInitialize},
+	{Requirement{"client/initialized", "notifications/initialized follows the initialize response"}, checkClientInitialized},
+	{Requirement{"client/ping", "ping from the server returns an empty result"}, checkClientPing},
+	{Requirement{"client/sampling", "sampling/createMessage returns a message with a role, valid content and a model when sampling is declared"}, checkClientSampling},
+	{Requirement{"client/elicitation", "elicitation/create returns an action and, when accepted, content matching the requested schema when elicitation is declared"}, checkClientElicitation},
+	{Requirement{"client/roots", "roots/list returns file:// roots when roots are declared"}, checkClientRoots},
+}
+
+// ClientRequirements returns the requirements RunClient checks, in order
+func ClientRequirements() []Requirement {
+	reqs := make([]Requirement, len(clientRequirements))
+	for i, req := range clientRequirements {
+		reqs[i] = req.Requirement
+	}
+	return reqs
+}
+
+// RunClient checks every client requirement against target and returns the
+// report. Each requirement starts the client on a fresh connection to a fake
+// server that advertises no capabilities, answers initialize and ping, and
+// sends the requests being checked. WithTimeout applies; the other options
+// only concern servers.
+func RunClient(ctx context.Context, target ClientTarget, opts ...Option) *Report {
+	cfg := &config{timeout: 5 * time.Second, maxItems: 20}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	report := &Report{}
+	for _, req := range clientRequirements {
+		result := Result{Requirement: req.Requirement}
+		o := runClientRequirement(ctx, target, cfg, req)
+		result.Status, result.Detail = o.status, o.detail
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+// clientInitialize holds the params of the client's initialize request
+type clientInitialize struct {
+	ProtocolVersion string                   `json:"protocolVersion"`
+	Capabilities    json.RawMessage          `json:"capabilities"`
+	ClientInfo      types.Implementation     `json:"clientInfo"`
+	caps            types.ClientCapabilities // Capabilities decoded
+}
+
+// clientSession is the fake server's side of a connection to the client
+// under test
+type clientSession struct {
+	*peer
+	config *config
+
+	mu           sync.Mutex
+	params       *clientInitialize // nil until the client sends initialize
+	paramsErr    error             // the initialize params did not decode
+	early        bool              // notifications/initialized preceded initialize
+	initializing chan struct{}     // closed when initialize is answered
+}
+
+// runClientRequirement starts the client on a new connection, waits for its
+// handshake and checks one requirement
+func runClientRequirement(ctx context.Context, target ClientTarget, cfg *config, req clientRequirement) outcome {
+	serverR, clientW := io.Pipe()
+	clientR, serverW := io.Pipe()
+	s := &clientSession{config: cfg, initializing: make(chan struct{})}
+	s.peer = newPeer(newStreamConn(serverR, serverW), cfg.timeout, s.answerRequest)
+
+	clientCtx, cancel := context.WithCancel(ctx)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- target.StartClient(clientCtx, clientR, clientW)
+	}()
+	defer func() {
+		// Hang up first so the client reads to the end of its input
+		// instead of having it closed under it
+		serverW.Close()
+		cancel()
+		select {
+		case <-stopped:
+		case <-time.After(cfg.timeout):
+		}
+		serverR.Close()
+		s.close()
+	}()
+
+	timer := time.NewTimer(cfg.timeout)
+	defer timer.Stop()
+	select {
+	case <-s.initializing:
+	case err := <-stopped:
+		stopped <- err
+		return fail("client stopped before initializing: %v", err)
+	case <-timer.C:
+		return fail("client did not send initialize within %s", cfg.timeout)
+	case <-ctx.Done():
+		return fail("%v", ctx.Err())
+	}
+
+	// Requests to the client belong after the handshake; a missing
+	// notifications/initialized is reported by its own requirement
+	s.waitNotification(ctx, "notifications/initialized")
+	return req.check(ctx, s)
+}
+
+// answerRequest answers the requests of the client under test as a server
+// without capabilities
+func (s *clientSession) answerRequest(msg message) (interface{}, *rpcError) {
+	switch msg.Method {
+	case "initialize":
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.params != nil {
+			return nil, &rpcError{Code: codeInvalidRequest, Message: "session is already initialized"}
+		}
+
+		params := &clientInitialize{}
+		if err := json.Unmarshal(msg.Params, params); err != nil {
+			s.paramsErr = err
+		} else if err := json.Unmarshal(params.Capabilities, &params.caps); err != nil {
+			s.paramsErr = fmt.Errorf("invalid capabilities: %w", err)
+		}
+		s.params = params
+		s.early = len(s.notificationsFor("notifications/initialized")) > 0
+		close(s.initializing)
+
+		version := params.ProtocolVersion
+		if !knownVersions[version] {
+			version = types.LatestProtocolVersion
+		}
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{},
+			"serverInfo":      map[string]interface{}{"name": "mcptest", "version": "1.0.0"},
+		}, nil
+	case "ping":
+		return struct{}{}, nil
+	}
+	return nil, &rpcError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+}
+
+// initializeParams returns the params of the client's initialize request
+func (s *clientSession) initializeParams() (*clientInitialize, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.params, s.paramsErr
+}
+
+// waitNotification waits until a notification with the given method has been
+// received and reports whether it was
+func (s *clientSession) waitNotification(ctx context.Context, method string) bool {
+	deadline := time.Now().Add(s.timeout)
+	for len(s.notificationsFor(method)) == 0 {
+		if time.Now().After(deadline) || ctx.Err() != nil {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return true
+}
+
+func checkClientInitialize(ctx context.Context, s *clientSession) outcome {
+	params, err := s.initializeParams()
+	if err != nil {
+		return fail("invalid initialize params: %v", err)
+	}
+	if !knownVersions[params.ProtocolVersion] {
+		return fail("client asked for protocol version %q, expected a released version", params.ProtocolVersion)
+	}
+	if params.ClientInfo.Name == "" || params.ClientInfo.Version == "" {
+		return fail("clientInfo needs a name and a version, got %+v", params.ClientInfo)
+	}
+	if !isObject(params.Capabilities) {
+		return fail("capabilities must be an object, got %s", params.Capabilities)
+	}
+	return pass("%s %s", params.ClientInfo.Name, params.ClientInfo.Version)
+}
+
+func checkClientInitialized(ctx context.Context, s *clientSession) outcome {
+	s.mu.Lock()
+	early := s.early
+	s.mu.Unlock()
+	if early {
+		return fail("notifications/initialized was sent before initialize")
+	}
+	if len(s.notificationsFor("notifications/initialized")) == 0 {
+		return fail("no notifications/initialized within %s of the initialize response", s.timeout)
+	}
+	return pass("")
+}
+
+func checkClientPing(ctx context.Context, s *clientSession) outcome {
+	data, err := s.request(ctx, "ping", nil)
+	if err != nil {
+		return fail("ping failed: %v", err)
+	}
+	if !isObject(data) {
+		return fail("ping result must be an object, got %s", data)
+	}
+	return pass("")
+}
+
+func checkClientSampling(ctx context.Context, s *clientSession) outcome {
+	params, _ := s.initializeParams()
+	if params.caps.Sampling == nil {
+		return skip("capability not declared")
+	}
+
+	var result struct {
+		Role    string          `json:"role"`
+		Content json.RawMessage `json:"content"`
+		Model   string          `json:"model"`
+	}
+	err := s.call(ctx, "sampling/createMessage", map[string]interface{}{
+		"messages": []interface{}{map[string]interface{}{
+			"role":    "user",
+			"content": map[string]interface{}{"type": types.ContentTypeText, "text": "Reply with one word."},
+		}},
+		"maxTokens": 16,
+	}, &result)
+	if err != nil {
+		return fail("sampling/createMessage failed: %v", err)
+	}
+	if result.Role != string(types.RoleUser) && result.Role != string(types.RoleAssistant) {
+		return fail("sampling result has role %q, expected user or assistant", result.Role)
+	}
+	typ, err := validateContent(result.Content)
+	if err != nil {
+		return fail("sampling result content: %v", err)
+	}
+	switch typ {
+	case types.ContentTypeText, types.ContentTypeImage, types.ContentTypeAudio:
+	default:
+		return fail("sampling result has %s content, expected text, image or audio", typ)
+	}
+	if result.Model == "" {
+		return fail("sampling result needs the model that produced it")
+	}
+	return pass("model %s", result.Model)
+}
+
+// elicitationSchema is the schema of the information the suite asks for
+var elicitationSchema = map[string]interface{}{
+	"type":       "object",
+	"properties": map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+	"required":   []interface{}{"name"},
+}
+
+func checkClientElicitation(ctx context.Context, s *clientSession) outcome {
+	params, _ := s.initializeParams()
+	if params.caps.Elicitation == nil {
+		return skip("capability not declared")
+	}
+
+	var result struct {
+		Action  string          `json:"action"`
+		Content json.RawMessage `json:"content"`
+	}
+	err := s.call(ctx, "elicitation/create", map[string]interface{}{
+		"message":         "What is your name?",
+		"requestedSchema": elicitationSchema,
+	}, &result)
+	if err != nil {
+		return fail("elicitation/create failed: %v", err)
+	}
+
+	switch result.Action {
+	case "accept":
+		var content map[string]interface{}
+		if err := json.Unmarshal(result.Content, &content); err != nil || content == nil {
+			return fail("accepted elicitation needs content, got %s", result.Content)
+		}
+		if err := schema.Validate(elicitationSchema, content); err != nil {
+			return fail("accepted content does not match the requested schema: %v", err)
+		}
+	case "decline", "cancel":
+	default:
+		return fail("elicitation result has action %q, expected accept, decline or cancel", result.Action)
+	}
+	return pass("action %s", result.Action)
+}
+
+func checkClientRoots(ctx context.Context, s *clientSession) outcome {
+	params, _ := s.initializeParams()
+	if params.caps.Roots == nil {
+		return skip("capability not declared")
+	}
+
+	var result struct {
+		Roots []types.Root `json:"roots"`
+	}
+	if err := s.call(ctx, "roots/list", nil, &result); err != nil {
+		return fail("roots/list failed: %v", err)
+	}
+	if result.Roots == nil {
+		return fail("roots/list result has no roots array")
+	}
+	for _, root := range result.Roots {
+		if !strings.HasPrefix(root.URI, "file://") {
+			return fail("root %q is not a file:// URI", root.URI)
+		}
+	}
+	return pass("%d roots", len(result.Roots))
+}
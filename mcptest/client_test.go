@@ -0,0 +1,101 @@
+package mcptest
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Convict3d/mcp-go/client"
+	"github.com/Convict3d/mcp-go/transport"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// newConformingClient returns a target for a client answering every request
+// the suite sends
+func newConformingClient() ClientTarget {
+	return Client(func(t transport.Transport) *client.Client {
+		return client.NewClient(
+			client.WithTransport(t),
+			client.WithClientInfo("conforming", "1.0.0"),
+			client.WithClientCapabilities(types.ClientCapabilities{
+				Sampling:    &types.SamplingCapability{},
+				Elicitation: &types.ElicitationCapability{},
+				Roots:       &types.RootsCapability{},
+			}),
+			client.WithRequestHandler("sampling/createMessage", func(ctx context.Context, params interface{}) (interface{}, error) {
+				return map[string]interface{}{
+					"role":    "assistant",
+					"content": map[string]interface{}{"type": "text", "text": "Hello"},
+					"model":   "test-model",
+				}, nil
+			}),
+			client.WithRequestHandler("elicitation/create", func(ctx context.Context, params interface{}) (interface{}, error) {
+				return map[string]interface{}{"action": "accept", "content": map[string]interface{}{"name": "Ada"}}, nil
+			}),
+			client.WithRequestHandler("roots/list", func(ctx context.Context, params interface{}) (interface{}, error) {
+				return map[string]interface{}{"roots": []interface{}{map[string]interface{}{"uri": "file:///home/ada/project"}}}, nil
+			}),
+		)
+	})
+}
+
+func TestRunClient(t *testing.T) {
+	report := RunClient(context.Background(), newConformingClient(), WithTimeout(2*time.Second))
+	if len(report.Results) != len(ClientRequirements()) {
+		t.Fatalf("Results = %d, expected %d", len(report.Results), len(ClientRequirements()))
+	}
+	for _, result := range report.Results {
+		if result.Status != StatusPass {
+			t.Errorf("%s: %s %s", result.ID, result.Status, result.Detail)
+		}
+	}
+	if result, _ := report.Result("client/sampling"); result.Detail != "model test-model" {
+		t.Errorf("client/sampling = %+v", result)
+	}
+}
+
+func TestRunClientReportsFailures(t *testing.T) {
+	target := Client(func(t transport.Transport) *client.Client {
+		return client.NewClient(
+			client.WithTransport(t),
+			client.WithClientCapabilities(types.ClientCapabilities{Sampling: &types.SamplingCapability{}}),
+			client.WithRequestHandler("sampling/createMessage", func(ctx context.Context, params interface{}) (interface{}, error) {
+				return map[string]interface{}{"role": "assistant", "content": map[string]interface{}{"type": "text"}}, nil
+			}),
+		)
+	})
+
+	report := RunClient(context.Background(), target, WithTimeout(time.Second))
+	if report.OK() {
+		t.Fatalf("Report passed:\n%s", report)
+	}
+	expected := map[string]Status{
+		"client/initialize":  StatusPass,
+		"client/initialized": StatusPass,
+		"client/ping":        StatusPass,
+		"client/sampling":    StatusFail,
+		"client/elicitation": StatusSkip,
+		"client/roots":       StatusSkip,
+	}
+	for id, status := range expected {
+		if result, _ := report.Result(id); result.Status != status {
+			t.Errorf("%s = %+v, expected %s", id, result, status)
+		}
+	}
+}
+
+func TestRunClientWithoutHandshake(t *testing.T) {
+	target := ClientTargetFunc(func(ctx context.Context, r io.ReadCloser, w io.WriteCloser) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	report := RunClient(context.Background(), target, WithTimeout(100*time.Millisecond))
+	for _, result := range report.Results {
+		if result.Status != StatusFail || !strings.Contains(result.Detail, "did not send initialize") {
+			t.Errorf("%s = %+v, expected a failure", result.ID, result)
+		}
+	}
+}
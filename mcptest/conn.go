@@ -0,0 +1,343 @@
+package mcptest
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/Convict3d/mcp-go/server"
+	"github.com/Convict3d/mcp-go/transport"
+	"github.com/Convict3d/mcp-go/types"
+	"github.com/ybbus/jsonrpc/v3"
+)
+
+// maxMessageSize is the largest message read from a command
+const maxMessageSize = 10 * 1024 * 1024
+
+// ErrUnsupported is returned by Conn.Send for messages the connection cannot
+// carry, such as malformed JSON over a transport.Transport. Requirements that
+// need such messages are skipped.
+var ErrUnsupported = errors.New("message not supported by the connection")
+
+// Conn is a JSON-RPC connection to the server under test
+type Conn interface {
+	// Send writes one encoded message to the server
+	Send(ctx context.Context, msg []byte) error
+
+	// Receive returns the next message from the server. It returns io.EOF
+	// once the connection is closed.
+	Receive(ctx context.Context) ([]byte, error)
+
+	// Close ends the connection
+	Close() error
+}
+
+// Target opens connections to the server under test. Every requirement is
+// checked on a fresh connection.
+type Target interface {
+	Connect(ctx context.Context) (Conn, error)
+}
+
+// TargetFunc adapts a function to a Target
+type TargetFunc func(ctx context.Context) (Conn, error)
+
+// Connect calls f
+func (f TargetFunc) Connect(ctx context.Context) (Conn, error) {
+	return f(ctx)
+}
+
+// Server returns a target serving srv in process, one session per connection
+func Server(srv *server.Server) Target {
+	return TargetFunc(func(ctx context.Context) (Conn, error) {
+		c := &sessionConn{incoming: make(chan []byte, 64), done: make(chan struct{})}
+		c.ctx, c.cancel = context.WithCancel(context.Background())
+		c.sess = srv.NewSession(func(ctx context.Context, msg []byte) error {
+			return c.deliver(msg)
+		})
+		return c, nil
+	})
+}
+
+// sessionConn is a connection to a server.Session
+type sessionConn struct {
+	sess     *server.Session
+	ctx      context.Context
+	cancel   context.CancelFunc
+	incoming chan []byte
+	done     chan struct{}
+	once     sync.Once
+}
+
+func (c *sessionConn) Send(ctx context.Context, msg []byte) error {
+	select {
+	case <-c.done:
+		return io.EOF
+	default:
+	}
+	msg = append([]byte(nil), msg...)
+	go func() {
+		if response := c.sess.HandleMessage(c.ctx, msg); response != nil {
+			_ = c.deliver(response)
+		}
+	}()
+	return nil
+}
+
+// deliver queues a message from the session for Receive
+func (c *sessionConn) deliver(msg []byte) error {
+	select {
+	case c.incoming <- append([]byte(nil), msg...):
+		return nil
+	case <-c.done:
+		return io.EOF
+	}
+}
+
+func (c *sessionConn) Receive(ctx context.Context) ([]byte, error) {
+	select {
+	case msg := <-c.incoming:
+		return msg, nil
+	case <-c.done:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *sessionConn) Close() error {
+	c.once.Do(func() {
+		close(c.done)
+		c.cancel()
+		c.sess.Close()
+	})
+	return nil
+}
+
+// Command returns a target that starts the named program for every
+// connection and speaks newline-delimited JSON-RPC over its stdin and stdout.
+// The program's stderr is passed through to os.Stderr.
+func Command(name string, args ...string) Target {
+	return TargetFunc(func(ctx context.Context) (Conn, error) {
+		cmd := exec.Command(name, args...)
+		cmd.Stderr = os.Stderr
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start %s: %w", name, err)
+		}
+
+		c := newStreamConn(stdout, stdin)
+		c.cmd = cmd
+		return c, nil
+	})
+}
+
+// streamConn is a connection over newline-delimited JSON-RPC streams
+type streamConn struct {
+	cmd     *exec.Cmd
+	w       io.WriteCloser
+	writeMu sync.Mutex
+	lines   chan []byte
+	done    chan struct{}
+	once    sync.Once
+	err     error // read error, set before lines is closed
+}
+
+func newStreamConn(r io.Reader, w io.WriteCloser) *streamConn {
+	c := &streamConn{w: w, lines: make(chan []byte), done: make(chan struct{})}
+	go func() {
+		defer close(c.lines)
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			select {
+			case c.lines <- append([]byte(nil), line...):
+			case <-c.done:
+				return
+			}
+		}
+		c.err = scanner.Err()
+	}()
+	return c
+}
+
+func (c *streamConn) Send(ctx context.Context, msg []byte) error {
+	if bytes.ContainsAny(msg, "\r\n") {
+		return fmt.Errorf("message contains a newline: %w", ErrUnsupported)
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.w.Write(append(msg, '\n'))
+	return err
+}
+
+func (c *streamConn) Receive(ctx context.Context) ([]byte, error) {
+	select {
+	case line, ok := <-c.lines:
+		if !ok {
+			if c.err != nil {
+				return nil, c.err
+			}
+			return nil, io.EOF
+		}
+		return line, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *streamConn) Close() error {
+	c.once.Do(func() {
+		close(c.done)
+		c.w.Close()
+		if c.cmd == nil {
+			return
+		}
+
+		exited := make(chan struct{})
+		go func() {
+			_ = c.cmd.Wait()
+			close(exited)
+		}()
+		select {
+		case <-exited:
+		case <-time.After(2 * time.Second):
+			_ = c.cmd.Process.Kill()
+			<-exited
+		}
+	})
+	return nil
+}
+
+// Transport returns a target that creates a transport with newTransport for
+// every connection. Transports choose their own request ids and cannot send
+// malformed messages or notifications, so the requirements that need them
+// are skipped.
+func Transport(newTransport func() (transport.Transport, error)) Target {
+	return TargetFunc(func(ctx context.Context) (Conn, error) {
+		t, err := newTransport()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create transport: %w", err)
+		}
+
+		c := &transportConn{t: t, incoming: make(chan []byte, 64), done: make(chan struct{})}
+		c.ctx, c.cancel = context.WithCancel(context.Background())
+		if receiver, ok := t.(transport.NotificationReceiver); ok {
+			receiver.SetNotificationHandler(func(method string, params interface{}) {
+				msg, err := json.Marshal(map[string]interface{}{"jsonrpc": types.JSONRPCVersion, "method": method, "params": params})
+				if err == nil {
+					c.deliver(msg)
+				}
+			})
+		}
+		if receiver, ok := t.(transport.RequestReceiver); ok {
+			receiver.SetRequestHandler(func(method string, params interface{}) (interface{}, error) {
+				return nil, fmt.Errorf("%s is not supported by the conformance suite", method)
+			})
+		}
+		return c, nil
+	})
+}
+
+// transportConn is a connection over a transport.Transport
+type transportConn struct {
+	t        transport.Transport
+	ctx      context.Context
+	cancel   context.CancelFunc
+	incoming chan []byte
+	done     chan struct{}
+	once     sync.Once
+}
+
+func (c *transportConn) Send(ctx context.Context, data []byte) error {
+	var msg struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Method  string          `json:"method"`
+		Params  json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return fmt.Errorf("malformed message: %w", ErrUnsupported)
+	}
+	if msg.JSONRPC != types.JSONRPCVersion || msg.Method == "" || len(msg.ID) == 0 || string(msg.ID) == "null" {
+		return fmt.Errorf("only requests can be sent over a transport: %w", ErrUnsupported)
+	}
+
+	var params []interface{}
+	if len(msg.Params) > 0 {
+		var p interface{}
+		if err := json.Unmarshal(msg.Params, &p); err != nil {
+			return fmt.Errorf("malformed params: %w", ErrUnsupported)
+		}
+		params = append(params, p)
+	}
+
+	go func() {
+		response := map[string]interface{}{"jsonrpc": types.JSONRPCVersion, "id": msg.ID}
+		var result json.RawMessage
+		if err := c.t.Call(c.ctx, &result, msg.Method, params...); err != nil {
+			var rpcErr *jsonrpc.RPCError
+			if errors.As(err, &rpcErr) {
+				response["error"] = rpcErr
+			} else {
+				response["error"] = map[string]interface{}{"code": -32603, "message": "transport error: " + err.Error()}
+			}
+		} else if result == nil {
+			response["result"] = json.RawMessage("null")
+		} else {
+			response["result"] = result
+		}
+
+		if data, err := json.Marshal(response); err == nil {
+			c.deliver(data)
+		}
+	}()
+	return nil
+}
+
+// deliver queues a message for Receive, dropping it once the connection is closed
+func (c *transportConn) deliver(msg []byte) {
+	select {
+	case c.incoming <- msg:
+	case <-c.done:
+	}
+}
+
+func (c *transportConn) Receive(ctx context.Context) ([]byte, error) {
+	select {
+	case msg := <-c.incoming:
+		return msg, nil
+	case <-c.done:
+		return nil, io.EOF
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *transportConn) Close() error {
+	var err error
+	c.once.Do(func() {
+		close(c.done)
+		c.cancel()
+		err = c.t.Close()
+	})
+	return err
+}
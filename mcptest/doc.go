@@ -0,0 +1,78 @@
+/*
+Package mcptest provides a conformance suite for MCP servers and clients.
+
+The suite checks a server against the 2025-06-18 revision of the protocol:
+the initialize handshake and version negotiation, ping, JSON-RPC errors,
+pagination of every list method, tools, resources and subscriptions,
+prompts, every content type, cancellation and progress. Each requirement is
+checked on a fresh connection and reported as passed, failed or skipped.
+Requirements for features the server does not advertise are skipped.
+
+# Basic Usage
+
+Check a server of this library in process:
+
+	report := mcptest.Run(ctx, mcptest.Server(srv))
+	if !report.OK() {
+		t.Fatalf("conformance failures:\n%s", report)
+	}
+
+Check a third-party server started as a local process speaking stdio:
+
+	report := mcptest.Run(ctx, mcptest.Command("node", "build/index.js"))
+	fmt.Print(report)
+
+Check a server through any transport.Transport, such as a Streamable HTTP
+endpoint:
+
+	report := mcptest.Run(ctx, mcptest.Transport(func() (transport.Transport, error) {
+		return mcphttp.NewHTTPTransport("http://localhost:8080/mcp"), nil
+	}))
+
+Transports choose their own request ids and only send well-formed requests,
+so the requirements that need malformed messages or notifications are
+skipped over them. Server and Command targets check every requirement.
+
+# Tool Calls
+
+Calling arbitrary tools may have side effects, so the suite only calls the
+tools it is given. WithToolCall names calls whose results, content blocks and
+progress notifications are checked, and WithSlowToolCall names a call that
+runs until it is cancelled:
+
+	report := mcptest.Run(ctx, target,
+		mcptest.WithToolCall("search", map[string]interface{}{"query": "mcp"}),
+		mcptest.WithSlowToolCall("sleep", map[string]interface{}{"seconds": 60}),
+		mcptest.WithTimeout(2*time.Second),
+	)
+
+# Clients
+
+RunClient checks a client against an in-process fake server: the initialize
+request and notifications/initialized, and the answers to ping and, for the
+capabilities the client declares, to sampling, elicitation and roots
+requests. Check a client of this library:
+
+	report := mcptest.RunClient(ctx, mcptest.Client(func(t transport.Transport) *client.Client {
+		return client.NewClient(
+			client.WithTransport(t),
+			client.WithClientCapabilities(caps),
+			client.WithRequestHandler("sampling/createMessage", sample),
+		)
+	}))
+
+Other clients are started with a ClientTargetFunc, which connects the client
+to the streams of the fake server for each requirement.
+
+# Reports
+
+Report.String formats one line per requirement:
+
+	PASS  lifecycle/initialize              my-server 1.0.0
+	FAIL  tools/call                        search returned invalid content ...
+	SKIP  resources/subscribe               subscriptions not advertised
+
+Report.Result looks up the result of a single requirement by its id, and
+Requirements and ClientRequirements list every requirement the suite checks.
+*/
+package mcptest
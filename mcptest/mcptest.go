@@ -0,0 +1,211 @@
+package mcptest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// Status is the outcome of checking one requirement
+type Status string
+
+// Requirement outcomes
+const (
+	StatusPass Status = "PASS"
+	StatusFail Status = "FAIL"
+	StatusSkip Status = "SKIP" // the server does not offer the feature, or the target cannot send the messages needed
+)
+
+// Requirement is one behaviour the suite checks
+type Requirement struct {
+	ID          string
+	Description string
+}
+
+// Result is the outcome of checking a requirement
+type Result struct {
+	Requirement
+	Status Status
+	Detail string
+}
+
+// Report holds the results of a conformance run in the order the
+// requirements were checked
+type Report struct {
+	Results []Result
+}
+
+// OK reports whether no requirement failed
+func (r *Report) OK() bool {
+	return len(r.Failures()) == 0
+}
+
+// Failures returns the failed requirements
+func (r *Report) Failures() []Result {
+	var failures []Result
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// Result returns the result of the requirement with the given id
+func (r *Report) Result(id string) (Result, bool) {
+	for _, result := range r.Results {
+		if result.ID == id {
+			return result, true
+		}
+	}
+	return Result{}, false
+}
+
+// String formats the report as one line per requirement followed by totals
+func (r *Report) String() string {
+	var b strings.Builder
+	counts := make(map[Status]int)
+	for _, result := range r.Results {
+		counts[result.Status]++
+		detail := result.Description
+		if result.Detail != "" {
+			detail = result.Detail
+		}
+		fmt.Fprintf(&b, "%s  %-32s  %s\n", result.Status, result.ID, detail)
+	}
+	fmt.Fprintf(&b, "%d passed, %d failed, %d skipped\n", counts[StatusPass], counts[StatusFail], counts[StatusSkip])
+	return b.String()
+}
+
+// ToolCall is a tool call the suite may make against the server
+type ToolCall struct {
+	Name      string
+	Arguments map[string]interface{}
+}
+
+// Option configures a conformance run
+type Option func(*config)
+
+type config struct {
+	timeout   time.Duration
+	toolCalls []ToolCall
+	slowCall  *ToolCall
+	maxItems  int
+}
+
+// WithTimeout sets how long the suite waits for each response. The default
+// is 5 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.timeout = timeout
+	}
+}
+
+// WithToolCall allows the suite to call a tool with the given arguments to
+// check its result, content and progress notifications. Without tool calls
+// the suite only lists tools, since calling arbitrary tools may have side
+// effects.
+func WithToolCall(name string, arguments map[string]interface{}) Option {
+	return func(c *config) {
+		c.toolCalls = append(c.toolCalls, ToolCall{Name: name, Arguments: arguments})
+	}
+}
+
+// WithSlowToolCall names a tool call that runs longer than the timeout unless
+// it is cancelled. The suite uses it to check that cancelled requests stop
+// and are not answered; without it the cancellation requirement is skipped.
+func WithSlowToolCall(name string, arguments map[string]interface{}) Option {
+	return func(c *config) {
+		c.slowCall = &ToolCall{Name: name, Arguments: arguments}
+	}
+}
+
+// WithMaxItems limits how many listed resources and prompts are read. The
+// default is 20.
+func WithMaxItems(n int) Option {
+	return func(c *config) {
+		c.maxItems = n
+	}
+}
+
+// outcome is the status and detail produced by a check
+type outcome struct {
+	status Status
+	detail string
+}
+
+func pass(format string, args ...interface{}) outcome {
+	return outcome{StatusPass, fmt.Sprintf(format, args...)}
+}
+
+func fail(format string, args ...interface{}) outcome {
+	return outcome{StatusFail, fmt.Sprintf(format, args...)}
+}
+
+func skip(format string, args ...interface{}) outcome {
+	return outcome{StatusSkip, fmt.Sprintf(format, args...)}
+}
+
+// session is the state a check runs with: an initialized connection and the
+// capabilities the server advertised
+type session struct {
+	*peer
+	config *config
+	caps   types.ServerCapabilities
+}
+
+// requirement is a Requirement with the check that verifies it
+type requirement struct {
+	Requirement
+	raw   bool // the check performs the handshake itself
+	check func(ctx context.Context, s *session) outcome
+}
+
+// Requirements returns the requirements the suite checks, in order
+func Requirements() []Requirement {
+	reqs := make([]Requirement, len(requirements))
+	for i, req := range requirements {
+		reqs[i] = req.Requirement
+	}
+	return reqs
+}
+
+// Run checks every requirement against target and returns the report. Each
+// requirement runs on a fresh connection.
+func Run(ctx context.Context, target Target, opts ...Option) *Report {
+	cfg := &config{timeout: 5 * time.Second, maxItems: 20}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	report := &Report{}
+	for _, req := range requirements {
+		result := Result{Requirement: req.Requirement}
+		o := runRequirement(ctx, target, cfg, req)
+		result.Status, result.Detail = o.status, o.detail
+		report.Results = append(report.Results, result)
+	}
+	return report
+}
+
+// runRequirement connects to target and checks one requirement
+func runRequirement(ctx context.Context, target Target, cfg *config, req requirement) outcome {
+	conn, err := target.Connect(ctx)
+	if err != nil {
+		return fail("failed to connect: %v", err)
+	}
+	s := &session{peer: newPeer(conn, cfg.timeout, nil), config: cfg}
+	defer s.close()
+
+	if !req.raw {
+		result, _, err := s.initialize(ctx, types.LatestProtocolVersion)
+		if err != nil {
+			return fail("initialize failed: %v", err)
+		}
+		s.caps = result.Capabilities
+	}
+	return req.check(ctx, s)
+}
@@ -0,0 +1,185 @@
+package mcptest
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Convict3d/mcp-go/server"
+	"github.com/Convict3d/mcp-go/transport"
+	mcphttp "github.com/Convict3d/mcp-go/transport/http"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+type sumInput struct {
+	A int `json:"a"`
+	B int `json:"b"`
+}
+
+type sumOutput struct {
+	Sum int `json:"sum"`
+}
+
+// newConformingServer returns a server offering every feature the suite checks
+func newConformingServer(t *testing.T) *server.Server {
+	t.Helper()
+	srv := server.New("conforming", "1.0.0", server.WithPageSize(1))
+
+	if err := server.AddTool(srv, "sum", "Adds two numbers", func(ctx context.Context, in sumInput) (sumOutput, error) {
+		return sumOutput{Sum: in.A + in.B}, nil
+	}); err != nil {
+		t.Fatalf("AddTool failed: %v", err)
+	}
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "media"}}, func(ctx context.Context, req *server.ToolRequest) (*types.CallToolResult, error) {
+		for i := 1; i <= 3; i++ {
+			if err := req.ReportProgress(ctx, float64(i), 3, "rendering"); err != nil {
+				return nil, err
+			}
+		}
+		data := base64.StdEncoding.EncodeToString([]byte("media"))
+		return &types.CallToolResult{Content: []interface{}{
+			types.TextContent{Type: types.ContentTypeText, Text: "rendered"},
+			types.ImageContent{Type: types.ContentTypeImage, Data: data, MimeType: "image/png"},
+			types.AudioContent{Type: types.ContentTypeAudio, Data: data, MimeType: "audio/wav"},
+			types.ResourceLinkContent{Type: types.ContentTypeResourceLink, URI: "file:///readme.md", Name: "readme"},
+			map[string]interface{}{
+				"type":     types.ContentTypeResource,
+				"resource": map[string]interface{}{"uri": "file:///readme.md", "text": "# Readme"},
+			},
+		}}, nil
+	})
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "wait"}}, func(ctx context.Context, req *server.ToolRequest) (*types.CallToolResult, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Second):
+			return &types.CallToolResult{Content: []interface{}{types.TextContent{Type: types.ContentTypeText, Text: "done"}}}, nil
+		}
+	})
+
+	srv.AddResource(types.Resource{BaseMetadata: types.BaseMetadata{Name: "readme"}, URI: "file:///readme.md"}, func(ctx context.Context, req *server.ResourceRequest) (*types.ReadResourceResult, error) {
+		return server.ReadResult(types.TextResourceContents{ResourceContents: types.ResourceContents{URI: req.URI}, Text: "# Readme"}), nil
+	})
+	srv.AddResource(types.Resource{BaseMetadata: types.BaseMetadata{Name: "logo"}, URI: "file:///logo.png"}, func(ctx context.Context, req *server.ResourceRequest) (*types.ReadResourceResult, error) {
+		return server.ReadResult(types.BlobResourceContents{ResourceContents: types.ResourceContents{URI: req.URI, MimeType: "image/png"}, Blob: "iVBORw0KGgo="}), nil
+	})
+	if err := srv.AddResourceTemplate(types.ResourceTemplate{BaseMetadata: types.BaseMetadata{Name: "users"}, URITemplate: "users://{id}/profile"}, func(ctx context.Context, req *server.ResourceRequest) (*types.ReadResourceResult, error) {
+		return server.ReadResult(types.TextResourceContents{ResourceContents: types.ResourceContents{URI: req.URI}, Text: req.Vars["id"]}), nil
+	}); err != nil {
+		t.Fatalf("AddResourceTemplate failed: %v", err)
+	}
+
+	srv.AddPrompt(types.Prompt{BaseMetadata: types.BaseMetadata{Name: "hello"}}, server.PromptMessages(func(ctx context.Context, req *server.PromptRequest) ([]types.PromptMessage, error) {
+		return []types.PromptMessage{{Role: types.RoleUser, Content: types.TextContent{Type: types.ContentTypeText, Text: "Hello"}}}, nil
+	}))
+	srv.AddPrompt(types.Prompt{
+		BaseMetadata: types.BaseMetadata{Name: "greet"},
+		Arguments:    []types.PromptArgument{{BaseMetadata: types.BaseMetadata{Name: "name"}, Required: true}},
+	}, server.PromptMessages(func(ctx context.Context, req *server.PromptRequest) ([]types.PromptMessage, error) {
+		return []types.PromptMessage{{Role: types.RoleUser, Content: types.TextContent{Type: types.ContentTypeText, Text: "Hello " + req.Arguments["name"]}}}, nil
+	}))
+	return srv
+}
+
+// conformanceOptions are the options used against newConformingServer
+func conformanceOptions() []Option {
+	return []Option{
+		WithTimeout(time.Second),
+		WithToolCall("sum", map[string]interface{}{"a": 1, "b": 2}),
+		WithToolCall("media", nil),
+		WithSlowToolCall("wait", nil),
+	}
+}
+
+func TestRunServer(t *testing.T) {
+	report := Run(context.Background(), Server(newConformingServer(t)), conformanceOptions()...)
+	if len(report.Results) != len(Requirements()) {
+		t.Fatalf("Results = %d, expected %d", len(report.Results), len(Requirements()))
+	}
+	for _, result := range report.Results {
+		if result.Status != StatusPass {
+			t.Errorf("%s: %s %s", result.ID, result.Status, result.Detail)
+		}
+	}
+
+	result, ok := report.Result("content/types")
+	if !ok || result.Detail != "audio: 1, image: 1, resource: 1, resource_link: 1, text: 3" {
+		t.Errorf("content/types = %+v", result)
+	}
+}
+
+func TestRunTransport(t *testing.T) {
+	handler := server.NewHTTPHandler(newConformingServer(t))
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+	defer handler.Close()
+
+	target := Transport(func() (transport.Transport, error) {
+		return mcphttp.NewHTTPTransport(ts.URL), nil
+	})
+	report := Run(context.Background(), target, conformanceOptions()...)
+	if !report.OK() {
+		t.Fatalf("Report failed:\n%s", report)
+	}
+	for _, id := range []string{"errors/parse-error", "errors/invalid-request"} {
+		if result, _ := report.Result(id); result.Status != StatusSkip {
+			t.Errorf("%s = %+v, expected a skip", id, result)
+		}
+	}
+	if result, _ := report.Result("tools/call"); result.Status != StatusPass {
+		t.Errorf("tools/call = %+v", result)
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	t.Setenv("MCPTEST_HELPER_SERVER", "1")
+	// The race detector otherwise delays the exit of every server process by a second
+	t.Setenv("GORACE", "atexit_sleep_ms=0")
+	report := Run(context.Background(), Command(os.Args[0], "-test.run=TestHelperServer"), conformanceOptions()...)
+	if !report.OK() {
+		t.Fatalf("Report failed:\n%s", report)
+	}
+}
+
+// TestHelperServer serves the conforming server over stdio when run by TestRunCommand
+func TestHelperServer(t *testing.T) {
+	if os.Getenv("MCPTEST_HELPER_SERVER") != "1" {
+		t.Skip("helper process for TestRunCommand")
+	}
+	server.ServeStdio(context.Background(), newConformingServer(t))
+	os.Exit(0)
+}
+
+func TestRunReportsFailures(t *testing.T) {
+	srv := server.New("broken", "")
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "snapshot"}}, func(ctx context.Context, req *server.ToolRequest) (*types.CallToolResult, error) {
+		return &types.CallToolResult{Content: []interface{}{types.ImageContent{Type: types.ContentTypeImage, Data: "not base64!"}}}, nil
+	})
+
+	report := Run(context.Background(), Server(srv), WithTimeout(time.Second), WithToolCall("snapshot", nil))
+	if report.OK() {
+		t.Fatalf("Report passed:\n%s", report)
+	}
+	expected := map[string]Status{
+		"lifecycle/initialize": StatusFail,
+		"lifecycle/ping":       StatusPass,
+		"tools/call":           StatusFail,
+		"content/types":        StatusFail,
+		"pagination/prompts":   StatusSkip,
+		"resources/read":       StatusSkip,
+		// Cancellation needs a slow tool call to cancel
+		"utilities/cancellation": StatusSkip,
+	}
+	for id, status := range expected {
+		if result, _ := report.Result(id); result.Status != status {
+			t.Errorf("%s = %+v, expected %s", id, result, status)
+		}
+	}
+	if !strings.Contains(report.String(), "FAIL  tools/call") {
+		t.Errorf("String() = %s", report)
+	}
+}
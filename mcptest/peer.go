@@ -0,0 +1,281 @@
+package mcptest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// message is a JSON-RPC message as read from or written to the server
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a JSON-RPC error returned by the server
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// hasID reports whether the message has an id
+func (m *message) hasID() bool {
+	return len(m.ID) > 0 && string(m.ID) != "null"
+}
+
+// requestHandler answers a request received by a peer with a result or an error
+type requestHandler func(msg message) (interface{}, *rpcError)
+
+// peer is one side of a connection to the implementation under test: the
+// client of a server or the fake server of a client. It answers requests with
+// its handler, or with method not found when it has none, and records
+// notifications.
+type peer struct {
+	conn    Conn
+	timeout time.Duration
+	handle  requestHandler
+
+	mu            sync.Mutex
+	nextID        int64
+	pending       map[string]chan message
+	notifications []message
+	done          chan struct{}
+}
+
+// newPeer starts reading messages from conn, answering requests with handle
+func newPeer(conn Conn, timeout time.Duration, handle requestHandler) *peer {
+	p := &peer{
+		conn:    conn,
+		timeout: timeout,
+		handle:  handle,
+		pending: make(map[string]chan message),
+		done:    make(chan struct{}),
+	}
+	go p.read()
+	return p
+}
+
+// read dispatches the messages from the server until the connection ends
+func (p *peer) read() {
+	defer close(p.done)
+	for {
+		data, err := p.conn.Receive(context.Background())
+		if err != nil {
+			return
+		}
+		var msg message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			continue
+		}
+
+		switch {
+		case msg.Method != "" && msg.hasID():
+			_ = p.conn.Send(context.Background(), p.answer(msg))
+		case msg.Method != "":
+			p.mu.Lock()
+			p.notifications = append(p.notifications, msg)
+			p.mu.Unlock()
+		default:
+			key := string(msg.ID)
+			if len(msg.ID) == 0 {
+				key = "null"
+			}
+			p.mu.Lock()
+			responses, ok := p.pending[key]
+			delete(p.pending, key)
+			p.mu.Unlock()
+			if ok {
+				responses <- msg
+			}
+		}
+	}
+}
+
+// answer returns the encoded response to a request
+func (p *peer) answer(msg message) []byte {
+	response := message{JSONRPC: types.JSONRPCVersion, ID: msg.ID}
+	var result interface{}
+	if p.handle == nil {
+		// The suite declares no client capabilities
+		response.Error = &rpcError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+	} else {
+		result, response.Error = p.handle(msg)
+	}
+	if response.Error == nil {
+		data, err := json.Marshal(result)
+		if err != nil {
+			response.Error = &rpcError{Code: codeInternalError, Message: "failed to marshal result: " + err.Error()}
+		}
+		response.Result = data
+	}
+	data, _ := json.Marshal(response)
+	return data
+}
+
+// close closes the connection and waits for the reader to stop
+func (p *peer) close() {
+	p.conn.Close()
+	select {
+	case <-p.done:
+	case <-time.After(p.timeout):
+	}
+}
+
+// newID returns the id of a new request
+func (p *peer) newID() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nextID++
+	return strconv.FormatInt(p.nextID, 10)
+}
+
+// start sends an encoded message and returns the channel its response
+// arrives on; id is "null" for messages answered with a null id
+func (p *peer) start(ctx context.Context, id string, data []byte) (<-chan message, error) {
+	responses := make(chan message, 1)
+	p.mu.Lock()
+	p.pending[id] = responses
+	p.mu.Unlock()
+
+	if err := p.conn.Send(ctx, data); err != nil {
+		p.mu.Lock()
+		delete(p.pending, id)
+		p.mu.Unlock()
+		return nil, err
+	}
+	return responses, nil
+}
+
+// wait waits for the response to a started message
+func (p *peer) wait(ctx context.Context, method string, responses <-chan message) (message, error) {
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+	select {
+	case msg := <-responses:
+		return msg, nil
+	case <-timer.C:
+		return message{}, fmt.Errorf("no response to %s within %s", method, p.timeout)
+	case <-p.done:
+		return message{}, fmt.Errorf("connection closed while waiting for the response to %s", method)
+	case <-ctx.Done():
+		return message{}, ctx.Err()
+	}
+}
+
+// sendRaw sends an encoded message and waits for the response with the given id
+func (p *peer) sendRaw(ctx context.Context, id string, data []byte) (message, error) {
+	responses, err := p.start(ctx, id, data)
+	if err != nil {
+		return message{}, err
+	}
+	return p.wait(ctx, string(data), responses)
+}
+
+// startRequest sends a request without waiting for its response
+func (p *peer) startRequest(ctx context.Context, method string, params interface{}) (string, <-chan message, error) {
+	id := p.newID()
+	msg := map[string]interface{}{"jsonrpc": types.JSONRPCVersion, "id": json.RawMessage(id), "method": method}
+	if params != nil {
+		msg["params"] = params
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal %s: %w", method, err)
+	}
+	responses, err := p.start(ctx, id, data)
+	return id, responses, err
+}
+
+// request sends a request and returns its result. Error responses are
+// returned as *rpcError.
+func (p *peer) request(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	_, responses, err := p.startRequest(ctx, method, params)
+	if err != nil {
+		return nil, err
+	}
+	response, err := p.wait(ctx, method, responses)
+	if err != nil {
+		return nil, err
+	}
+	if response.Error != nil {
+		return nil, response.Error
+	}
+	if len(response.Result) == 0 {
+		return nil, fmt.Errorf("response to %s has neither result nor error", method)
+	}
+	return response.Result, nil
+}
+
+// call sends a request and decodes its result into result
+func (p *peer) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	data, err := p.request(ctx, method, params)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("invalid %s result %s: %w", method, data, err)
+	}
+	return nil
+}
+
+// notify sends a notification
+func (p *peer) notify(ctx context.Context, method string, params interface{}) error {
+	msg := map[string]interface{}{"jsonrpc": types.JSONRPCVersion, "method": method}
+	if params != nil {
+		msg["params"] = params
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", method, err)
+	}
+	return p.conn.Send(ctx, data)
+}
+
+// initialize performs the initialize handshake asking for version
+func (p *peer) initialize(ctx context.Context, version string) (*types.InitializeResult, json.RawMessage, error) {
+	data, err := p.request(ctx, "initialize", map[string]interface{}{
+		"protocolVersion": version,
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]interface{}{"name": "mcptest", "version": "1.0.0"},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var result types.InitializeResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, data, fmt.Errorf("invalid initialize result %s: %w", data, err)
+	}
+
+	if err := p.notify(ctx, "notifications/initialized", nil); err != nil && !errors.Is(err, ErrUnsupported) {
+		return nil, data, fmt.Errorf("failed to send notifications/initialized: %w", err)
+	}
+	return &result, data, nil
+}
+
+// notificationsFor returns the notifications received so far with the given method
+func (p *peer) notificationsFor(method string) []message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var matching []message
+	for _, msg := range p.notifications {
+		if msg.Method == method {
+			matching = append(matching, msg)
+		}
+	}
+	return matching
+}
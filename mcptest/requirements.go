@@ -0,0 +1,794 @@
+package mcptest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Convict3d/mcp-go/schema"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// JSON-RPC and MCP error codes the requirements expect
+const (
+	codeParseError       = -32700
+	codeInvalidRequest   = -32600
+	codeMethodNotFound   = -32601
+	codeInvalidParams    = -32602
+	codeInternalError    = -32603
+	codeResourceNotFound = -32002
+)
+
+// maxPages bounds pagination so that servers returning cursors forever fail
+// instead of hanging the suite
+const maxPages = 1000
+
+// knownVersions lists the released protocol versions
+var knownVersions = map[string]bool{
+	types.LatestProtocolVersion: true,
+	"2025-03-26":                true,
+	"2024-11-05":                true,
+}
+
+// requirements are checked in order
+var requirements = []requirement{
+	{Requirement{"lifecycle/initialize", "initialize returns the requested 2025-06-18 version, server info and capabilities"}, true, checkInitialize},
+	{Requirement{"lifecycle/version-negotiation", "an unsupported protocol version is answered with a version the server supports"}, true, checkVersionNegotiation},
+	{Requirement{"lifecycle/ping", "ping returns an empty result"}, false, checkPing},
+	{Requirement{"errors/parse-error", "malformed JSON is answered with error -32700 and a null id"}, false, checkParseError},
+	{Requirement{"errors/invalid-request", "a request with the wrong jsonrpc version is answered with error -32600"}, false, checkInvalidRequest},
+	{Requirement{"errors/method-not-found", "unknown methods are answered with error -32601"}, false, checkMethodNotFound},
+	{Requirement{"pagination/tools", "following nextCursor through tools/list returns every tool once"}, false, checkList(toolsList)},
+	{Requirement{"pagination/resources", "following nextCursor through resources/list returns every resource once"}, false, checkList(resourcesList)},
+	{Requirement{"pagination/resource-templates", "following nextCursor through resources/templates/list returns every template once"}, false, checkList(templatesList)},
+	{Requirement{"pagination/prompts", "following nextCursor through prompts/list returns every prompt once"}, false, checkList(promptsList)},
+	{Requirement{"pagination/invalid-cursor", "an invalid cursor is answered with error -32602"}, false, checkInvalidCursor},
+	{Requirement{"tools/schema", "every tool has an object input schema and, when it declares one, an object output schema"}, false, checkToolSchemas},
+	{Requirement{"tools/unknown", "calling an unknown tool is answered with error -32602"}, false, checkUnknownTool},
+	{Requirement{"tools/call", "tool results hold valid content and structured content matching the output schema"}, false, checkToolCalls},
+	{Requirement{"resources/read", "listed resources read as contents with a URI and either text or a base64 blob"}, false, checkReadResources},
+	{Requirement{"resources/not-found", "reading an unknown resource is answered with error -32002"}, false, checkResourceNotFound},
+	{Requirement{"resources/subscribe", "resources/subscribe and resources/unsubscribe succeed when subscriptions are advertised"}, false, checkSubscribe},
+	{Requirement{"prompts/get", "prompts return messages with user or assistant roles and valid content"}, false, checkGetPrompts},
+	{Requirement{"prompts/missing-argument", "a prompt request missing a required argument is answered with error -32602"}, false, checkMissingArgument},
+	{Requirement{"content/types", "every content block is a 2025-06-18 content type with its required fields"}, false, checkContentTypes},
+	{Requirement{"utilities/cancellation", "cancelled requests are not answered and the server keeps serving"}, false, checkCancellation},
+	{Requirement{"utilities/progress", "progress notifications carry the request's progressToken and increasing progress"}, false, checkProgress},
+}
+
+func checkInitialize(ctx context.Context, s *session) outcome {
+	result, data, err := s.initialize(ctx, types.LatestProtocolVersion)
+	if err != nil {
+		return fail("initialize failed: %v", err)
+	}
+	if result.ProtocolVersion != types.LatestProtocolVersion {
+		return fail("server answered protocol version %q, expected %q", result.ProtocolVersion, types.LatestProtocolVersion)
+	}
+	if result.ServerInfo.Name == "" || result.ServerInfo.Version == "" {
+		return fail("serverInfo needs a name and a version, got %+v", result.ServerInfo)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || !isObject(fields["capabilities"]) {
+		return fail("capabilities must be an object, got %s", fields["capabilities"])
+	}
+	return pass("%s %s", result.ServerInfo.Name, result.ServerInfo.Version)
+}
+
+func checkVersionNegotiation(ctx context.Context, s *session) outcome {
+	result, _, err := s.initialize(ctx, "1999-01-01")
+	if err != nil {
+		return fail("initialize with an unsupported version failed: %v", err)
+	}
+	if !knownVersions[result.ProtocolVersion] {
+		return fail("server answered protocol version %q, expected a released version", result.ProtocolVersion)
+	}
+	return pass("offered %s", result.ProtocolVersion)
+}
+
+func checkPing(ctx context.Context, s *session) outcome {
+	data, err := s.request(ctx, "ping", nil)
+	if err != nil {
+		return fail("ping failed: %v", err)
+	}
+	if !isObject(data) {
+		return fail("ping result must be an object, got %s", data)
+	}
+	return pass("")
+}
+
+func checkParseError(ctx context.Context, s *session) outcome {
+	response, err := s.sendRaw(ctx, "null", []byte(`{"jsonrpc":"2.0","id":1,"method":"ping"`))
+	if errors.Is(err, ErrUnsupported) {
+		return skip("the target cannot send malformed JSON")
+	}
+	if err != nil {
+		return fail("%v", err)
+	}
+	if response.Error == nil {
+		return fail("malformed JSON was answered with result %s", response.Result)
+	}
+	if response.Error.Code != codeParseError {
+		return fail("malformed JSON was answered with code %d, expected %d", response.Error.Code, codeParseError)
+	}
+	return pass("")
+}
+
+func checkInvalidRequest(ctx context.Context, s *session) outcome {
+	id := s.newID()
+	response, err := s.sendRaw(ctx, id, []byte(`{"jsonrpc":"1.0","id":`+id+`,"method":"ping"}`))
+	if errors.Is(err, ErrUnsupported) {
+		return skip("the target cannot send invalid requests")
+	}
+	if err != nil {
+		return fail("%v", err)
+	}
+	if response.Error == nil {
+		return fail("the invalid request was answered with result %s", response.Result)
+	}
+	if response.Error.Code != codeInvalidRequest {
+		return fail("the invalid request was answered with code %d, expected %d", response.Error.Code, codeInvalidRequest)
+	}
+	return pass("")
+}
+
+func checkMethodNotFound(ctx context.Context, s *session) outcome {
+	_, err := s.request(ctx, "mcptest/unknown-method", nil)
+	return expectCode(err, codeMethodNotFound, "mcptest/unknown-method")
+}
+
+// list describes a paginated list method
+type list struct {
+	method  string
+	field   string // field of the result holding the items
+	key     string // field identifying an item
+	enabled func(caps types.ServerCapabilities) bool
+}
+
+var (
+	toolsList = list{"tools/list", "tools", "name", func(caps types.ServerCapabilities) bool {
+		return caps.Tools != nil
+	}}
+	resourcesList = list{"resources/list", "resources", "uri", func(caps types.ServerCapabilities) bool {
+		return caps.Resources != nil
+	}}
+	templatesList = list{"resources/templates/list", "resourceTemplates", "uriTemplate", func(caps types.ServerCapabilities) bool {
+		return caps.Resources != nil
+	}}
+	promptsList = list{"prompts/list", "prompts", "name", func(caps types.ServerCapabilities) bool {
+		return caps.Prompts != nil
+	}}
+)
+
+// listAll follows nextCursor through every page of l and returns the items
+// and the number of pages
+func listAll(ctx context.Context, s *session, l list) ([]json.RawMessage, int, error) {
+	var items []json.RawMessage
+	seen := make(map[string]bool)
+	cursor := ""
+	for pages := 1; pages <= maxPages; pages++ {
+		var params interface{}
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+		var page map[string]json.RawMessage
+		if err := s.call(ctx, l.method, params, &page); err != nil {
+			return nil, pages, err
+		}
+
+		var pageItems []json.RawMessage
+		if err := json.Unmarshal(page[l.field], &pageItems); err != nil || page[l.field] == nil {
+			return nil, pages, fmt.Errorf("%s result has no %s array", l.method, l.field)
+		}
+		for _, item := range pageItems {
+			var fields map[string]interface{}
+			_ = json.Unmarshal(item, &fields)
+			key, _ := fields[l.key].(string)
+			if key == "" {
+				return nil, pages, fmt.Errorf("%s returned an item without %s: %s", l.method, l.key, item)
+			}
+			if seen[key] {
+				return nil, pages, fmt.Errorf("%s returned %s %q twice", l.method, l.key, key)
+			}
+			seen[key] = true
+		}
+		items = append(items, pageItems...)
+
+		next, ok := page["nextCursor"]
+		if !ok || string(next) == "null" {
+			return items, pages, nil
+		}
+		if err := json.Unmarshal(next, &cursor); err != nil {
+			return nil, pages, fmt.Errorf("%s returned a nextCursor that is not a string: %s", l.method, next)
+		}
+		if cursor == "" {
+			return items, pages, nil
+		}
+	}
+	return nil, maxPages, fmt.Errorf("%s returned more than %d pages", l.method, maxPages)
+}
+
+// checkList returns the check of the pagination of l
+func checkList(l list) func(ctx context.Context, s *session) outcome {
+	return func(ctx context.Context, s *session) outcome {
+		if !l.enabled(s.caps) {
+			return skip("capability not advertised")
+		}
+		items, pages, err := listAll(ctx, s, l)
+		if err != nil {
+			return fail("%v", err)
+		}
+		return pass("%d items in %d pages", len(items), pages)
+	}
+}
+
+func checkInvalidCursor(ctx context.Context, s *session) outcome {
+	for _, l := range []list{toolsList, resourcesList, promptsList} {
+		if l.enabled(s.caps) {
+			_, err := s.request(ctx, l.method, map[string]string{"cursor": "mcptest-invalid-cursor"})
+			return expectCode(err, codeInvalidParams, l.method+" with an invalid cursor")
+		}
+	}
+	return skip("no list capability advertised")
+}
+
+// listTools returns every tool of the server
+func listTools(ctx context.Context, s *session) ([]types.Tool, error) {
+	items, _, err := listAll(ctx, s, toolsList)
+	if err != nil {
+		return nil, err
+	}
+	tools := make([]types.Tool, len(items))
+	for i, item := range items {
+		if err := json.Unmarshal(item, &tools[i]); err != nil {
+			return nil, fmt.Errorf("invalid tool %s: %w", item, err)
+		}
+	}
+	return tools, nil
+}
+
+func checkToolSchemas(ctx context.Context, s *session) outcome {
+	if s.caps.Tools == nil {
+		return skip("capability not advertised")
+	}
+	tools, err := listTools(ctx, s)
+	if err != nil {
+		return fail("%v", err)
+	}
+	for _, tool := range tools {
+		if tool.InputSchema.Type != "object" {
+			return fail("tool %s has input schema type %q, expected \"object\"", tool.Name, tool.InputSchema.Type)
+		}
+		if tool.OutputSchema != nil && tool.OutputSchema.Type != "object" {
+			return fail("tool %s has output schema type %q, expected \"object\"", tool.Name, tool.OutputSchema.Type)
+		}
+	}
+	return pass("%d tools", len(tools))
+}
+
+func checkUnknownTool(ctx context.Context, s *session) outcome {
+	if s.caps.Tools == nil {
+		return skip("capability not advertised")
+	}
+	_, err := s.request(ctx, "tools/call", map[string]interface{}{"name": "mcptest-unknown-tool", "arguments": map[string]interface{}{}})
+	return expectCode(err, codeInvalidParams, "calling an unknown tool")
+}
+
+// callTool calls a configured tool, passing meta as the _meta of the request
+func callTool(ctx context.Context, s *session, call ToolCall, meta map[string]interface{}) (json.RawMessage, error) {
+	params := map[string]interface{}{"name": call.Name}
+	if call.Arguments != nil {
+		params["arguments"] = call.Arguments
+	}
+	if meta != nil {
+		params["_meta"] = meta
+	}
+	return s.request(ctx, "tools/call", params)
+}
+
+// toolContent returns the content blocks of a tool result
+func toolContent(data json.RawMessage) ([]json.RawMessage, error) {
+	var result struct {
+		Content []json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil || result.Content == nil {
+		return nil, fmt.Errorf("tool result has no content array: %s", data)
+	}
+	return result.Content, nil
+}
+
+func checkToolCalls(ctx context.Context, s *session) outcome {
+	if s.caps.Tools == nil {
+		return skip("capability not advertised")
+	}
+	if len(s.config.toolCalls) == 0 {
+		return skip("no tool calls configured")
+	}
+	tools, err := listTools(ctx, s)
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	for _, call := range s.config.toolCalls {
+		tool := findTool(tools, call.Name)
+		if tool == nil {
+			return fail("tool %s is not listed", call.Name)
+		}
+		data, err := callTool(ctx, s, call, nil)
+		if err != nil {
+			return fail("calling %s failed: %v", call.Name, err)
+		}
+		content, err := toolContent(data)
+		if err != nil {
+			return fail("%s: %v", call.Name, err)
+		}
+		for _, block := range content {
+			if _, err := validateContent(block); err != nil {
+				return fail("%s returned invalid content %s: %v", call.Name, block, err)
+			}
+		}
+
+		var result types.CallToolResult
+		if err := json.Unmarshal(data, &result); err != nil {
+			return fail("%s returned an invalid result: %v", call.Name, err)
+		}
+		if tool.OutputSchema == nil || result.IsError {
+			continue
+		}
+		if result.StructuredContent == nil {
+			return fail("%s declares an output schema but returned no structured content", call.Name)
+		}
+		if err := schema.Validate(tool.OutputSchema, result.StructuredContent); err != nil {
+			return fail("%s returned structured content not matching its output schema: %v", call.Name, err)
+		}
+	}
+	return pass("%d calls", len(s.config.toolCalls))
+}
+
+// findTool returns the tool with the given name, or nil
+func findTool(tools []types.Tool, name string) *types.Tool {
+	for i := range tools {
+		if tools[i].Name == name {
+			return &tools[i]
+		}
+	}
+	return nil
+}
+
+// listResources returns the URIs of the first resources of the server
+func listResources(ctx context.Context, s *session) ([]string, error) {
+	items, _, err := listAll(ctx, s, resourcesList)
+	if err != nil {
+		return nil, err
+	}
+	var uris []string
+	for _, item := range items {
+		var resource types.Resource
+		if err := json.Unmarshal(item, &resource); err != nil {
+			return nil, fmt.Errorf("invalid resource %s: %w", item, err)
+		}
+		uris = append(uris, resource.URI)
+		if len(uris) == s.config.maxItems {
+			break
+		}
+	}
+	return uris, nil
+}
+
+func checkReadResources(ctx context.Context, s *session) outcome {
+	if s.caps.Resources == nil {
+		return skip("capability not advertised")
+	}
+	uris, err := listResources(ctx, s)
+	if err != nil {
+		return fail("%v", err)
+	}
+	if len(uris) == 0 {
+		return skip("no resources listed")
+	}
+
+	for _, uri := range uris {
+		var result struct {
+			Contents []map[string]interface{} `json:"contents"`
+		}
+		if err := s.call(ctx, "resources/read", map[string]string{"uri": uri}, &result); err != nil {
+			return fail("reading %s failed: %v", uri, err)
+		}
+		if result.Contents == nil {
+			return fail("reading %s returned no contents array", uri)
+		}
+		for _, contents := range result.Contents {
+			if err := validateResourceContents(contents); err != nil {
+				return fail("reading %s: %v", uri, err)
+			}
+		}
+	}
+	return pass("%d resources read", len(uris))
+}
+
+func checkResourceNotFound(ctx context.Context, s *session) outcome {
+	if s.caps.Resources == nil {
+		return skip("capability not advertised")
+	}
+	_, err := s.request(ctx, "resources/read", map[string]string{"uri": "mcptest://unknown/resource"})
+	return expectCode(err, codeResourceNotFound, "reading an unknown resource")
+}
+
+func checkSubscribe(ctx context.Context, s *session) outcome {
+	if s.caps.Resources == nil || !s.caps.Resources.Subscribe {
+		return skip("subscriptions not advertised")
+	}
+	uris, err := listResources(ctx, s)
+	if err != nil {
+		return fail("%v", err)
+	}
+	if len(uris) == 0 {
+		return skip("no resources listed")
+	}
+
+	for _, method := range []string{"resources/subscribe", "resources/unsubscribe"} {
+		data, err := s.request(ctx, method, map[string]string{"uri": uris[0]})
+		if err != nil {
+			return fail("%s %s failed: %v", method, uris[0], err)
+		}
+		if !isObject(data) {
+			return fail("%s result must be an object, got %s", method, data)
+		}
+	}
+	return pass("subscribed to %s", uris[0])
+}
+
+// listPrompts returns the first prompts of the server
+func listPrompts(ctx context.Context, s *session) ([]types.Prompt, error) {
+	items, _, err := listAll(ctx, s, promptsList)
+	if err != nil {
+		return nil, err
+	}
+	var prompts []types.Prompt
+	for _, item := range items {
+		var prompt types.Prompt
+		if err := json.Unmarshal(item, &prompt); err != nil {
+			return nil, fmt.Errorf("invalid prompt %s: %w", item, err)
+		}
+		prompts = append(prompts, prompt)
+		if len(prompts) == s.config.maxItems {
+			break
+		}
+	}
+	return prompts, nil
+}
+
+// hasRequiredArguments reports whether a prompt has required arguments
+func hasRequiredArguments(prompt types.Prompt) bool {
+	for _, arg := range prompt.Arguments {
+		if arg.Required {
+			return true
+		}
+	}
+	return false
+}
+
+// promptContent gets a prompt without arguments, checks the roles of its
+// messages and returns their content blocks
+func promptContent(ctx context.Context, s *session, name string) ([]json.RawMessage, error) {
+	var result struct {
+		Messages []struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		} `json:"messages"`
+	}
+	if err := s.call(ctx, "prompts/get", map[string]interface{}{"name": name}, &result); err != nil {
+		return nil, fmt.Errorf("getting prompt %s failed: %w", name, err)
+	}
+	if result.Messages == nil {
+		return nil, fmt.Errorf("prompt %s returned no messages array", name)
+	}
+
+	var content []json.RawMessage
+	for _, msg := range result.Messages {
+		if msg.Role != string(types.RoleUser) && msg.Role != string(types.RoleAssistant) {
+			return nil, fmt.Errorf("prompt %s returned a message with role %q", name, msg.Role)
+		}
+		content = append(content, msg.Content)
+	}
+	return content, nil
+}
+
+func checkGetPrompts(ctx context.Context, s *session) outcome {
+	if s.caps.Prompts == nil {
+		return skip("capability not advertised")
+	}
+	prompts, err := listPrompts(ctx, s)
+	if err != nil {
+		return fail("%v", err)
+	}
+
+	checked := 0
+	for _, prompt := range prompts {
+		if hasRequiredArguments(prompt) {
+			continue
+		}
+		content, err := promptContent(ctx, s, prompt.Name)
+		if err != nil {
+			return fail("%v", err)
+		}
+		for _, block := range content {
+			if _, err := validateContent(block); err != nil {
+				return fail("prompt %s returned invalid content %s: %v", prompt.Name, block, err)
+			}
+		}
+		checked++
+	}
+	if checked == 0 {
+		return skip("no prompts without required arguments")
+	}
+	return pass("%d prompts", checked)
+}
+
+func checkMissingArgument(ctx context.Context, s *session) outcome {
+	if s.caps.Prompts == nil {
+		return skip("capability not advertised")
+	}
+	prompts, err := listPrompts(ctx, s)
+	if err != nil {
+		return fail("%v", err)
+	}
+	for _, prompt := range prompts {
+		if hasRequiredArguments(prompt) {
+			_, err := s.request(ctx, "prompts/get", map[string]interface{}{"name": prompt.Name, "arguments": map[string]string{}})
+			return expectCode(err, codeInvalidParams, "getting prompt "+prompt.Name+" without arguments")
+		}
+	}
+	return skip("no prompts with required arguments")
+}
+
+func checkContentTypes(ctx context.Context, s *session) outcome {
+	var blocks []json.RawMessage
+	if s.caps.Tools != nil {
+		for _, call := range s.config.toolCalls {
+			data, err := callTool(ctx, s, call, nil)
+			if err != nil {
+				return fail("calling %s failed: %v", call.Name, err)
+			}
+			content, err := toolContent(data)
+			if err != nil {
+				return fail("%s: %v", call.Name, err)
+			}
+			blocks = append(blocks, content...)
+		}
+	}
+	if s.caps.Prompts != nil {
+		prompts, err := listPrompts(ctx, s)
+		if err != nil {
+			return fail("%v", err)
+		}
+		for _, prompt := range prompts {
+			if !hasRequiredArguments(prompt) {
+				content, err := promptContent(ctx, s, prompt.Name)
+				if err != nil {
+					return fail("%v", err)
+				}
+				blocks = append(blocks, content...)
+			}
+		}
+	}
+	if len(blocks) == 0 {
+		return skip("no content returned by configured tool calls or prompts")
+	}
+
+	counts := make(map[string]int)
+	for _, block := range blocks {
+		typ, err := validateContent(block)
+		if err != nil {
+			return fail("invalid content %s: %v", block, err)
+		}
+		counts[typ]++
+	}
+	var summary []string
+	for typ, n := range counts {
+		summary = append(summary, fmt.Sprintf("%s: %d", typ, n))
+	}
+	sort.Strings(summary)
+	return pass("%s", strings.Join(summary, ", "))
+}
+
+func checkCancellation(ctx context.Context, s *session) outcome {
+	err := s.notify(ctx, "notifications/cancelled", map[string]interface{}{"requestId": "mcptest-unknown-request", "reason": "conformance test"})
+	if errors.Is(err, ErrUnsupported) {
+		return skip("the target cannot send notifications")
+	}
+	if err != nil {
+		return fail("failed to send notifications/cancelled: %v", err)
+	}
+	if _, err := s.request(ctx, "ping", nil); err != nil {
+		return fail("ping after cancelling an unknown request failed: %v", err)
+	}
+
+	call := s.config.slowCall
+	if call == nil {
+		return skip("no slow tool call configured")
+	}
+	params := map[string]interface{}{"name": call.Name, "arguments": call.Arguments}
+	id, responses, err := s.startRequest(ctx, "tools/call", params)
+	if err != nil {
+		return fail("calling %s failed: %v", call.Name, err)
+	}
+	// Give the server time to start the call; cancelling a request that has
+	// not arrived yet is allowed to have no effect
+	time.Sleep(s.timeout / 10)
+	if err := s.notify(ctx, "notifications/cancelled", map[string]interface{}{"requestId": json.RawMessage(id), "reason": "conformance test"}); err != nil {
+		return fail("failed to send notifications/cancelled: %v", err)
+	}
+
+	timer := time.NewTimer(s.timeout)
+	defer timer.Stop()
+	select {
+	case response := <-responses:
+		return fail("the cancelled call to %s was answered: %s%s", call.Name, response.Result, errorText(response.Error))
+	case <-timer.C:
+	case <-ctx.Done():
+		return fail("%v", ctx.Err())
+	}
+	if _, err := s.request(ctx, "ping", nil); err != nil {
+		return fail("ping after cancelling %s failed: %v", call.Name, err)
+	}
+	return pass("cancelled %s", call.Name)
+}
+
+func checkProgress(ctx context.Context, s *session) outcome {
+	if s.caps.Tools == nil {
+		return skip("capability not advertised")
+	}
+	if len(s.config.toolCalls) == 0 {
+		return skip("no tool calls configured")
+	}
+
+	tokens := make(map[string]bool)
+	for i, call := range s.config.toolCalls {
+		token := fmt.Sprintf("mcptest-progress-%d", i)
+		tokens[token] = true
+		if _, err := callTool(ctx, s, call, map[string]interface{}{"progressToken": token}); err != nil {
+			return fail("calling %s failed: %v", call.Name, err)
+		}
+	}
+
+	notifications := s.notificationsFor("notifications/progress")
+	if len(notifications) == 0 {
+		return skip("no progress notifications were sent")
+	}
+	last := make(map[string]float64)
+	for _, msg := range notifications {
+		var params struct {
+			ProgressToken interface{} `json:"progressToken"`
+			Progress      *float64    `json:"progress"`
+			Total         *float64    `json:"total"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil || params.Progress == nil {
+			return fail("invalid progress notification %s", msg.Params)
+		}
+		token, _ := params.ProgressToken.(string)
+		if !tokens[token] {
+			return fail("progress notification for unknown token %v", params.ProgressToken)
+		}
+		if previous, ok := last[token]; ok && *params.Progress <= previous {
+			return fail("progress for %s went from %v to %v, expected it to increase", token, previous, *params.Progress)
+		}
+		if params.Total != nil && *params.Progress > *params.Total {
+			return fail("progress %v exceeds total %v", *params.Progress, *params.Total)
+		}
+		last[token] = *params.Progress
+	}
+	return pass("%d notifications", len(notifications))
+}
+
+// expectCode checks that a request failed with a JSON-RPC error with code
+func expectCode(err error, code int, what string) outcome {
+	var rpcErr *rpcError
+	switch {
+	case err == nil:
+		return fail("%s succeeded, expected error %d", what, code)
+	case !errors.As(err, &rpcErr):
+		return fail("%s: %v", what, err)
+	case rpcErr.Code != code:
+		return fail("%s failed with code %d (%s), expected %d", what, rpcErr.Code, rpcErr.Message, code)
+	}
+	return pass("")
+}
+
+// validateContent checks a content block against the 2025-06-18 content
+// types and returns its type
+func validateContent(data json.RawMessage) (string, error) {
+	var block map[string]interface{}
+	if err := json.Unmarshal(data, &block); err != nil {
+		return "", fmt.Errorf("content must be an object: %w", err)
+	}
+
+	typ, _ := block["type"].(string)
+	switch typ {
+	case types.ContentTypeText:
+		if _, ok := block["text"].(string); !ok {
+			return typ, errors.New("text content needs text")
+		}
+	case types.ContentTypeImage, types.ContentTypeAudio:
+		if err := validateBase64(block["data"]); err != nil {
+			return typ, fmt.Errorf("%s content data: %w", typ, err)
+		}
+		if mimeType, _ := block["mimeType"].(string); mimeType == "" {
+			return typ, fmt.Errorf("%s content needs a mimeType", typ)
+		}
+	case types.ContentTypeResourceLink:
+		uri, _ := block["uri"].(string)
+		name, _ := block["name"].(string)
+		if uri == "" || name == "" {
+			return typ, errors.New("resource links need a uri and a name")
+		}
+	case types.ContentTypeResource:
+		resource, ok := block["resource"].(map[string]interface{})
+		if !ok {
+			return typ, errors.New("embedded resources need a resource object")
+		}
+		if err := validateResourceContents(resource); err != nil {
+			return typ, err
+		}
+	default:
+		return typ, fmt.Errorf("unknown content type %q", typ)
+	}
+
+	if _, err := types.UnmarshalContentBlock(data); err != nil {
+		return typ, fmt.Errorf("content does not decode: %w", err)
+	}
+	return typ, nil
+}
+
+// validateResourceContents checks that resource contents have a URI and
+// either text or a base64 blob
+func validateResourceContents(contents map[string]interface{}) error {
+	if uri, _ := contents["uri"].(string); uri == "" {
+		return errors.New("resource contents need a uri")
+	}
+	text, hasText := contents["text"]
+	blob, hasBlob := contents["blob"]
+	switch {
+	case hasText == hasBlob:
+		return errors.New("resource contents need either text or blob")
+	case hasText:
+		if _, ok := text.(string); !ok {
+			return errors.New("resource text must be a string")
+		}
+	default:
+		if err := validateBase64(blob); err != nil {
+			return fmt.Errorf("resource blob: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateBase64 checks that v is a non-empty base64 string
+func validateBase64(v interface{}) error {
+	s, _ := v.(string)
+	if s == "" {
+		return errors.New("missing base64 data")
+	}
+	if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+		return fmt.Errorf("invalid base64: %w", err)
+	}
+	return nil
+}
+
+// isObject reports whether data is a JSON object
+func isObject(data json.RawMessage) bool {
+	var object map[string]json.RawMessage
+	return json.Unmarshal(data, &object) == nil && object != nil
+}
+
+// errorText formats an error response for a report detail
+func errorText(err *rpcError) string {
+	if err == nil {
+		return ""
+	}
+	return "error " + err.Error()
+}
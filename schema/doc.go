@@ -0,0 +1,62 @@
+/*
+Package schema provides JSON Schema validation for MCP tool schemas.
+
+MCP tools describe their arguments and output with JSON Schema
+(types.ToolInputSchema and types.ToolOutputSchema). This package validates
+decoded JSON values against such schemas so that clients can reject bad tool
+arguments before a round trip and detect malformed tool output before it
+reaches application code.
+
+# Basic Usage
+
+Validate a value against a schema:
+
+	err := schema.Validate(tool.OutputSchema, result.StructuredContent)
+	if err != nil {
+		var verrs schema.ValidationErrors
+		if errors.As(err, &verrs) {
+			for _, e := range verrs {
+				fmt.Printf("%s: %s\n", e.Path, e.Message)
+			}
+		}
+	}
+
+Both the schema and the value may be any JSON-serializable Go value, including
+the schema structs from the types package and plain map[string]interface{}
+documents.
+
+# Supported Keywords
+
+The validator understands the keywords used by MCP tool schemas:
+
+  - type (a single type name or a list of type names)
+  - enum
+  - properties, required and additionalProperties for nested objects
+  - items, minItems and maxItems for arrays
+  - minLength, maxLength and pattern for strings
+  - minimum, maximum, exclusiveMinimum and exclusiveMaximum for numbers
+  - $ref with local JSON pointers such as "#/$defs/address"
+
+Unknown keywords are ignored, so schemas using other JSON Schema features are
+still validated on the keywords above.
+
+# Schemas From Go Types
+
+Reflect and For derive a schema from a Go type, reading field names from json
+tags and extra keywords from jsonschema tags:
+
+	type Input struct {
+		City string `json:"city" jsonschema:"description=City name"`
+		Days int    `json:"days,omitempty" jsonschema:"minimum=1,maximum=14"`
+	}
+
+	s, err := schema.For[Input]()
+
+# Error Reporting
+
+Validation collects every failure instead of stopping at the first one. Each
+ValidationError carries the dotted path of the failing field (for example
+"address.zip" or "items[2].name"), the schema keyword that failed and a human
+readable message.
+*/
+package schema
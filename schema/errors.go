@@ -0,0 +1,42 @@
+// Package schema provides JSON Schema validation for MCP tool schemas
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes a single schema violation
+type ValidationError struct {
+	Path    string // Dotted path of the failing field, empty for the root value
+	Keyword string // Schema keyword that failed (e.g. "type", "required")
+	Message string // Human readable description of the failure
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("value: %s", e.Message)
+	}
+	return fmt.Sprintf("field %q: %s", e.Path, e.Message)
+}
+
+// ValidationErrors is the list of violations found while validating a value
+type ValidationErrors []*ValidationError
+
+// Error implements the error interface
+func (ve ValidationErrors) Error() string {
+	messages := make([]string, 0, len(ve))
+	for _, e := range ve {
+		messages = append(messages, e.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// joinPath appends a property name to a dotted path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,319 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// For returns the JSON Schema of the JSON encoding of T. See Reflect.
+func For[T any]() (map[string]interface{}, error) {
+	return Reflect(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Reflect returns the JSON Schema of the JSON encoding of values of type t.
+//
+// Struct fields are named by their json tags and are required unless the
+// tag has omitempty. Pointer, slice and map fields without omitempty, and
+// such list items and map values, also accept null, the encoding of their
+// nil values. Structs do not allow additional properties. The
+// jsonschema tag adds keywords to a field as comma-separated key=value pairs,
+// with \, for a literal comma:
+//
+//	City  string  `json:"city" jsonschema:"description=City name\, e.g. Paris"`
+//	Units string  `json:"units,omitempty" jsonschema:"enum=metric|imperial"`
+//	Days  int     `json:"days" jsonschema:"minimum=1,maximum=14"`
+//	Note  *string `json:"note" jsonschema:"optional"`
+//
+// Supported keys are description, title, format, pattern, enum (values
+// separated by |), minimum, maximum, exclusiveMinimum, exclusiveMaximum,
+// minLength, maxLength, minItems and maxItems, plus the flags required and
+// optional, which override the omitempty rule.
+//
+// Recursive types, channels, functions and complex numbers are not supported.
+func Reflect(t reflect.Type) (map[string]interface{}, error) {
+	r := &reflector{seen: make(map[reflect.Type]bool)}
+	return r.schema(t)
+}
+
+// reflector builds schemas, tracking the struct types being expanded to detect recursion
+type reflector struct {
+	seen map[reflect.Type]bool
+}
+
+// schema returns the schema of type t
+func (r *reflector) schema(t reflect.Type) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	case rawMessageType:
+		return map[string]interface{}{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return map[string]interface{}{"type": "integer", "minimum": 0}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}, nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			// encoding/json encodes []byte as a base64 string
+			return map[string]interface{}{"type": "string", "contentEncoding": "base64"}, nil
+		}
+		items, err := r.nullable(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		s := map[string]interface{}{"type": "array", "items": items}
+		if t.Kind() == reflect.Array {
+			s["minItems"] = t.Len()
+			s["maxItems"] = t.Len()
+		}
+		return s, nil
+
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type %s", t.Key())
+		}
+		values, err := r.nullable(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "object", "additionalProperties": values}, nil
+
+	case reflect.Struct:
+		return r.structSchema(t)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// nullable returns the schema of type t, allowing null for pointers, slices
+// and maps, whose nil values encode to null
+func (r *reflector) nullable(t reflect.Type) (map[string]interface{}, error) {
+	s, err := r.schema(t)
+	if err != nil {
+		return nil, err
+	}
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		if typ, ok := s["type"].(string); ok {
+			s["type"] = []interface{}{typ, "null"}
+		}
+	}
+	return s, nil
+}
+
+// structSchema returns the object schema of a struct type
+func (r *reflector) structSchema(t reflect.Type) (map[string]interface{}, error) {
+	if r.seen[t] {
+		return nil, fmt.Errorf("recursive type %s is not supported", t)
+	}
+	r.seen[t] = true
+	defer delete(r.seen, t)
+
+	properties := make(map[string]interface{})
+	required := []string{}
+	if err := r.addFields(t, properties, &required); err != nil {
+		return nil, err
+	}
+
+	s := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s, nil
+}
+
+// addFields adds the properties of the fields of struct type t, flattening
+// embedded structs the way encoding/json does
+func (r *reflector) addFields(t reflect.Type, properties map[string]interface{}, required *[]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(jsonTag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if err := r.addFields(embedded, properties, required); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		// Nil values of fields without omitempty are encoded as null
+		isRequired := !strings.Contains(","+options+",", ",omitempty,")
+		schemaOf := r.schema
+		if isRequired {
+			schemaOf = r.nullable
+		}
+		s, err := schemaOf(field.Type)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		if tag, ok := field.Tag.Lookup("jsonschema"); ok {
+			if isRequired, err = applyTag(s, tag, field.Type, isRequired); err != nil {
+				return fmt.Errorf("field %s: %w", field.Name, err)
+			}
+		}
+
+		properties[name] = s
+		if isRequired {
+			*required = append(*required, name)
+		}
+	}
+	return nil
+}
+
+// applyTag adds the keywords of a jsonschema tag to s and returns whether
+// the field is required
+func applyTag(s map[string]interface{}, tag string, t reflect.Type, required bool) (bool, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	for _, item := range splitTag(tag) {
+		key, value, hasValue := strings.Cut(item, "=")
+		switch key {
+		case "":
+			continue
+		case "required":
+			required = true
+			continue
+		case "optional":
+			required = false
+			continue
+		}
+		if !hasValue {
+			return required, fmt.Errorf("jsonschema tag key %q needs a value", key)
+		}
+
+		switch key {
+		case "description", "title", "format", "pattern":
+			s[key] = value
+
+		case "enum":
+			var values []interface{}
+			for _, item := range strings.Split(value, "|") {
+				v, err := parseValue(item, t)
+				if err != nil {
+					return required, fmt.Errorf("enum value %q: %w", item, err)
+				}
+				values = append(values, v)
+			}
+			// Enums on list fields constrain the items
+			target := s
+			if items, ok := s["items"].(map[string]interface{}); ok {
+				target = items
+			}
+			if allowsNull(target) {
+				values = append(values, nil)
+			}
+			target["enum"] = values
+
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return required, fmt.Errorf("%s must be a number: %q", key, value)
+			}
+			s[key] = n
+
+		case "minLength", "maxLength", "minItems", "maxItems":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return required, fmt.Errorf("%s must be a non-negative integer: %q", key, value)
+			}
+			s[key] = n
+
+		default:
+			return required, fmt.Errorf("unknown jsonschema tag key %q", key)
+		}
+	}
+	return required, nil
+}
+
+// allowsNull reports whether the type keyword of s includes null
+func allowsNull(s map[string]interface{}) bool {
+	types, _ := s["type"].([]interface{})
+	for _, t := range types {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// splitTag splits a jsonschema tag at commas not escaped with a backslash
+func splitTag(tag string) []string {
+	var items []string
+	var b strings.Builder
+	for i := 0; i < len(tag); i++ {
+		switch {
+		case tag[i] == '\\' && i+1 < len(tag) && tag[i+1] == ',':
+			b.WriteByte(',')
+			i++
+		case tag[i] == ',':
+			items = append(items, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(tag[i])
+		}
+	}
+	return append(items, b.String())
+}
+
+// parseValue parses an enum value for a field of type t
+func parseValue(s string, t reflect.Type) (interface{}, error) {
+	switch t.Kind() {
+	case reflect.Bool:
+		return strconv.ParseBool(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseInt(s, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(s, 64)
+	case reflect.Slice, reflect.Array:
+		return parseValue(s, t.Elem())
+	}
+	return s, nil
+}
@@ -0,0 +1,142 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type reflectAddress struct {
+	Street string `json:"street"`
+	Zip    string `json:"zip,omitempty" jsonschema:"pattern=^[0-9]{5}$"`
+}
+
+type reflectBase struct {
+	ID string `json:"id" jsonschema:"description=Unique id"`
+}
+
+type reflectInput struct {
+	reflectBase
+	City     string          `json:"city" jsonschema:"description=City name\\, e.g. Paris,minLength=1"`
+	Units    string          `json:"units,omitempty" jsonschema:"enum=metric|imperial"`
+	Days     int             `json:"days" jsonschema:"minimum=1,maximum=14"`
+	Ratio    float64         `json:"ratio,omitempty" jsonschema:"exclusiveMinimum=0"`
+	Note     *string         `json:"note" jsonschema:"optional"`
+	Force    bool            `json:"force,omitempty" jsonschema:"required"`
+	Tags     []string        `json:"tags,omitempty" jsonschema:"enum=a|b,maxItems=3"`
+	Address  reflectAddress  `json:"address"`
+	Labels   map[string]int  `json:"labels,omitempty"`
+	When     time.Time       `json:"when,omitempty"`
+	Data     []byte          `json:"data,omitempty"`
+	Extra    json.RawMessage `json:"extra,omitempty"`
+	Any      interface{}     `json:"any,omitempty"`
+	Count    uint8           `json:"count,omitempty"`
+	Pair     [2]int          `json:"pair,omitempty"`
+	Ignored  string          `json:"-"`
+	hidden   string
+	Untagged string            `jsonschema:"title=Untagged"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Aliases  []*string         `json:"aliases" jsonschema:"enum=x|y"`
+}
+
+func TestReflect(t *testing.T) {
+	got, err := For[reflectInput]()
+	if err != nil {
+		t.Fatalf("For failed: %v", err)
+	}
+
+	expected := `{
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["id", "city", "days", "force", "address", "Untagged", "aliases"],
+		"properties": {
+			"id": {"type": "string", "description": "Unique id"},
+			"city": {"type": "string", "description": "City name, e.g. Paris", "minLength": 1},
+			"units": {"type": "string", "enum": ["metric", "imperial"]},
+			"days": {"type": "integer", "minimum": 1, "maximum": 14},
+			"ratio": {"type": "number", "exclusiveMinimum": 0},
+			"note": {"type": ["string", "null"]},
+			"force": {"type": "boolean"},
+			"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}, "maxItems": 3},
+			"address": {
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["street"],
+				"properties": {
+					"street": {"type": "string"},
+					"zip": {"type": "string", "pattern": "^[0-9]{5}$"}
+				}
+			},
+			"labels": {"type": "object", "additionalProperties": {"type": "integer"}},
+			"when": {"type": "string", "format": "date-time"},
+			"data": {"type": "string", "contentEncoding": "base64"},
+			"extra": {},
+			"any": {},
+			"count": {"type": "integer", "minimum": 0},
+			"pair": {"type": "array", "items": {"type": "integer"}, "minItems": 2, "maxItems": 2},
+			"Untagged": {"type": "string", "title": "Untagged"},
+			"headers": {"type": "object", "additionalProperties": {"type": "string"}},
+			"aliases": {"type": ["array", "null"], "items": {"type": ["string", "null"], "enum": ["x", "y", null]}}
+		}
+	}`
+
+	var want, have interface{}
+	json.Unmarshal([]byte(expected), &want)
+	data, _ := json.Marshal(got)
+	json.Unmarshal(data, &have)
+	if !reflect.DeepEqual(want, have) {
+		t.Errorf("Schema = %s", data)
+	}
+}
+
+func TestReflectValidates(t *testing.T) {
+	s, err := For[reflectAddress]()
+	if err != nil {
+		t.Fatalf("For failed: %v", err)
+	}
+
+	if err := Validate(s, map[string]interface{}{"street": "Main St", "zip": "12345"}); err != nil {
+		t.Errorf("Valid value rejected: %v", err)
+	}
+	err = Validate(s, map[string]interface{}{"zip": "abc", "country": "US"})
+	verrs, ok := err.(ValidationErrors)
+	if !ok || len(verrs) != 3 {
+		t.Errorf("Expected 3 validation errors, got %v", err)
+	}
+}
+
+func TestReflectErrors(t *testing.T) {
+	type recursive struct {
+		Children []recursive `json:"children"`
+	}
+	type badTag struct {
+		N int `json:"n" jsonschema:"minimum=low"`
+	}
+	type unknownKey struct {
+		N int `json:"n" jsonschema:"color=red"`
+	}
+	type badEnum struct {
+		N int `json:"n" jsonschema:"enum=1|two"`
+	}
+
+	tests := map[string]reflect.Type{
+		"recursive":   reflect.TypeOf(recursive{}),
+		"minimum":     reflect.TypeOf(badTag{}),
+		"unknown":     reflect.TypeOf(unknownKey{}),
+		"enum":        reflect.TypeOf(badEnum{}),
+		"unsupported": reflect.TypeOf(struct{ C chan int }{}),
+		"map key":     reflect.TypeOf(map[int]string{}),
+	}
+	for name, typ := range tests {
+		_, err := Reflect(typ)
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+			continue
+		}
+		if name != "map key" && !strings.Contains(err.Error(), "field") {
+			t.Errorf("%s: error %q does not name the field", name, err)
+		}
+	}
+}
@@ -0,0 +1,379 @@
+// Package schema provides JSON Schema validation for MCP tool schemas
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxRefDepth bounds $ref resolution so that cyclic schemas cannot recurse forever
+const maxRefDepth = 64
+
+// Validate checks value against the given JSON Schema.
+// It returns nil when the value conforms, ValidationErrors when it does not,
+// and a plain error when the schema or value cannot be interpreted.
+func Validate(schema interface{}, value interface{}) error {
+	doc, err := normalize(schema)
+	if err != nil {
+		return fmt.Errorf("invalid schema: %w", err)
+	}
+
+	schemaMap, ok := doc.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("invalid schema: expected object, got %s", typeOf(doc))
+	}
+
+	instance, err := normalize(value)
+	if err != nil {
+		return fmt.Errorf("invalid value: %w", err)
+	}
+
+	v := &validator{root: schemaMap}
+	v.validate(schemaMap, instance, "")
+
+	if len(v.errors) > 0 {
+		return v.errors
+	}
+	return nil
+}
+
+// validator accumulates errors while walking a schema
+type validator struct {
+	root     map[string]interface{}
+	errors   ValidationErrors
+	refDepth int
+	patterns map[string]*regexp.Regexp
+}
+
+// addError records a validation failure
+func (v *validator) addError(path, keyword, format string, args ...interface{}) {
+	v.errors = append(v.errors, &ValidationError{
+		Path:    path,
+		Keyword: keyword,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// validate checks a single value against a schema node
+func (v *validator) validate(schema map[string]interface{}, value interface{}, path string) {
+	if ref, ok := schema["$ref"].(string); ok {
+		v.validateRef(ref, value, path)
+		return
+	}
+
+	if typ, ok := schema["type"]; ok {
+		if !v.validateType(typ, value, path) {
+			// Further keywords are meaningless once the type is wrong
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		v.validateEnum(enum, value, path)
+	}
+
+	switch val := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(schema, val, path)
+	case []interface{}:
+		v.validateArray(schema, val, path)
+	case string:
+		v.validateString(schema, val, path)
+	case float64:
+		v.validateNumber(schema, val, path)
+	}
+}
+
+// validateRef resolves a local "$ref" and validates the value against its target
+func (v *validator) validateRef(ref string, value interface{}, path string) {
+	target, err := v.resolveRef(ref)
+	if err != nil {
+		v.addError(path, "$ref", "%v", err)
+		return
+	}
+
+	if v.refDepth >= maxRefDepth {
+		v.addError(path, "$ref", "reference %q nests too deeply", ref)
+		return
+	}
+
+	v.refDepth++
+	v.validate(target, value, path)
+	v.refDepth--
+}
+
+// resolveRef looks up a JSON pointer reference such as "#/$defs/address" in the root schema
+func (v *validator) resolveRef(ref string) (map[string]interface{}, error) {
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported reference %q: only local references are allowed", ref)
+	}
+
+	var node interface{} = v.root
+	pointer := strings.TrimPrefix(ref, "#")
+	if pointer != "" {
+		for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+			token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+			obj, ok := node.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unresolvable reference %q", ref)
+			}
+			if node, ok = obj[token]; !ok {
+				return nil, fmt.Errorf("unresolvable reference %q", ref)
+			}
+		}
+	}
+
+	target, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("reference %q does not point to a schema", ref)
+	}
+	return target, nil
+}
+
+// validateEnum checks that the value equals one of the allowed values
+func (v *validator) validateEnum(enum []interface{}, value interface{}, path string) {
+	for _, allowed := range enum {
+		if reflect.DeepEqual(allowed, value) {
+			return
+		}
+	}
+
+	names := make([]string, 0, len(enum))
+	for _, allowed := range enum {
+		data, _ := json.Marshal(allowed)
+		names = append(names, string(data))
+	}
+	v.addError(path, "enum", "value must be one of [%s]", strings.Join(names, ", "))
+}
+
+// validateType checks the "type" keyword, which may be a string or a list of strings
+func (v *validator) validateType(typ interface{}, value interface{}, path string) bool {
+	var allowed []string
+	switch t := typ.(type) {
+	case string:
+		allowed = []string{t}
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok {
+				allowed = append(allowed, s)
+			}
+		}
+	default:
+		return true
+	}
+
+	for _, name := range allowed {
+		if matchesType(name, value) {
+			return true
+		}
+	}
+
+	if len(allowed) == 1 {
+		v.addError(path, "type", "expected %s, got %s", allowed[0], typeOf(value))
+	} else {
+		v.addError(path, "type", "expected one of %v, got %s", allowed, typeOf(value))
+	}
+	return false
+}
+
+// validateObject checks the "required", "properties" and "additionalProperties" keywords
+func (v *validator) validateObject(schema map[string]interface{}, obj map[string]interface{}, path string) {
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, item := range required {
+			name, ok := item.(string)
+			if !ok {
+				continue
+			}
+			if _, present := obj[name]; !present {
+				v.addError(joinPath(path, name), "required", "required field is missing")
+			}
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	// Walk fields in a stable order so error lists are deterministic
+	for _, name := range sortedKeys(obj) {
+		fieldPath := joinPath(path, name)
+
+		if propSchema, ok := properties[name]; ok {
+			if propMap, ok := propSchema.(map[string]interface{}); ok {
+				v.validate(propMap, obj[name], fieldPath)
+			}
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.addError(fieldPath, "additionalProperties", "unknown field is not allowed")
+			}
+		case map[string]interface{}:
+			v.validate(additional, obj[name], fieldPath)
+		}
+	}
+}
+
+// validateArray checks the "items", "minItems" and "maxItems" keywords
+func (v *validator) validateArray(schema map[string]interface{}, arr []interface{}, path string) {
+	if minItems, ok := schema["minItems"].(float64); ok && float64(len(arr)) < minItems {
+		v.addError(path, "minItems", "expected at least %v items, got %d", minItems, len(arr))
+	}
+	if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(arr)) > maxItems {
+		v.addError(path, "maxItems", "expected at most %v items, got %d", maxItems, len(arr))
+	}
+
+	items, ok := schema["items"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	for i, item := range arr {
+		v.validate(items, item, fmt.Sprintf("%s[%d]", path, i))
+	}
+}
+
+// validateString checks the "minLength", "maxLength" and "pattern" keywords
+func (v *validator) validateString(schema map[string]interface{}, str string, path string) {
+	length := utf8.RuneCountInString(str)
+	if minLength, ok := schema["minLength"].(float64); ok && float64(length) < minLength {
+		v.addError(path, "minLength", "expected at least %v characters, got %d", minLength, length)
+	}
+	if maxLength, ok := schema["maxLength"].(float64); ok && float64(length) > maxLength {
+		v.addError(path, "maxLength", "expected at most %v characters, got %d", maxLength, length)
+	}
+
+	pattern, ok := schema["pattern"].(string)
+	if !ok {
+		return
+	}
+	re, err := v.compilePattern(pattern)
+	if err != nil {
+		v.addError(path, "pattern", "invalid pattern %q in schema: %v", pattern, err)
+		return
+	}
+	if !re.MatchString(str) {
+		v.addError(path, "pattern", "value does not match pattern %q", pattern)
+	}
+}
+
+// validateNumber checks the inclusive and exclusive minimum and maximum keywords
+func (v *validator) validateNumber(schema map[string]interface{}, num float64, path string) {
+	if minimum, ok := schema["minimum"].(float64); ok && num < minimum {
+		v.addError(path, "minimum", "value %v is less than minimum %v", num, minimum)
+	}
+	if maximum, ok := schema["maximum"].(float64); ok && num > maximum {
+		v.addError(path, "maximum", "value %v is greater than maximum %v", num, maximum)
+	}
+	if exclusiveMinimum, ok := schema["exclusiveMinimum"].(float64); ok && num <= exclusiveMinimum {
+		v.addError(path, "exclusiveMinimum", "value %v must be greater than %v", num, exclusiveMinimum)
+	}
+	if exclusiveMaximum, ok := schema["exclusiveMaximum"].(float64); ok && num >= exclusiveMaximum {
+		v.addError(path, "exclusiveMaximum", "value %v must be less than %v", num, exclusiveMaximum)
+	}
+}
+
+// compilePattern compiles a regular expression, caching the result per validation run
+func (v *validator) compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := v.patterns[pattern]; ok {
+		return re, nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	if v.patterns == nil {
+		v.patterns = make(map[string]*regexp.Regexp)
+	}
+	v.patterns[pattern] = re
+	return re, nil
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// matchesType reports whether value is an instance of the named JSON type
+func matchesType(name string, value interface{}) bool {
+	switch name {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f) && !math.IsInf(f, 0)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "null":
+		return value == nil
+	default:
+		// Unknown type names are not enforced
+		return true
+	}
+}
+
+// typeOf returns the JSON type name of a decoded value
+func typeOf(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// normalize converts an arbitrary Go value into its generic JSON representation
+func normalize(value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case nil, bool, string, float64:
+		return value, nil
+	}
+
+	// Maps and slices are round-tripped too, since they may hold Go-typed values
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
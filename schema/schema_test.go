@@ -0,0 +1,334 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate_Types(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  map[string]interface{}
+		value   interface{}
+		wantErr bool
+	}{
+		{name: "string ok", schema: map[string]interface{}{"type": "string"}, value: "hello"},
+		{name: "string mismatch", schema: map[string]interface{}{"type": "string"}, value: 42, wantErr: true},
+		{name: "integer ok", schema: map[string]interface{}{"type": "integer"}, value: 42},
+		{name: "integer rejects fraction", schema: map[string]interface{}{"type": "integer"}, value: 4.2, wantErr: true},
+		{name: "number accepts integer", schema: map[string]interface{}{"type": "number"}, value: 7},
+		{name: "boolean ok", schema: map[string]interface{}{"type": "boolean"}, value: true},
+		{name: "null ok", schema: map[string]interface{}{"type": "null"}, value: nil},
+		{name: "array ok", schema: map[string]interface{}{"type": "array"}, value: []string{"a"}},
+		{name: "object mismatch", schema: map[string]interface{}{"type": "object"}, value: []int{1}, wantErr: true},
+		{name: "type list", schema: map[string]interface{}{"type": []interface{}{"string", "null"}}, value: nil},
+		{name: "type list mismatch", schema: map[string]interface{}{"type": []interface{}{"string", "null"}}, value: 1, wantErr: true},
+		{name: "no type accepts anything", schema: map[string]interface{}{}, value: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.schema, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_NestedObjectErrors(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"address": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"zip": map[string]interface{}{"type": "string"},
+				},
+				"required": []interface{}{"city"},
+			},
+		},
+		"required": []interface{}{"name", "address"},
+	}
+
+	value := map[string]interface{}{
+		"address": map[string]interface{}{
+			"zip": 12345,
+		},
+	}
+
+	err := Validate(schema, value)
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("Expected ValidationErrors, got %T", err)
+	}
+
+	expected := map[string]string{
+		"name":         "required",
+		"address.city": "required",
+		"address.zip":  "type",
+	}
+	if len(verrs) != len(expected) {
+		t.Fatalf("Expected %d errors, got %d: %v", len(expected), len(verrs), err)
+	}
+	for _, e := range verrs {
+		if keyword, ok := expected[e.Path]; !ok || keyword != e.Keyword {
+			t.Errorf("Unexpected error at %q (%s): %s", e.Path, e.Keyword, e.Message)
+		}
+	}
+}
+
+func TestValidate_StructSchema(t *testing.T) {
+	type outputSchema struct {
+		Type       string                 `json:"type"`
+		Properties map[string]interface{} `json:"properties,omitempty"`
+		Required   []string               `json:"required,omitempty"`
+	}
+
+	schema := outputSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"temperature": map[string]interface{}{"type": "number"},
+		},
+		Required: []string{"temperature"},
+	}
+
+	if err := Validate(schema, map[string]interface{}{"temperature": 21.5}); err != nil {
+		t.Errorf("Expected valid value, got %v", err)
+	}
+
+	err := Validate(schema, map[string]interface{}{"temperature": "warm"})
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	if got := err.Error(); got != `field "temperature": expected number, got string` {
+		t.Errorf("Unexpected error message: %s", got)
+	}
+}
+
+func TestValidate_InvalidSchema(t *testing.T) {
+	err := Validate("not a schema", map[string]interface{}{})
+	if err == nil {
+		t.Fatal("Expected error for non-object schema")
+	}
+
+	var verrs ValidationErrors
+	if errors.As(err, &verrs) {
+		t.Error("Invalid schema should not be reported as a validation failure")
+	}
+}
+
+func TestValidate_Keywords(t *testing.T) {
+	tests := []struct {
+		name        string
+		schema      map[string]interface{}
+		value       interface{}
+		wantKeyword string
+	}{
+		{
+			name:   "enum ok",
+			schema: map[string]interface{}{"enum": []interface{}{"add", "subtract"}},
+			value:  "add",
+		},
+		{
+			name:        "enum mismatch",
+			schema:      map[string]interface{}{"enum": []interface{}{"add", "subtract"}},
+			value:       "divide",
+			wantKeyword: "enum",
+		},
+		{
+			name:        "minimum",
+			schema:      map[string]interface{}{"type": "integer", "minimum": 0},
+			value:       -1,
+			wantKeyword: "minimum",
+		},
+		{
+			name:        "maximum",
+			schema:      map[string]interface{}{"type": "number", "maximum": 150},
+			value:       151,
+			wantKeyword: "maximum",
+		},
+		{
+			name:        "exclusive minimum",
+			schema:      map[string]interface{}{"exclusiveMinimum": 0},
+			value:       0,
+			wantKeyword: "exclusiveMinimum",
+		},
+		{
+			name:        "exclusive maximum",
+			schema:      map[string]interface{}{"exclusiveMaximum": 10},
+			value:       10,
+			wantKeyword: "exclusiveMaximum",
+		},
+		{
+			name:        "min length counts runes",
+			schema:      map[string]interface{}{"minLength": 3},
+			value:       "hé",
+			wantKeyword: "minLength",
+		},
+		{
+			name:        "max length",
+			schema:      map[string]interface{}{"maxLength": 2},
+			value:       "abc",
+			wantKeyword: "maxLength",
+		},
+		{
+			name:   "pattern ok",
+			schema: map[string]interface{}{"pattern": "^[a-z]+$"},
+			value:  "abc",
+		},
+		{
+			name:        "pattern mismatch",
+			schema:      map[string]interface{}{"pattern": "^[a-z]+$"},
+			value:       "ABC",
+			wantKeyword: "pattern",
+		},
+		{
+			name:        "min items",
+			schema:      map[string]interface{}{"type": "array", "minItems": 2},
+			value:       []string{"a"},
+			wantKeyword: "minItems",
+		},
+		{
+			name:        "max items",
+			schema:      map[string]interface{}{"type": "array", "maxItems": 1},
+			value:       []string{"a", "b"},
+			wantKeyword: "maxItems",
+		},
+		{
+			name: "additional properties rejected",
+			schema: map[string]interface{}{
+				"type":                 "object",
+				"properties":           map[string]interface{}{"a": map[string]interface{}{}},
+				"additionalProperties": false,
+			},
+			value:       map[string]interface{}{"a": 1, "b": 2},
+			wantKeyword: "additionalProperties",
+		},
+		{
+			name: "additional properties schema",
+			schema: map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+			value:       map[string]interface{}{"b": 2},
+			wantKeyword: "type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.schema, tt.value)
+			if tt.wantKeyword == "" {
+				if err != nil {
+					t.Errorf("Expected valid value, got %v", err)
+				}
+				return
+			}
+
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("Expected ValidationErrors, got %v", err)
+			}
+			if verrs[0].Keyword != tt.wantKeyword {
+				t.Errorf("Expected keyword %q, got %q (%s)", tt.wantKeyword, verrs[0].Keyword, verrs[0].Message)
+			}
+		})
+	}
+}
+
+func TestValidate_ArrayItemPaths(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"tags": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+
+	err := Validate(schema, map[string]interface{}{"tags": []interface{}{"ok", 2, "fine", false}})
+
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	if len(verrs) != 2 || verrs[0].Path != "tags[1]" || verrs[1].Path != "tags[3]" {
+		t.Errorf("Unexpected errors: %v", err)
+	}
+}
+
+func TestValidate_Refs(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"home": map[string]interface{}{"$ref": "#/$defs/address"},
+			"work": map[string]interface{}{"$ref": "#/definitions/address"},
+		},
+		"$defs": map[string]interface{}{
+			"address": map[string]interface{}{
+				"type":     "object",
+				"required": []interface{}{"city"},
+				"properties": map[string]interface{}{
+					"city": map[string]interface{}{"type": "string"},
+				},
+			},
+		},
+		"definitions": map[string]interface{}{
+			"address": map[string]interface{}{"$ref": "#/$defs/address"},
+		},
+	}
+
+	err := Validate(schema, map[string]interface{}{
+		"home": map[string]interface{}{"city": "Oslo"},
+		"work": map[string]interface{}{},
+	})
+
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("Expected ValidationErrors, got %v", err)
+	}
+	if len(verrs) != 1 || verrs[0].Path != "work.city" || verrs[0].Keyword != "required" {
+		t.Errorf("Unexpected errors: %v", err)
+	}
+}
+
+func TestValidate_RefErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema map[string]interface{}
+	}{
+		{
+			name:   "missing target",
+			schema: map[string]interface{}{"$ref": "#/$defs/missing"},
+		},
+		{
+			name:   "remote reference",
+			schema: map[string]interface{}{"$ref": "https://example.com/schema.json"},
+		},
+		{
+			name:   "cyclic reference",
+			schema: map[string]interface{}{"$ref": "#"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.schema, "value")
+
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("Expected ValidationErrors, got %v", err)
+			}
+			if verrs[0].Keyword != "$ref" {
+				t.Errorf("Expected $ref error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// maxCompletionValues is the maximum number of values in one completion
+// response, as set by the specification
+const maxCompletionValues = 100
+
+// CompletionProvider returns the completions of a prompt argument or resource
+// template variable. The server returns the first 100 values and reports the
+// total and whether more values exist.
+type CompletionProvider func(ctx context.Context, req *CompletionRequest) ([]string, error)
+
+// CompletionOption attaches a completion provider to a prompt argument or a
+// resource template variable
+type CompletionOption func(providers map[string]CompletionProvider)
+
+// WithCompletion completes the named prompt argument or template variable with provider
+func WithCompletion(argument string, provider CompletionProvider) CompletionOption {
+	return func(providers map[string]CompletionProvider) {
+		providers[argument] = provider
+	}
+}
+
+// CompleteValues returns a provider completing from a fixed list of values.
+// Values starting with the typed value are returned, ignoring case.
+func CompleteValues(values ...string) CompletionProvider {
+	return func(ctx context.Context, req *CompletionRequest) ([]string, error) {
+		prefix := strings.ToLower(req.Value)
+		var matches []string
+		for _, v := range values {
+			if strings.HasPrefix(strings.ToLower(v), prefix) {
+				matches = append(matches, v)
+			}
+		}
+		return matches, nil
+	}
+}
+
+// completionProviders builds the provider map of a prompt or template, or nil without options
+func completionProviders(opts []CompletionOption) map[string]CompletionProvider {
+	if len(opts) == 0 {
+		return nil
+	}
+	providers := make(map[string]CompletionProvider)
+	for _, opt := range opts {
+		opt(providers)
+	}
+	return providers
+}
+
+// lookupCompletion returns the completion provider of a prompt argument or
+// template variable. It fails when the prompt or template does not exist and
+// returns a nil provider when the argument has none.
+func (s *Server) lookupCompletion(ref completionRef, argument string) (CompletionProvider, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	switch ref.Type {
+	case types.ReferenceTypePrompt:
+		p, ok := s.prompts[ref.Name]
+		if !ok {
+			return nil, NewError(CodeInvalidParams, "unknown prompt: %s", ref.Name)
+		}
+		return p.completions[argument], nil
+	case types.ReferenceTypeResource:
+		t, ok := s.templates[ref.URI]
+		if !ok {
+			return nil, NewError(CodeInvalidParams, "unknown resource template: %s", ref.URI)
+		}
+		return t.completions[argument], nil
+	default:
+		return nil, NewError(CodeInvalidParams, "unknown reference type %q", ref.Type)
+	}
+}
+
+// completionRef holds the fields of both reference types
+type completionRef struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	URI  string `json:"uri"`
+}
+
+func handleComplete(ctx context.Context, req *Request) (interface{}, error) {
+	var params struct {
+		Ref      completionRef `json:"ref"`
+		Argument struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"argument"`
+		Context *types.CompletionContext `json:"context"`
+	}
+	if err := decodeParams(req, &params); err != nil {
+		return nil, err
+	}
+
+	provider, err := req.Session.server.lookupCompletion(params.Ref, params.Argument.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &types.CompleteResult{}
+	result.Completion.Values = []string{}
+	if provider == nil {
+		return result, nil
+	}
+
+	compReq := &CompletionRequest{
+		Request:   req,
+		Argument:  params.Argument.Name,
+		Value:     params.Argument.Value,
+		Arguments: map[string]string{},
+	}
+	if params.Ref.Type == types.ReferenceTypePrompt {
+		compReq.Ref = types.PromptReference{BaseMetadata: types.BaseMetadata{Name: params.Ref.Name}, Type: params.Ref.Type}
+	} else {
+		compReq.Ref = types.ResourceTemplateReference{Type: params.Ref.Type, URI: params.Ref.URI}
+	}
+	if params.Context != nil && params.Context.Arguments != nil {
+		compReq.Arguments = params.Context.Arguments
+	}
+
+	values, err := provider(ctx, compReq)
+	if err != nil {
+		return nil, err
+	}
+	total, hasMore := len(values), len(values) > maxCompletionValues
+	if hasMore {
+		values = values[:maxCompletionValues]
+	}
+	if values != nil {
+		result.Completion.Values = values
+	}
+	result.Completion.Total = &total
+	result.Completion.HasMore = &hasMore
+	return result, nil
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+func newCompletionServer(t *testing.T) *Server {
+	t.Helper()
+	srv := New("test", "1.0.0")
+	srv.AddPrompt(types.Prompt{
+		BaseMetadata: types.BaseMetadata{Name: "review"},
+		Arguments: []types.PromptArgument{
+			{BaseMetadata: types.BaseMetadata{Name: "language"}, Required: true},
+			{BaseMetadata: types.BaseMetadata{Name: "style"}},
+		},
+	}, PromptMessages(func(ctx context.Context, req *PromptRequest) ([]types.PromptMessage, error) {
+		return []types.PromptMessage{
+			{Role: "user", Content: types.TextContent{Type: types.ContentTypeText, Text: "Review this " + req.Arguments["language"] + " code"}},
+		}, nil
+	}), WithCompletion("language", CompleteValues("Go", "Python", "PHP", "Rust")))
+
+	err := srv.AddResourceTemplate(types.ResourceTemplate{
+		BaseMetadata: types.BaseMetadata{Name: "issues"},
+		URITemplate:  "issues://{repo}/{number}",
+	}, nil, WithCompletion("number", func(ctx context.Context, req *CompletionRequest) ([]string, error) {
+		if _, ok := req.Ref.(types.ResourceTemplateReference); !ok {
+			return nil, fmt.Errorf("unexpected reference %#v", req.Ref)
+		}
+		var numbers []string
+		for i := 1; i <= 250; i++ {
+			if n := fmt.Sprint(i); strings.HasPrefix(n, req.Value) {
+				numbers = append(numbers, req.Arguments["repo"]+"-"+n)
+			}
+		}
+		return numbers, nil
+	}))
+	if err != nil {
+		t.Fatalf("AddResourceTemplate failed: %v", err)
+	}
+	return srv
+}
+
+func TestPromptArgumentValidation(t *testing.T) {
+	sess := newTestSession(t, newCompletionServer(t))
+	sess.initialize(t)
+
+	response := sess.request(t, "prompts/get", map[string]interface{}{"name": "review", "arguments": map[string]string{"style": "terse"}})
+	if response.Error == nil || response.Error.Code != CodeInvalidParams || !strings.Contains(response.Error.Message, "language") {
+		t.Errorf("Missing required argument: %+v", response.Error)
+	}
+
+	var result types.GetPromptResult
+	sess.call(t, "prompts/get", map[string]interface{}{"name": "review", "arguments": map[string]string{"language": "Go"}}, &result)
+	if len(result.Messages) != 1 {
+		t.Fatalf("Messages = %+v", result.Messages)
+	}
+	if text, ok := result.Messages[0].Content.(types.TextContent); !ok || text.Text != "Review this Go code" {
+		t.Errorf("Message content = %#v", result.Messages[0].Content)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	srv := newCompletionServer(t)
+	c, _ := servePipe(t, context.Background(), srv)
+	defer c.Close()
+	if !c.HasCompletions() {
+		t.Fatal("Server does not advertise completions")
+	}
+	ctx := context.Background()
+
+	result, err := c.CompletePromptArgument(ctx, "review", "language", "p", nil)
+	if err != nil {
+		t.Fatalf("CompletePromptArgument failed: %v", err)
+	}
+	if fmt.Sprint(result.Completion.Values) != "[Python PHP]" || *result.Completion.Total != 2 || *result.Completion.HasMore {
+		t.Errorf("Completion = %+v", result.Completion)
+	}
+
+	result, err = c.CompletePromptArgument(ctx, "review", "style", "", nil)
+	if err != nil || len(result.Completion.Values) != 0 {
+		t.Errorf("Argument without provider: %+v, %v", result, err)
+	}
+
+	result, err = c.CompleteResourceTemplateArgument(ctx, "issues://{repo}/{number}", "number", "", map[string]string{"repo": "mcp"})
+	if err != nil {
+		t.Fatalf("CompleteResourceTemplateArgument failed: %v", err)
+	}
+	values := result.Completion.Values
+	if len(values) != maxCompletionValues || values[0] != "mcp-1" || *result.Completion.Total != 250 || !*result.Completion.HasMore {
+		t.Errorf("Completion = %d values from %v, total %d", len(values), values[0], *result.Completion.Total)
+	}
+
+	if _, err := c.CompletePromptArgument(ctx, "missing", "language", "", nil); err == nil {
+		t.Error("Expected an error for an unknown prompt")
+	}
+	if _, err := c.CompleteResourceTemplateArgument(ctx, "missing://{id}", "id", "", nil); err == nil {
+		t.Error("Expected an error for an unknown template")
+	}
+}
+
+func TestCompletionsCapability(t *testing.T) {
+	sess := newTestSession(t, newTestServer())
+	if caps := sess.initialize(t).Capabilities; caps.Completions != nil {
+		t.Errorf("Completions advertised without providers: %+v", caps.Completions)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Package server provides an MCP server implementation.
+
+Register tools, resources, resource templates and prompts on a Server. The
+capabilities advertised during initialization are built from what is
+registered, and connected clients are notified when the registry changes.
+
+# Basic Usage
+
+	srv := server.New("my-server", "1.0.0",
+		server.WithInstructions("Tools for the weather service"),
+	)
+
+	srv.AddTool(types.Tool{
+		BaseMetadata: types.BaseMetadata{Name: "echo"},
+		Description:  "Echo the text back",
+	}, func(ctx context.Context, req *server.ToolRequest) (*types.CallToolResult, error) {
+		text, _ := req.Arguments["text"].(string)
+		return &types.CallToolResult{
+			Content: []interface{}{types.TextContent{Type: types.ContentTypeText, Text: text}},
+		}, nil
+	})
+
+	srv.AddResourceTemplate(types.ResourceTemplate{
+		BaseMetadata: types.BaseMetadata{Name: "files"},
+		URITemplate:  "file:///{path}",
+	}, func(ctx context.Context, req *server.ResourceRequest) (*types.ReadResourceResult, error) {
+		return readFile(req.Vars["path"])
+	})
+
+Resource handlers can build their result from typed contents with ReadResult:
+
+	return server.ReadResult(types.TextResourceContents{
+		ResourceContents: types.ResourceContents{URI: req.URI, MimeType: "text/markdown"},
+		Text:             text,
+	}), nil
+
+Clients subscribe to resources with resources/subscribe. Call
+NotifyResourceUpdated when a resource changes to tell its subscribers.
+
+Prompts declare their arguments; requests missing a required argument are
+rejected before the handler runs. PromptMessages adapts a function returning
+the messages, and WithCompletion answers completion/complete requests for an
+argument or template variable:
+
+	srv.AddPrompt(types.Prompt{
+		BaseMetadata: types.BaseMetadata{Name: "review"},
+		Arguments: []types.PromptArgument{
+			{BaseMetadata: types.BaseMetadata{Name: "language"}, Required: true},
+		},
+	}, server.PromptMessages(reviewMessages),
+		server.WithCompletion("language", server.CompleteValues("Go", "Python", "Rust")))
+
+Tools implemented by typed Go functions can be registered with the AddTool
+function, which derives the input and output schemas from the struct types
+and validates and decodes the arguments:
+
+	type ForecastInput struct {
+		City string `json:"city" jsonschema:"description=City name"`
+		Days int    `json:"days" jsonschema:"minimum=1,maximum=14"`
+	}
+
+	err := server.AddTool(srv, "forecast", "Daily forecast",
+		func(ctx context.Context, in ForecastInput) (Forecast, error) {
+			return lookupForecast(ctx, in.City, in.Days)
+		})
+
+# Serving
+
+ServeStdio serves one client over the process's stdin and stdout until stdin
+ends or the process is signalled:
+
+	if err := server.ServeStdio(context.Background(), srv); err != nil {
+		log.Fatal(err)
+	}
+
+Log to stderr or through the server logger; anything else written to stdout
+corrupts the protocol stream.
+
+NewHTTPHandler serves any number of clients over the Streamable HTTP transport:
+
+	http.Handle("/mcp", server.NewHTTPHandler(srv))
+	log.Fatal(http.ListenAndServe("localhost:8080", nil))
+
+Responses are streamed as SSE when the client accepts it, carrying the
+notifications and requests the server sends while handling the request.
+Clients that lose a stream resume it with Last-Event-ID. A session is created
+only when initialize succeeds and is terminated after DefaultSessionIdleTimeout
+without requests or open streams; WithSessionIdleTimeout changes the limit.
+
+# Sessions
+
+Each client connection is served by a Session. Custom transports create one with
+NewSession, pass every message read from the client to HandleMessage, write
+the returned response and close the session when the connection ends:
+
+	sess := srv.NewSession(func(ctx context.Context, msg []byte) error {
+		return writeLine(conn, msg)
+	})
+	defer sess.Close()
+
+	for msg := range incoming {
+		go func(msg []byte) {
+			if resp := sess.HandleMessage(ctx, msg); resp != nil {
+				writeLine(conn, resp)
+			}
+		}(msg)
+	}
+
+Handlers can reach the session of the request they serve with
+SessionFromContext.
+
+# Requests to the Client
+
+Handlers ask the client for an LLM completion with CreateMessage, ask the user
+for input with Elicit and read the client's roots with ListRoots, called on
+the request they serve or on its Session. Pass the handler's context so that
+the request travels with the response being prepared:
+
+	roots, err := req.ListRoots(ctx)
+	if errors.Is(err, server.ErrNotSupported) {
+		// The client did not declare the roots capability
+	}
+
+The calls block until the client responds, ctx is done or the session closes.
+
+# Progress and Cancellation
+
+Long-running handlers report progress with ReportProgress, which only sends
+notifications when the client passed a progressToken, and stop when their
+context is done:
+
+	for i, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		req.ReportProgress(ctx, float64(i), float64(len(files)), file)
+		index(file)
+	}
+
+The context is cancelled when the client sends notifications/cancelled for
+the request, which is then left unanswered, or when the timeout set with
+WithRequestTimeout expires.
+
+# Logging
+
+NewLogHandler returns a slog.Handler that sends records to clients as
+notifications/message, honouring the level each client sets with
+logging/setLevel and rate-limiting bursts. Records logged with a handler's
+context go to the client of that request; others go to every client, or to
+stderr while none is connected:
+
+	logger := slog.New(server.NewLogHandler(srv, server.WithLoggerName("indexer")))
+	logger.InfoContext(ctx, "indexing", "files", len(files))
+
+# Errors
+
+Tool handlers that return an error produce a tool result with isError set, so
+the model can see what went wrong. Return an *Error to fail the request with a
+JSON-RPC error instead. Panics in handlers are recovered and reported as
+internal errors.
+*/
+package server
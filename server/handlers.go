@@ -0,0 +1,318 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// methodHandler handles one request method
+type methodHandler func(ctx context.Context, req *Request) (interface{}, error)
+
+// methods maps request methods to their handlers
+var methods map[string]methodHandler
+
+func init() {
+	methods = map[string]methodHandler{
+		"initialize":               handleInitialize,
+		"ping":                     handlePing,
+		"tools/list":               handleListTools,
+		"tools/call":               handleCallTool,
+		"resources/list":           handleListResources,
+		"resources/templates/list": handleListResourceTemplates,
+		"resources/read":           handleReadResource,
+		"resources/subscribe":      handleSubscribe,
+		"resources/unsubscribe":    handleUnsubscribe,
+		"prompts/list":             handleListPrompts,
+		"prompts/get":              handleGetPrompt,
+		"completion/complete":      handleComplete,
+		"logging/setLevel":         handleSetLevel,
+	}
+}
+
+// decodeParams decodes request params into v, reporting failures as invalid params
+func decodeParams(req *Request, v interface{}) error {
+	if len(req.Params) == 0 || string(req.Params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(req.Params, v); err != nil {
+		return NewError(CodeInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
+
+func handleInitialize(ctx context.Context, req *Request) (interface{}, error) {
+	var params struct {
+		ProtocolVersion string                   `json:"protocolVersion"`
+		Capabilities    types.ClientCapabilities `json:"capabilities"`
+		ClientInfo      types.Implementation     `json:"clientInfo"`
+	}
+	if err := decodeParams(req, &params); err != nil {
+		return nil, err
+	}
+	if params.ProtocolVersion == "" {
+		return nil, NewError(CodeInvalidParams, "missing protocolVersion")
+	}
+
+	version := params.ProtocolVersion
+	if !supportedVersions[version] {
+		version = types.LatestProtocolVersion
+	}
+
+	sess := req.Session
+	sess.mu.Lock()
+	if sess.initializing {
+		sess.mu.Unlock()
+		return nil, NewError(CodeInvalidRequest, "session is already initialized")
+	}
+	sess.initializing = true
+	sess.protocolVersion = version
+	sess.clientInfo = params.ClientInfo
+	sess.caps = params.Capabilities
+	sess.mu.Unlock()
+
+	srv := sess.server
+	return &types.InitializeResult{
+		ProtocolVersion: version,
+		Capabilities:    srv.capabilities(),
+		ServerInfo:      srv.info,
+		Instructions:    srv.instructions,
+	}, nil
+}
+
+func handlePing(ctx context.Context, req *Request) (interface{}, error) {
+	return struct{}{}, nil
+}
+
+// cursorParams holds the params of list requests
+type cursorParams struct {
+	Cursor string `json:"cursor"`
+}
+
+// paginate returns the page of items starting at cursor and the cursor of the
+// next page. Cursors are opaque to clients; they encode an offset.
+func paginate[T any](items []T, cursor string, pageSize int) ([]T, *types.Cursor, error) {
+	start := 0
+	if cursor != "" {
+		data, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err == nil {
+			start, err = strconv.Atoi(string(data))
+		}
+		if err != nil || start < 0 || start > len(items) {
+			return nil, nil, NewError(CodeInvalidParams, "invalid cursor %q", cursor)
+		}
+	}
+
+	if pageSize <= 0 || start+pageSize >= len(items) {
+		return items[start:], nil, nil
+	}
+	next := types.Cursor(base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(start + pageSize))))
+	return items[start : start+pageSize], &next, nil
+}
+
+func handleListTools(ctx context.Context, req *Request) (interface{}, error) {
+	var params cursorParams
+	if err := decodeParams(req, &params); err != nil {
+		return nil, err
+	}
+	srv := req.Session.server
+	tools, next, err := paginate(srv.listTools(), params.Cursor, srv.pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ListToolsResult{Tools: tools, NextCursor: next}, nil
+}
+
+func handleCallTool(ctx context.Context, req *Request) (interface{}, error) {
+	var params struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := decodeParams(req, &params); err != nil {
+		return nil, err
+	}
+
+	t := req.Session.server.lookupTool(params.Name)
+	if t == nil {
+		return nil, NewError(CodeInvalidParams, "unknown tool: %s", params.Name)
+	}
+
+	toolReq := &ToolRequest{Request: req, Name: params.Name, RawArguments: params.Arguments}
+	if len(params.Arguments) == 0 || string(params.Arguments) == "null" {
+		toolReq.RawArguments = json.RawMessage("{}")
+	} else if err := json.Unmarshal(params.Arguments, &toolReq.Arguments); err != nil {
+		return nil, NewError(CodeInvalidParams, "tool arguments must be an object")
+	}
+	if toolReq.Arguments == nil {
+		toolReq.Arguments = map[string]interface{}{}
+	}
+
+	result, err := t.handler(ctx, toolReq)
+	if err != nil {
+		var rpcErr *Error
+		if errors.As(err, &rpcErr) {
+			return nil, rpcErr
+		}
+		return &types.CallToolResult{
+			Content: []interface{}{types.TextContent{Type: types.ContentTypeText, Text: err.Error()}},
+			IsError: true,
+		}, nil
+	}
+	if result == nil {
+		result = &types.CallToolResult{}
+	}
+	if result.Content == nil {
+		result.Content = []interface{}{}
+	}
+	return result, nil
+}
+
+func handleListResources(ctx context.Context, req *Request) (interface{}, error) {
+	var params cursorParams
+	if err := decodeParams(req, &params); err != nil {
+		return nil, err
+	}
+	srv := req.Session.server
+	resources, next, err := paginate(srv.listResources(), params.Cursor, srv.pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ListResourcesResult{Resources: resources, NextCursor: next}, nil
+}
+
+func handleListResourceTemplates(ctx context.Context, req *Request) (interface{}, error) {
+	var params cursorParams
+	if err := decodeParams(req, &params); err != nil {
+		return nil, err
+	}
+	srv := req.Session.server
+	templates, next, err := paginate(srv.listResourceTemplates(), params.Cursor, srv.pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ListResourceTemplatesResult{ResourceTemplates: templates, NextCursor: next}, nil
+}
+
+func handleReadResource(ctx context.Context, req *Request) (interface{}, error) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := decodeParams(req, &params); err != nil {
+		return nil, err
+	}
+	if params.URI == "" {
+		return nil, NewError(CodeInvalidParams, "missing uri")
+	}
+
+	handler, vars := req.Session.server.lookupResource(params.URI)
+	if handler == nil {
+		return nil, resourceNotFound(params.URI)
+	}
+
+	result, err := handler(ctx, &ResourceRequest{Request: req, URI: params.URI, Vars: vars})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = &types.ReadResourceResult{}
+	}
+	if result.Contents == nil {
+		result.Contents = []types.ResourceContents{}
+	}
+	return result, nil
+}
+
+func handleSubscribe(ctx context.Context, req *Request) (interface{}, error) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := decodeParams(req, &params); err != nil {
+		return nil, err
+	}
+	if params.URI == "" {
+		return nil, NewError(CodeInvalidParams, "missing uri")
+	}
+
+	srv := req.Session.server
+	if handler, _ := srv.lookupResource(params.URI); handler == nil {
+		return nil, resourceNotFound(params.URI)
+	}
+	srv.subscribe(req.Session, params.URI)
+	return struct{}{}, nil
+}
+
+func handleUnsubscribe(ctx context.Context, req *Request) (interface{}, error) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := decodeParams(req, &params); err != nil {
+		return nil, err
+	}
+	if params.URI == "" {
+		return nil, NewError(CodeInvalidParams, "missing uri")
+	}
+
+	req.Session.server.unsubscribe(req.Session, params.URI)
+	return struct{}{}, nil
+}
+
+// resourceNotFound returns the error for reads of unknown resources
+func resourceNotFound(uri string) *Error {
+	return &Error{
+		Code:    CodeResourceNotFound,
+		Message: "resource not found",
+		Data:    map[string]string{"uri": uri},
+	}
+}
+
+func handleListPrompts(ctx context.Context, req *Request) (interface{}, error) {
+	var params cursorParams
+	if err := decodeParams(req, &params); err != nil {
+		return nil, err
+	}
+	srv := req.Session.server
+	prompts, next, err := paginate(srv.listPrompts(), params.Cursor, srv.pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ListPromptsResult{Prompts: prompts, NextCursor: next}, nil
+}
+
+func handleGetPrompt(ctx context.Context, req *Request) (interface{}, error) {
+	var params struct {
+		Name      string            `json:"name"`
+		Arguments map[string]string `json:"arguments"`
+	}
+	if err := decodeParams(req, &params); err != nil {
+		return nil, err
+	}
+
+	p := req.Session.server.lookupPrompt(params.Name)
+	if p == nil {
+		return nil, NewError(CodeInvalidParams, "unknown prompt: %s", params.Name)
+	}
+	if params.Arguments == nil {
+		params.Arguments = map[string]string{}
+	}
+	for _, arg := range p.prompt.Arguments {
+		if _, ok := params.Arguments[arg.Name]; arg.Required && !ok {
+			return nil, NewError(CodeInvalidParams, "missing required argument %q of prompt %s", arg.Name, params.Name)
+		}
+	}
+
+	result, err := p.handler(ctx, &PromptRequest{Request: req, Name: params.Name, Arguments: params.Arguments})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = &types.GetPromptResult{}
+	}
+	if result.Messages == nil {
+		result.Messages = []types.PromptMessage{}
+	}
+	return result, nil
+}
@@ -0,0 +1,605 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Streamable HTTP headers
+const (
+	sessionHeader         = "Mcp-Session-Id"
+	protocolVersionHeader = "Mcp-Protocol-Version"
+	lastEventIDHeader     = "Last-Event-ID"
+)
+
+// standaloneStream is the id of the stream opened with GET, which carries
+// messages not tied to a request
+const standaloneStream = 0
+
+// retainedStreams is how many finished request streams a session keeps for
+// clients that lost the connection before reading the response. A write to a
+// dropped connection can succeed, so delivery does not prove receipt.
+const retainedStreams = 32
+
+// DefaultSessionIdleTimeout is how long a session may go without requests or
+// open streams before the handler terminates it
+const DefaultSessionIdleTimeout = 30 * time.Minute
+
+// HTTPOption configures an HTTPHandler
+type HTTPOption func(*HTTPHandler)
+
+// WithAllowedOrigins allows browser requests from the given origins, such as
+// "https://app.example.com". Requests without an Origin header and requests
+// from localhost are always allowed; any other origin is rejected to protect
+// local servers from DNS rebinding attacks.
+func WithAllowedOrigins(origins ...string) HTTPOption {
+	return func(h *HTTPHandler) {
+		for _, origin := range origins {
+			h.origins[strings.TrimRight(origin, "/")] = true
+		}
+	}
+}
+
+// WithEventHistory sets how many events are kept per stream for clients that
+// reconnect with Last-Event-ID. The default is 100.
+func WithEventHistory(n int) HTTPOption {
+	return func(h *HTTPHandler) {
+		h.history = n
+	}
+}
+
+// WithSessionIdleTimeout sets how long a session may go without requests or
+// open streams before it is terminated; clients must then initialize a new
+// session. The default is DefaultSessionIdleTimeout, and 0 keeps sessions
+// until the client deletes them or the handler is closed.
+func WithSessionIdleTimeout(timeout time.Duration) HTTPOption {
+	return func(h *HTTPHandler) {
+		h.idleTimeout = timeout
+	}
+}
+
+// HTTPHandler serves a Server over the Streamable HTTP transport. Clients
+// POST messages to it, receiving responses as JSON or as an SSE stream that
+// also carries the messages the server sends while handling the request, and
+// open a standalone SSE stream with GET. Every SSE event has an id, so a
+// client that loses a stream can resume it by sending GET with Last-Event-ID.
+type HTTPHandler struct {
+	server      *Server
+	origins     map[string]bool
+	history     int
+	idleTimeout time.Duration
+
+	mu       sync.Mutex
+	sessions map[string]*httpSession
+}
+
+// NewHTTPHandler creates a Streamable HTTP handler for srv
+func NewHTTPHandler(srv *Server, opts ...HTTPOption) *HTTPHandler {
+	h := &HTTPHandler{
+		server:      srv,
+		origins:     make(map[string]bool),
+		history:     100,
+		idleTimeout: DefaultSessionIdleTimeout,
+		sessions:    make(map[string]*httpSession),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// httpSession is a session whose messages are written to SSE streams
+type httpSession struct {
+	*Session
+	ctx     context.Context // Cancelled when the session is terminated
+	cancel  context.CancelFunc
+	history int
+
+	mu         sync.Mutex
+	streams    map[int]*eventStream
+	finished   []int // Finished request streams, oldest first
+	nextStream int
+	nextConn   int
+	active     int         // Requests and connections using the session
+	lastActive time.Time   // When the session was last used
+	idle       *time.Timer // Expires the session, nil without an idle timeout
+}
+
+// eventStream is a sequence of SSE events. Events are kept after they are
+// written so that a client can resume the stream on a new connection.
+type eventStream struct {
+	id        int
+	events    []event
+	nextSeq   int
+	delivered int           // Highest sequence number written to a connection
+	done      bool          // The last event has been added
+	conn      int           // Connection writing the stream, 0 if none
+	wake      chan struct{} // Signalled when events are added or the connection changes
+}
+
+// event is one message on a stream
+type event struct {
+	seq  int
+	data []byte
+}
+
+// streamKey is the context key of the stream answering a request
+type streamKey struct{}
+
+// ServeHTTP implements http.Handler
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.allowedOrigin(r) {
+		http.Error(w, "Forbidden origin", http.StatusForbidden)
+		return
+	}
+	if version := r.Header.Get(protocolVersionHeader); version != "" && !supportedVersions[version] {
+		http.Error(w, "Unsupported protocol version "+version, http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodGet:
+		h.handleGet(w, r)
+	case http.MethodDelete:
+		h.handleDelete(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// Close terminates every session. Open streams end and the contexts of
+// requests being handled are cancelled.
+func (h *HTTPHandler) Close() error {
+	h.mu.Lock()
+	sessions := h.sessions
+	h.sessions = make(map[string]*httpSession)
+	h.mu.Unlock()
+
+	for _, s := range sessions {
+		s.terminate()
+	}
+	return nil
+}
+
+// handlePost handles a message sent by the client
+func (h *HTTPHandler) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, "Failed to read body", http.StatusBadRequest)
+		return
+	}
+
+	msg, rpcErr := decodeMessage(body)
+	if rpcErr != nil {
+		writeJSON(w, http.StatusBadRequest, encodeError(nil, rpcErr))
+		return
+	}
+	isRequest := msg.Method != "" && msg.hasID()
+
+	if isRequest && msg.Method == "initialize" {
+		h.initialize(w, r, body)
+		return
+	}
+	s := h.lookup(w, r)
+	if s == nil {
+		return
+	}
+	defer s.begin()()
+
+	if !isRequest {
+		if response := s.HandleMessage(s.ctx, body); response != nil {
+			writeJSON(w, http.StatusBadRequest, response)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok || !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		// Without a stream the request ends with the client's connection,
+		// and messages sent while handling it go to the standalone stream
+		ctx, cancel := context.WithCancel(r.Context())
+		defer cancel()
+		stop := context.AfterFunc(s.ctx, cancel)
+		defer stop()
+
+		response := s.HandleMessage(ctx, body)
+		if response == nil {
+			// The client cancelled the request
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		writeJSON(w, http.StatusOK, response)
+		return
+	}
+
+	// Answer on an SSE stream that also carries the notifications and
+	// requests sent to the client while the request is handled. The request
+	// outlives the connection so that the client can resume the stream.
+	st := s.openStream()
+	end := s.begin()
+	go func() {
+		defer end()
+		ctx := context.WithValue(s.ctx, streamKey{}, st)
+		response := s.HandleMessage(ctx, body)
+		s.finishStream(st, response)
+	}()
+
+	s.serveStream(w, flusher, r, st, 0)
+}
+
+// handleGet opens the standalone SSE stream, or resumes a stream after the
+// event named by Last-Event-ID
+func (h *HTTPHandler) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		http.Error(w, "Accept must include text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	s := h.lookup(w, r)
+	if s == nil {
+		return
+	}
+	defer s.begin()()
+
+	streamID, after := standaloneStream, -1
+	if lastEventID := r.Header.Get(lastEventIDHeader); lastEventID != "" {
+		var err error
+		if streamID, after, err = parseEventID(lastEventID); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	st := s.streams[streamID]
+	if st != nil && after < 0 {
+		after = st.delivered
+	}
+	s.mu.Unlock()
+	if st == nil {
+		http.Error(w, "Stream not found", http.StatusNotFound)
+		return
+	}
+
+	s.serveStream(w, flusher, r, st, after)
+}
+
+// handleDelete terminates a session
+func (h *HTTPHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	s := h.lookup(w, r)
+	if s == nil {
+		return
+	}
+
+	h.remove(s)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// initialize opens a session with an initialize request. The session is only
+// registered and its id only sent to the client once the handshake succeeds,
+// so a failed initialize leaves nothing behind. The response is always JSON;
+// the server sends nothing else while answering initialize.
+func (h *HTTPHandler) initialize(w http.ResponseWriter, r *http.Request, body []byte) {
+	s := h.newSession()
+	response := s.HandleMessage(r.Context(), body)
+
+	var result message
+	if response == nil || json.Unmarshal(response, &result) != nil || result.Error != nil {
+		s.terminate()
+		if response != nil {
+			writeJSON(w, http.StatusOK, response)
+		}
+		return
+	}
+
+	h.register(s)
+	w.Header().Set(sessionHeader, s.ID())
+	writeJSON(w, http.StatusOK, response)
+}
+
+// newSession creates a session that is not yet registered with the handler
+func (h *HTTPHandler) newSession() *httpSession {
+	s := &httpSession{
+		history: h.history,
+		streams: make(map[int]*eventStream),
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.streams[standaloneStream] = newEventStream(standaloneStream)
+	s.nextStream = standaloneStream + 1
+	s.Session = h.server.NewSession(s.send)
+	return s
+}
+
+// register makes a session available to later requests and starts its idle
+// timer
+func (h *HTTPHandler) register(s *httpSession) {
+	h.mu.Lock()
+	h.sessions[s.ID()] = s
+	h.mu.Unlock()
+
+	if h.idleTimeout > 0 {
+		s.mu.Lock()
+		s.lastActive = time.Now()
+		s.idle = time.AfterFunc(h.idleTimeout, func() { h.expire(s) })
+		s.mu.Unlock()
+	}
+}
+
+// expire terminates a session that has been idle for the idle timeout, and
+// otherwise checks again when the session could next expire
+func (h *HTTPHandler) expire(s *httpSession) {
+	if s.ctx.Err() != nil {
+		return
+	}
+
+	s.mu.Lock()
+	wait := h.idleTimeout
+	if s.active == 0 {
+		wait -= time.Since(s.lastActive)
+	}
+	if wait > 0 {
+		s.idle.Reset(wait)
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+
+	h.remove(s)
+}
+
+// remove unregisters and terminates a session
+func (h *HTTPHandler) remove(s *httpSession) {
+	h.mu.Lock()
+	if h.sessions[s.ID()] == s {
+		delete(h.sessions, s.ID())
+	}
+	h.mu.Unlock()
+
+	s.terminate()
+}
+
+// lookup returns the session named by the request, writing an error response
+// and returning nil if the header is missing or the session is unknown
+func (h *HTTPHandler) lookup(w http.ResponseWriter, r *http.Request) *httpSession {
+	id := r.Header.Get(sessionHeader)
+	if id == "" {
+		http.Error(w, "Missing "+sessionHeader+" header", http.StatusBadRequest)
+		return nil
+	}
+
+	h.mu.Lock()
+	s := h.sessions[id]
+	h.mu.Unlock()
+
+	if s == nil {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return nil
+	}
+	return s
+}
+
+// allowedOrigin reports whether a request may be served
+func (h *HTTPHandler) allowedOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || h.origins[origin] {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	switch u.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
+
+// terminate closes the session and ends its streams
+func (s *httpSession) terminate() {
+	s.mu.Lock()
+	if s.idle != nil {
+		s.idle.Stop()
+	}
+	s.mu.Unlock()
+
+	s.Close()
+	s.cancel()
+}
+
+// begin marks the session as in use until the returned function is called, so
+// that it does not expire while a request is handled or a stream is open
+func (s *httpSession) begin() func() {
+	s.mu.Lock()
+	s.active++
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		s.active--
+		s.lastActive = time.Now()
+		s.mu.Unlock()
+	}
+}
+
+// send adds a message to the stream answering the request that ctx belongs
+// to, or to the standalone stream if that stream has ended or there is none
+func (s *httpSession) send(ctx context.Context, msg []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, _ := ctx.Value(streamKey{}).(*eventStream)
+	if st == nil || st.done {
+		st = s.streams[standaloneStream]
+	}
+	s.addEvent(st, msg)
+	return nil
+}
+
+// openStream creates a stream for the response to a request
+func (s *httpSession) openStream() *eventStream {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := newEventStream(s.nextStream)
+	s.streams[st.id] = st
+	s.nextStream++
+	return st
+}
+
+// finishStream adds the response to a request as the last event of its stream
+func (s *httpSession) finishStream(st *eventStream, response []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if response != nil {
+		s.addEvent(st, response)
+	}
+	st.done = true
+	st.signal()
+
+	s.finished = append(s.finished, st.id)
+	if len(s.finished) > retainedStreams {
+		delete(s.streams, s.finished[0])
+		s.finished = s.finished[1:]
+	}
+}
+
+// addEvent appends a message to a stream, dropping the oldest events beyond
+// the history limit. Callers must hold s.mu.
+func (s *httpSession) addEvent(st *eventStream, data []byte) {
+	st.nextSeq++
+	st.events = append(st.events, event{seq: st.nextSeq, data: data})
+	if s.history > 0 && len(st.events) > s.history {
+		st.events = append(st.events[:0:0], st.events[len(st.events)-s.history:]...)
+	}
+	st.signal()
+}
+
+// serveStream writes the events of st after sequence number after as an SSE
+// response until the stream is done, the client disconnects, another
+// connection takes the stream over or the session is terminated
+func (s *httpSession) serveStream(w http.ResponseWriter, flusher http.Flusher, r *http.Request, st *eventStream, after int) {
+	s.mu.Lock()
+	s.nextConn++
+	conn := s.nextConn
+	st.conn = conn
+	st.signal()
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		if st.conn == conn {
+			st.conn = 0
+		} else {
+			// Pass on a wakeup that may have been meant for the new connection
+			st.signal()
+		}
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		s.mu.Lock()
+		if st.conn != conn {
+			s.mu.Unlock()
+			return
+		}
+		var pending []event
+		for _, ev := range st.events {
+			if ev.seq > after {
+				pending = append(pending, ev)
+			}
+		}
+		done := st.done
+		s.mu.Unlock()
+
+		for _, ev := range pending {
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: message\ndata: %s\n\n", eventID(st.id, ev.seq), ev.data); err != nil {
+				return
+			}
+			after = ev.seq
+		}
+		flusher.Flush()
+
+		s.mu.Lock()
+		if after > st.delivered {
+			st.delivered = after
+		}
+		finished := done && after == st.nextSeq
+		s.mu.Unlock()
+		if finished {
+			return
+		}
+
+		select {
+		case <-st.wake:
+		case <-r.Context().Done():
+			return
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// newEventStream creates an empty stream
+func newEventStream(id int) *eventStream {
+	return &eventStream{id: id, wake: make(chan struct{}, 1)}
+}
+
+// signal wakes the connection writing the stream
+func (st *eventStream) signal() {
+	select {
+	case st.wake <- struct{}{}:
+	default:
+	}
+}
+
+// eventID returns the SSE event id of an event, which names its stream so
+// that Last-Event-ID identifies the stream to resume
+func eventID(stream, seq int) string {
+	return strconv.Itoa(stream) + "-" + strconv.Itoa(seq)
+}
+
+// parseEventID parses an event id created by eventID
+func parseEventID(id string) (stream, seq int, err error) {
+	streamPart, seqPart, ok := strings.Cut(id, "-")
+	if ok {
+		if stream, err = strconv.Atoi(streamPart); err == nil {
+			seq, err = strconv.Atoi(seqPart)
+		}
+	}
+	if !ok || err != nil || stream < 0 || seq < 0 {
+		return 0, 0, fmt.Errorf("invalid %s %q", lastEventIDHeader, id)
+	}
+	return stream, seq, nil
+}
+
+// writeJSON writes an encoded JSON-RPC message as the response body
+func writeJSON(w http.ResponseWriter, status int, data []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(data)
+}
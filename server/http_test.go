@@ -0,0 +1,332 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Convict3d/mcp-go/client"
+	mcphttp "github.com/Convict3d/mcp-go/transport/http"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+const initializeBody = `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{}}}`
+
+// newHTTPTestServer serves srv over Streamable HTTP
+func newHTTPTestServer(t *testing.T, srv *Server, opts ...HTTPOption) *httptest.Server {
+	t.Helper()
+	handler := NewHTTPHandler(srv, opts...)
+	ts := httptest.NewServer(handler)
+	t.Cleanup(func() {
+		handler.Close()
+		ts.Close()
+	})
+	return ts
+}
+
+// post sends a message and returns the response
+func post(t *testing.T, url, sessionID, body, accept string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", accept)
+	if sessionID != "" {
+		req.Header.Set(sessionHeader, sessionID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST failed: %v", err)
+	}
+	return resp
+}
+
+// initializeHTTP opens a session and returns its id
+func initializeHTTP(t *testing.T, url string) string {
+	t.Helper()
+	resp := post(t, url, "", initializeBody, "application/json")
+	resp.Body.Close()
+	id := resp.Header.Get(sessionHeader)
+	if resp.StatusCode != http.StatusOK || id == "" {
+		t.Fatalf("initialize: status %d, session %q", resp.StatusCode, id)
+	}
+	resp = post(t, url, id, `{"jsonrpc":"2.0","method":"notifications/initialized"}`, "application/json")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("notifications/initialized: status %d", resp.StatusCode)
+	}
+	return id
+}
+
+// sseEvent is one event read from an SSE stream
+type sseEvent struct {
+	id   string
+	data map[string]interface{}
+}
+
+// readEvent reads the next event from an SSE stream
+func readEvent(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+	var ev sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Stream ended: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && ev.data != nil:
+			return ev
+		case strings.HasPrefix(line, "id: "):
+			ev.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &ev.data); err != nil {
+				t.Fatalf("Invalid event data %q: %v", line, err)
+			}
+		}
+	}
+}
+
+func TestHTTPHandlerClient(t *testing.T) {
+	srv := newTestServer()
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "log"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		err := req.Session.Notify(ctx, "notifications/message", map[string]interface{}{"level": "info", "data": "working"})
+		if err != nil {
+			return nil, err
+		}
+		return textResult("done"), nil
+	})
+	ts := newHTTPTestServer(t, srv)
+
+	var mu sync.Mutex
+	var logs []string
+	c := client.NewClient(
+		client.WithTransport(mcphttp.NewHTTPTransport(ts.URL)),
+		client.WithLogSink(func(msg types.LoggingMessageNotification) {
+			mu.Lock()
+			defer mu.Unlock()
+			logs = append(logs, msg.Params.Data.(string))
+		}),
+	)
+	defer c.Close()
+
+	if err := c.Initialize(types.LatestProtocolVersion); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	if c.GetSessionID() == "" {
+		t.Error("Client did not receive a session id")
+	}
+
+	tools, err := c.ListTools()
+	if err != nil || len(tools) != 5 {
+		t.Fatalf("ListTools = %d tools, %v", len(tools), err)
+	}
+
+	result, err := c.CallTool("log", nil)
+	if err != nil || result.GetTextStrings()[0] != "done" {
+		t.Fatalf("CallTool = %+v, %v", result, err)
+	}
+	mu.Lock()
+	if len(logs) != 1 || logs[0] != "working" {
+		t.Errorf("Logs = %v, expected the message sent while the tool ran", logs)
+	}
+	mu.Unlock()
+
+	read, err := c.ReadResource("users://7/profile")
+	if err != nil || read.Contents[0].Text != "user 7" {
+		t.Errorf("ReadResource = %+v, %v", read, err)
+	}
+	prompt, err := c.GetPrompt("greet", map[string]string{"name": "Ada"})
+	if err != nil || len(prompt.Messages) != 1 {
+		t.Errorf("GetPrompt = %+v, %v", prompt, err)
+	}
+}
+
+func TestHTTPHandlerSessions(t *testing.T) {
+	ts := newHTTPTestServer(t, newTestServer(), WithAllowedOrigins("https://app.example.com"))
+
+	id := initializeHTTP(t, ts.URL)
+
+	resp := post(t, ts.URL, id, `{"jsonrpc":"2.0","id":2,"method":"ping"}`, "application/json")
+	var msg message
+	json.NewDecoder(resp.Body).Decode(&msg)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "application/json" || msg.Error != nil || string(msg.ID) != "2" {
+		t.Errorf("ping: status %d, content type %q, response %+v", resp.StatusCode, resp.Header.Get("Content-Type"), msg)
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		session string
+		body    string
+		headers map[string]string
+		status  int
+	}{
+		{"missing session", http.MethodPost, "", `{"jsonrpc":"2.0","id":2,"method":"ping"}`, nil, http.StatusBadRequest},
+		{"unknown session", http.MethodPost, "unknown", `{"jsonrpc":"2.0","id":2,"method":"ping"}`, nil, http.StatusNotFound},
+		{"parse error", http.MethodPost, id, `{`, nil, http.StatusBadRequest},
+		{"foreign origin", http.MethodPost, id, `{"jsonrpc":"2.0","id":2,"method":"ping"}`, map[string]string{"Origin": "https://evil.example.com"}, http.StatusForbidden},
+		{"allowed origin", http.MethodPost, id, `{"jsonrpc":"2.0","id":2,"method":"ping"}`, map[string]string{"Origin": "https://app.example.com"}, http.StatusOK},
+		{"localhost origin", http.MethodPost, id, `{"jsonrpc":"2.0","id":2,"method":"ping"}`, map[string]string{"Origin": "http://localhost:3000"}, http.StatusOK},
+		{"unsupported version", http.MethodPost, id, `{"jsonrpc":"2.0","id":2,"method":"ping"}`, map[string]string{protocolVersionHeader: "1999-01-01"}, http.StatusBadRequest},
+		{"supported version", http.MethodPost, id, `{"jsonrpc":"2.0","id":2,"method":"ping"}`, map[string]string{protocolVersionHeader: "2025-06-18"}, http.StatusOK},
+		{"response", http.MethodPost, id, `{"jsonrpc":"2.0","id":"s-1","result":{}}`, nil, http.StatusAccepted},
+		{"GET without SSE", http.MethodGet, id, "", map[string]string{"Accept": "application/json"}, http.StatusNotAcceptable},
+		{"bad event id", http.MethodGet, id, "", map[string]string{"Last-Event-ID": "x"}, http.StatusBadRequest},
+		{"unknown stream", http.MethodGet, id, "", map[string]string{"Last-Event-ID": "99-1"}, http.StatusNotFound},
+		{"PUT", http.MethodPut, id, "", nil, http.StatusMethodNotAllowed},
+		{"DELETE", http.MethodDelete, id, "", nil, http.StatusNoContent},
+		{"after DELETE", http.MethodPost, id, `{"jsonrpc":"2.0","id":2,"method":"ping"}`, nil, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, ts.URL, strings.NewReader(tt.body))
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if tt.session != "" {
+			req.Header.Set(sessionHeader, tt.session)
+		}
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tt.status {
+			t.Errorf("%s: status %d, expected %d", tt.name, resp.StatusCode, tt.status)
+		}
+	}
+}
+
+func TestHTTPHandlerFailedInitialize(t *testing.T) {
+	srv := newTestServer()
+	ts := newHTTPTestServer(t, srv)
+
+	resp := post(t, ts.URL, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"capabilities":{}}}`, "application/json, text/event-stream")
+	var msg message
+	json.NewDecoder(resp.Body).Decode(&msg)
+	resp.Body.Close()
+	if msg.Error == nil || msg.Error.Code != CodeInvalidParams {
+		t.Errorf("Response = %+v, expected invalid params", msg)
+	}
+	if id := resp.Header.Get(sessionHeader); id != "" {
+		t.Errorf("Failed initialize returned session %q", id)
+	}
+	if sessions := srv.Sessions(); len(sessions) != 0 {
+		t.Errorf("Failed initialize left %d sessions", len(sessions))
+	}
+}
+
+func TestHTTPHandlerIdleTimeout(t *testing.T) {
+	srv := newTestServer()
+	ts := newHTTPTestServer(t, srv, WithSessionIdleTimeout(50*time.Millisecond))
+
+	id := initializeHTTP(t, ts.URL)
+	deadline := time.Now().Add(2 * time.Second)
+	for len(srv.Sessions()) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Idle session was not terminated")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	resp := post(t, ts.URL, id, `{"jsonrpc":"2.0","id":2,"method":"ping"}`, "application/json")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("ping on an expired session: status %d", resp.StatusCode)
+	}
+}
+
+func TestHTTPHandlerResume(t *testing.T) {
+	srv := newTestServer()
+	release := make(chan struct{})
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "slow"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		req.Session.Notify(ctx, "notifications/message", map[string]interface{}{"level": "info", "data": "started"})
+		<-release
+		return textResult("finished"), nil
+	})
+	ts := newHTTPTestServer(t, srv)
+	id := initializeHTTP(t, ts.URL)
+
+	// The first event arrives, then the connection drops
+	resp := post(t, ts.URL, id, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"slow"}}`, "application/json, text/event-stream")
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Content type = %q", resp.Header.Get("Content-Type"))
+	}
+	first := readEvent(t, bufio.NewReader(resp.Body))
+	resp.Body.Close()
+	if first.data["method"] != "notifications/message" || first.id == "" {
+		t.Fatalf("First event = %+v", first)
+	}
+
+	close(release)
+
+	// Resuming after the first event delivers the response
+	req, _ := http.NewRequest(http.MethodGet, ts.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(sessionHeader, id)
+	req.Header.Set(lastEventIDHeader, first.id)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	ev := readEvent(t, bufio.NewReader(resp.Body))
+	result, _ := ev.data["result"].(map[string]interface{})
+	if ev.data["id"] != float64(2) || result == nil || ev.id == first.id {
+		t.Errorf("Resumed event = %+v", ev)
+	}
+}
+
+func TestHTTPHandlerStandaloneStream(t *testing.T) {
+	srv := newTestServer()
+	ts := newHTTPTestServer(t, srv)
+	id := initializeHTTP(t, ts.URL)
+
+	// Messages sent before the stream is opened are kept for it
+	srv.RemoveTools("fail")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, ts.URL, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set(sessionHeader, id)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	stream := bufio.NewReader(resp.Body)
+
+	if ev := readEvent(t, stream); ev.data["method"] != "notifications/tools/list_changed" || !strings.HasPrefix(ev.id, "0-") {
+		t.Errorf("First event = %+v", ev)
+	}
+
+	srv.RemovePrompts("greet")
+	if ev := readEvent(t, stream); ev.data["method"] != "notifications/prompts/list_changed" {
+		t.Errorf("Second event = %+v", ev)
+	}
+
+	// Terminating the session ends the stream
+	done := make(chan struct{})
+	go func() {
+		stream.ReadString(0)
+		close(done)
+	}()
+	req, _ = http.NewRequest(http.MethodDelete, ts.URL, nil)
+	req.Header.Set(sessionHeader, id)
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("DELETE: %v", err)
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Error("Stream did not end when the session was terminated")
+	}
+}
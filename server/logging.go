@@ -0,0 +1,386 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// loggingLevels orders the MCP log levels by severity
+var loggingLevels = map[types.LoggingLevel]int{
+	types.LoggingLevelDebug:     0,
+	types.LoggingLevelInfo:      1,
+	types.LoggingLevelNotice:    2,
+	types.LoggingLevelWarning:   3,
+	types.LoggingLevelError:     4,
+	types.LoggingLevelCritical:  5,
+	types.LoggingLevelAlert:     6,
+	types.LoggingLevelEmergency: 7,
+}
+
+// LoggingLevel returns the MCP log level of a slog level, the inverse of
+// client.SlogLevel
+func LoggingLevel(level slog.Level) types.LoggingLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return types.LoggingLevelDebug
+	case level < slog.LevelInfo+2:
+		return types.LoggingLevelInfo
+	case level < slog.LevelWarn:
+		return types.LoggingLevelNotice
+	case level < slog.LevelError:
+		return types.LoggingLevelWarning
+	case level < slog.LevelError+4:
+		return types.LoggingLevelError
+	case level < slog.LevelError+8:
+		return types.LoggingLevelCritical
+	case level < slog.LevelError+12:
+		return types.LoggingLevelAlert
+	default:
+		return types.LoggingLevelEmergency
+	}
+}
+
+// LogOption configures a LogHandler
+type LogOption func(*logState)
+
+// WithLogLevel sets the minimum level sent to sessions that have not chosen
+// one with logging/setLevel. The default is slog.LevelInfo.
+func WithLogLevel(level slog.Leveler) LogOption {
+	return func(s *logState) {
+		s.level = level
+	}
+}
+
+// WithLoggerName sets the logger name sent with every message
+func WithLoggerName(name string) LogOption {
+	return func(s *logState) {
+		s.name = name
+	}
+}
+
+// WithLogRateLimit limits the messages sent to each session to perSecond on
+// average, with bursts of up to burst messages. Messages over the limit are
+// dropped and the client is told how many were dropped with the next message.
+// The default is 20 messages per second with bursts of 100.
+func WithLogRateLimit(perSecond float64, burst int) LogOption {
+	return func(s *logState) {
+		s.rate = perSecond
+		s.burst = burst
+	}
+}
+
+// WithLogFallback sets the handler for records logged while no session is
+// connected. The default writes text to stderr, never to stdout.
+func WithLogFallback(handler slog.Handler) LogOption {
+	return func(s *logState) {
+		s.fallback = handler
+	}
+}
+
+// logState is shared by a LogHandler and the handlers derived from it
+type logState struct {
+	server   *Server
+	level    slog.Leveler
+	name     string
+	rate     float64
+	burst    int
+	fallback slog.Handler
+
+	mu      sync.Mutex
+	buckets map[*Session]*logBucket
+}
+
+// logBucket is the token bucket limiting the messages sent to one session
+type logBucket struct {
+	tokens  float64
+	last    time.Time
+	dropped int
+}
+
+// logAttrs are attributes added with WithAttrs under the groups open at the time
+type logAttrs struct {
+	groups []string
+	attrs  []slog.Attr
+}
+
+// LogHandler is a slog.Handler that sends log records to clients as
+// notifications/message. Records logged with the context of a request go to
+// the session of the request; other records go to every initialized session.
+// Each session receives the records at or above the level it set with
+// logging/setLevel. While no session is connected, records go to the
+// fallback handler.
+type LogHandler struct {
+	state    *logState
+	attrs    []logAttrs
+	groups   []string
+	fallback slog.Handler
+}
+
+// NewLogHandler returns a handler that logs to the clients of srv. The server
+// advertises the logging capability from then on.
+func NewLogHandler(srv *Server, opts ...LogOption) *LogHandler {
+	state := &logState{
+		server:   srv,
+		level:    slog.LevelInfo,
+		rate:     20,
+		burst:    100,
+		fallback: slog.NewTextHandler(os.Stderr, nil),
+		buckets:  make(map[*Session]*logBucket),
+	}
+	for _, opt := range opts {
+		opt(state)
+	}
+
+	srv.mu.Lock()
+	srv.logging = true
+	srv.mu.Unlock()
+
+	return &LogHandler{state: state, fallback: state.fallback}
+}
+
+// Enabled reports whether a record at level would be sent to any session or,
+// without sessions, to the fallback handler
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	sessions := h.sessions(ctx)
+	if len(sessions) == 0 {
+		return h.fallback.Enabled(ctx, level)
+	}
+	for _, sess := range sessions {
+		if h.sessionEnabled(sess, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle sends the record to the sessions that enabled its level
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	sessions := h.sessions(ctx)
+	if len(sessions) == 0 {
+		if !h.fallback.Enabled(ctx, record.Level) {
+			return nil
+		}
+		return h.fallback.Handle(ctx, record)
+	}
+
+	var params struct {
+		Level  types.LoggingLevel `json:"level"`
+		Logger string             `json:"logger,omitempty"`
+		Data   interface{}        `json:"data"`
+	}
+	params.Level = LoggingLevel(record.Level)
+	params.Logger = h.state.name
+	params.Data = h.data(record)
+
+	for _, sess := range sessions {
+		if !h.sessionEnabled(sess, record.Level) {
+			continue
+		}
+		allowed, dropped := h.state.allow(sess, record.Time)
+		if !allowed {
+			continue
+		}
+		if dropped > 0 {
+			h.notify(ctx, sess, map[string]interface{}{
+				"level":  types.LoggingLevelWarning,
+				"logger": h.state.name,
+				"data":   map[string]interface{}{"message": "log messages dropped by the rate limit", "dropped": dropped},
+			})
+		}
+		h.notify(ctx, sess, params)
+	}
+	return nil
+}
+
+// WithAttrs returns a handler that adds attrs to every record
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	derived := *h
+	derived.attrs = append(append([]logAttrs(nil), h.attrs...), logAttrs{groups: h.groups, attrs: attrs})
+	derived.fallback = h.fallback.WithAttrs(attrs)
+	return &derived
+}
+
+// WithGroup returns a handler that nests the attributes added later under name
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	derived := *h
+	derived.groups = append(append([]string(nil), h.groups...), name)
+	derived.fallback = h.fallback.WithGroup(name)
+	return &derived
+}
+
+// sessions returns the open session of the request ctx belongs to, or every
+// initialized session of the server
+func (h *LogHandler) sessions(ctx context.Context) []*Session {
+	if sess := SessionFromContext(ctx); sess != nil {
+		if sess.isClosed() {
+			return nil
+		}
+		return []*Session{sess}
+	}
+
+	var sessions []*Session
+	for _, sess := range h.state.server.Sessions() {
+		if sess.Initialized() {
+			sessions = append(sessions, sess)
+		}
+	}
+	return sessions
+}
+
+// sessionEnabled reports whether sess receives records at level
+func (h *LogHandler) sessionEnabled(sess *Session, level slog.Level) bool {
+	if sessLevel := sess.LogLevel(); sessLevel != "" {
+		return loggingLevels[LoggingLevel(level)] >= loggingLevels[sessLevel]
+	}
+	return level >= h.state.level.Level()
+}
+
+// notify sends a log message to sess. Failures go to the fallback handler
+// rather than the server logger, which may be this handler.
+func (h *LogHandler) notify(ctx context.Context, sess *Session, params interface{}) {
+	if err := sess.Notify(ctx, "notifications/message", params); err != nil {
+		record := slog.NewRecord(time.Now(), slog.LevelDebug, "failed to send log message", 0)
+		record.AddAttrs(slog.String("session", sess.ID()), slog.Any("error", err))
+		if h.state.fallback.Enabled(ctx, slog.LevelDebug) {
+			_ = h.state.fallback.Handle(ctx, record)
+		}
+	}
+}
+
+// data returns the data of the notification for record: the message alone,
+// or an object holding the message and the attributes
+func (h *LogHandler) data(record slog.Record) interface{} {
+	if len(h.attrs) == 0 && record.NumAttrs() == 0 {
+		return record.Message
+	}
+
+	data := map[string]interface{}{"message": record.Message}
+	for _, a := range h.attrs {
+		addAttrs(data, a.groups, a.attrs)
+	}
+	var attrs []slog.Attr
+	record.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	addAttrs(data, h.groups, attrs)
+	return data
+}
+
+// addAttrs adds attrs to data, nested under groups
+func addAttrs(data map[string]interface{}, groups []string, attrs []slog.Attr) {
+	if len(attrs) == 0 {
+		return
+	}
+	for _, group := range groups {
+		nested, ok := data[group].(map[string]interface{})
+		if !ok {
+			nested = make(map[string]interface{})
+			data[group] = nested
+		}
+		data = nested
+	}
+
+	for _, a := range attrs {
+		value := a.Value.Resolve()
+		switch {
+		case a.Equal(slog.Attr{}):
+		case value.Kind() == slog.KindGroup:
+			if a.Key == "" {
+				addAttrs(data, nil, value.Group())
+			} else {
+				addAttrs(data, []string{a.Key}, value.Group())
+			}
+		case value.Kind() == slog.KindTime:
+			data[a.Key] = value.Time().Format(time.RFC3339Nano)
+		case value.Kind() == slog.KindDuration:
+			data[a.Key] = value.Duration().String()
+		default:
+			v := value.Any()
+			if err, ok := v.(error); ok {
+				v = err.Error()
+			}
+			data[a.Key] = v
+		}
+	}
+}
+
+// allow takes a token from the bucket of sess. It reports whether the message
+// may be sent and, when it may, how many messages were dropped before it.
+func (s *logState) allow(sess *Session, now time.Time) (bool, int) {
+	if s.rate <= 0 {
+		return true, 0
+	}
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buckets[sess]
+	if !ok {
+		b = &logBucket{tokens: float64(s.burst), last: now}
+		s.buckets[sess] = b
+		s.prune()
+	}
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * s.rate
+		if b.tokens > float64(s.burst) {
+			b.tokens = float64(s.burst)
+		}
+		b.last = now
+	}
+
+	if b.tokens < 1 {
+		b.dropped++
+		return false, 0
+	}
+	b.tokens--
+	dropped := b.dropped
+	b.dropped = 0
+	return true, dropped
+}
+
+// prune drops the buckets of closed sessions. Callers must hold s.mu.
+func (s *logState) prune() {
+	for sess := range s.buckets {
+		if sess.isClosed() {
+			delete(s.buckets, sess)
+		}
+	}
+}
+
+func handleSetLevel(ctx context.Context, req *Request) (interface{}, error) {
+	// The method only exists when the logging capability is advertised
+	if req.Session.server.capabilities().Logging == nil {
+		return nil, NewError(CodeMethodNotFound, "method not found: %s", req.Method)
+	}
+
+	var params struct {
+		Level types.LoggingLevel `json:"level"`
+	}
+	if err := decodeParams(req, &params); err != nil {
+		return nil, err
+	}
+	if _, ok := loggingLevels[params.Level]; !ok {
+		return nil, NewError(CodeInvalidParams, "invalid log level %q", params.Level)
+	}
+
+	sess := req.Session
+	sess.mu.Lock()
+	sess.logLevel = params.Level
+	sess.mu.Unlock()
+	return struct{}{}, nil
+}
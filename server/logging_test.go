@@ -0,0 +1,150 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Convict3d/mcp-go/client"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// logMessages returns the params of the notifications/message sent to ts
+func logMessages(t *testing.T, ts *testSession) []map[string]interface{} {
+	t.Helper()
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	var messages []map[string]interface{}
+	for _, msg := range ts.sent {
+		if msg.Method != "notifications/message" {
+			continue
+		}
+		var params map[string]interface{}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			t.Fatalf("Invalid params %s: %v", msg.Params, err)
+		}
+		messages = append(messages, params)
+	}
+	return messages
+}
+
+func TestLogHandler(t *testing.T) {
+	srv := New("test", "1.0.0")
+	var fallback bytes.Buffer
+	logger := slog.New(NewLogHandler(srv, WithLoggerName("app"), WithLogFallback(slog.NewTextHandler(&fallback, nil))))
+
+	logger.Info("starting")
+	if !strings.Contains(fallback.String(), "msg=starting") {
+		t.Errorf("Fallback output = %q", fallback.String())
+	}
+
+	verbose := newTestSession(t, srv)
+	quiet := newTestSession(t, srv)
+	if caps := verbose.initialize(t).Capabilities; caps.Logging == nil {
+		t.Errorf("Logging capability is not advertised: %+v", caps)
+	}
+	quiet.initialize(t)
+	verbose.call(t, "logging/setLevel", map[string]interface{}{"level": "debug"}, &struct{}{})
+	if response := quiet.request(t, "logging/setLevel", map[string]interface{}{"level": "loud"}); response.Error == nil || response.Error.Code != CodeInvalidParams {
+		t.Errorf("Invalid level: %+v", response.Error)
+	}
+
+	logger.Debug("details")
+	logger.With("user", "ada").WithGroup("req").Warn("slow", "ms", 1200, slog.Group("db", "table", "users"))
+
+	got := logMessages(t, verbose)
+	if len(got) != 2 {
+		t.Fatalf("Verbose session got %v", got)
+	}
+	if got[0]["level"] != "debug" || got[0]["data"] != "details" || got[0]["logger"] != "app" {
+		t.Errorf("Debug message = %v", got[0])
+	}
+	data, _ := json.Marshal(got[1]["data"])
+	if got[1]["level"] != "warning" || string(data) != `{"message":"slow","req":{"db":{"table":"users"},"ms":1200},"user":"ada"}` {
+		t.Errorf("Warning message = %v, data %s", got[1], data)
+	}
+	if got := logMessages(t, quiet); len(got) != 1 || got[0]["data"].(map[string]interface{})["message"] != "slow" {
+		t.Errorf("Quiet session got %v", got)
+	}
+}
+
+func TestLogHandlerRequestContext(t *testing.T) {
+	srv := New("test", "1.0.0")
+	logger := slog.New(NewLogHandler(srv))
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "work"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		logger.InfoContext(ctx, "working")
+		return textResult("done"), nil
+	})
+	caller := newTestSession(t, srv)
+	other := newTestSession(t, srv)
+	caller.initialize(t)
+	other.initialize(t)
+
+	caller.call(t, "tools/call", map[string]interface{}{"name": "work"}, &types.CallToolResult{})
+	if got := logMessages(t, caller); len(got) != 1 || got[0]["data"] != "working" {
+		t.Errorf("Caller got %v", got)
+	}
+	if got := logMessages(t, other); len(got) != 0 {
+		t.Errorf("Other session got %v", got)
+	}
+}
+
+func TestLogHandlerRateLimit(t *testing.T) {
+	srv := New("test", "1.0.0")
+	handler := NewLogHandler(srv, WithLogRateLimit(1, 2))
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	start := time.Now()
+	for i, at := range []time.Duration{0, 0, 0, 0, time.Second} {
+		record := slog.NewRecord(start.Add(at), slog.LevelInfo, "tick", 0)
+		record.AddAttrs(slog.Int("i", i))
+		if err := handler.Handle(context.Background(), record); err != nil {
+			t.Fatalf("Handle failed: %v", err)
+		}
+	}
+
+	var sent []string
+	for _, msg := range logMessages(t, sess) {
+		data := msg["data"].(map[string]interface{})
+		if data["dropped"] != nil {
+			sent = append(sent, "dropped "+string(mustJSON(t, data["dropped"])))
+		} else {
+			sent = append(sent, "tick "+string(mustJSON(t, data["i"])))
+		}
+	}
+	if strings.Join(sent, ", ") != "tick 0, tick 1, dropped 2, tick 4" {
+		t.Errorf("Sent %v", sent)
+	}
+}
+
+func TestSetLevelWithoutLogging(t *testing.T) {
+	sess := newTestSession(t, New("test", "1.0.0"))
+	if caps := sess.initialize(t).Capabilities; caps.Logging != nil {
+		t.Errorf("Logging capability is advertised without a LogHandler: %+v", caps)
+	}
+	if response := sess.request(t, "logging/setLevel", map[string]interface{}{"level": "debug"}); response.Error == nil || response.Error.Code != CodeMethodNotFound {
+		t.Errorf("Error = %+v, expected method not found", response.Error)
+	}
+}
+
+func TestLoggingLevel(t *testing.T) {
+	for level := range loggingLevels {
+		if got := LoggingLevel(client.SlogLevel(level)); got != level {
+			t.Errorf("LoggingLevel(SlogLevel(%s)) = %s", level, got)
+		}
+	}
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Failed to marshal %v: %v", v, err)
+	}
+	return data
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// errRequestCancelled is the cause of the context of a request the client cancelled
+var errRequestCancelled = errors.New("request cancelled by the client")
+
+// errRequestTimeout is the cause of the context of a request that ran past the
+// server's request timeout, as opposed to a deadline set by the caller
+var errRequestTimeout = errors.New("request timed out")
+
+// ReportProgress sends a progress notification for the request when the
+// client asked for progress with a progressToken, and does nothing otherwise.
+// Progress must increase with every call; total is omitted when zero and
+// message when empty.
+func (r *Request) ReportProgress(ctx context.Context, progress, total float64, message string) error {
+	token, ok := r.Meta["progressToken"]
+	if !ok || token == nil {
+		return nil
+	}
+
+	params := struct {
+		ProgressToken interface{} `json:"progressToken"`
+		Progress      float64     `json:"progress"`
+		Total         float64     `json:"total,omitempty"`
+		Message       string      `json:"message,omitempty"`
+	}{token, progress, total, message}
+	return r.Session.Notify(ctx, "notifications/progress", params)
+}
+
+// startRequest registers the cancel function of a request in flight so that
+// notifications/cancelled can stop it, and returns the function that
+// unregisters it
+func (sess *Session) startRequest(id json.RawMessage, cancel context.CancelCauseFunc) func() {
+	key := string(id)
+	sess.mu.Lock()
+	sess.inflight[key] = cancel
+	sess.mu.Unlock()
+
+	return func() {
+		sess.mu.Lock()
+		delete(sess.inflight, key)
+		sess.mu.Unlock()
+	}
+}
+
+// handleCancelled cancels the context of the request named by a
+// notifications/cancelled notification
+func (sess *Session) handleCancelled(params json.RawMessage) {
+	var cancelled struct {
+		RequestID json.RawMessage `json:"requestId"`
+		Reason    string          `json:"reason"`
+	}
+	if err := json.Unmarshal(params, &cancelled); err != nil {
+		sess.server.logger.Debug("invalid cancellation", "session", sess.id, "error", err)
+		return
+	}
+
+	sess.mu.Lock()
+	cancel, ok := sess.inflight[string(cancelled.RequestID)]
+	sess.mu.Unlock()
+	if ok {
+		sess.server.logger.Debug("request cancelled", "session", sess.id, "id", string(cancelled.RequestID), "reason", cancelled.Reason)
+		cancel(errRequestCancelled)
+	}
+}
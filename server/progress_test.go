@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+func TestReportProgress(t *testing.T) {
+	srv := New("test", "1.0.0")
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "index"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		for i := 1; i <= 2; i++ {
+			if err := req.ReportProgress(ctx, float64(i), 2, "indexing"); err != nil {
+				return nil, err
+			}
+		}
+		return textResult("indexed"), nil
+	})
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	var result types.CallToolResult
+	sess.call(t, "tools/call", map[string]interface{}{"name": "index"}, &result)
+	if got := sess.notifications(); len(got) != 0 {
+		t.Errorf("Progress sent without a token: %v", got)
+	}
+
+	sess.call(t, "tools/call", map[string]interface{}{"name": "index", "_meta": map[string]interface{}{"progressToken": "job-1"}}, &result)
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if len(sess.sent) != 2 {
+		t.Fatalf("Sent = %+v", sess.sent)
+	}
+	for i, msg := range sess.sent {
+		if msg.Method != "notifications/progress" {
+			t.Errorf("Method = %s", msg.Method)
+		}
+		var params map[string]interface{}
+		json.Unmarshal(msg.Params, &params)
+		if params["progressToken"] != "job-1" || params["progress"] != float64(i+1) || params["total"] != float64(2) || params["message"] != "indexing" {
+			t.Errorf("Progress params = %v", params)
+		}
+	}
+}
+
+func TestCancelRequest(t *testing.T) {
+	srv := New("test", "1.0.0")
+	cause := make(chan error, 1)
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "wait"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		<-ctx.Done()
+		cause <- context.Cause(ctx)
+		return nil, ctx.Err()
+	})
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	responses := make(chan []byte, 1)
+	go func() {
+		responses <- sess.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":"call-1","method":"tools/call","params":{"name":"wait"}}`))
+	}()
+
+	// Cancellations of other requests are ignored
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		sess.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-2"}}`))
+		sess.Session.mu.Lock()
+		started := len(sess.inflight) == 1
+		sess.Session.mu.Unlock()
+		if started {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Request did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-responses:
+		t.Fatal("Request finished before it was cancelled")
+	default:
+	}
+
+	sess.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":"call-1","reason":"user abort"}}`))
+	if response := <-responses; response != nil {
+		t.Errorf("Cancelled request was answered with %s", response)
+	}
+	if err := <-cause; err != errRequestCancelled {
+		t.Errorf("Cause = %v", err)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	srv := New("test", "1.0.0", WithRequestTimeout(10*time.Millisecond))
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "wait"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "quick"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		return textResult("done"), nil
+	})
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	response := sess.request(t, "tools/call", map[string]interface{}{"name": "wait"})
+	if response.Error == nil || response.Error.Code != CodeInternalError || !strings.Contains(response.Error.Message, "timed out") {
+		t.Errorf("Error = %+v", response.Error)
+	}
+	var result types.CallToolResult
+	sess.call(t, "tools/call", map[string]interface{}{"name": "quick"}, &result)
+	if result.IsError {
+		t.Errorf("Result = %+v", result)
+	}
+}
+
+func TestRequestCallerDeadline(t *testing.T) {
+	srv := New("test", "1.0.0", WithRequestTimeout(time.Hour))
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "wait"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	// A deadline set by the caller is not the server's request timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	var response message
+	data := sess.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"wait"}}`))
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("Failed to decode response %s: %v", data, err)
+	}
+	if response.Error != nil {
+		t.Fatalf("Error = %+v", response.Error)
+	}
+	var result types.CallToolResult
+	if err := json.Unmarshal(response.Result, &result); err != nil {
+		t.Fatalf("Failed to decode result %s: %v", response.Result, err)
+	}
+	if !result.IsError || !strings.Contains(string(response.Result), "deadline exceeded") {
+		t.Errorf("Result = %s", response.Result)
+	}
+}
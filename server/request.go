@@ -0,0 +1,72 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// Request describes the request a handler is serving
+type Request struct {
+	Session *Session
+	Method  string
+	ID      json.RawMessage // JSON-RPC id of the request
+	Params  json.RawMessage // Raw request params
+	Meta    types.Meta      // The _meta field of the params
+}
+
+// ToolRequest is passed to tool handlers
+type ToolRequest struct {
+	*Request
+	Name         string
+	Arguments    map[string]interface{}
+	RawArguments json.RawMessage // Arguments as sent by the client, for decoding into a struct
+}
+
+// ResourceRequest is passed to resource handlers
+type ResourceRequest struct {
+	*Request
+	URI  string
+	Vars map[string]string // Variables matched from the URI template, nil for static resources
+}
+
+// PromptRequest is passed to prompt handlers
+type PromptRequest struct {
+	*Request
+	Name      string
+	Arguments map[string]string
+}
+
+// requestKey is the context key of the request being served
+type requestKey struct{}
+
+// withRequest returns a context carrying req
+func withRequest(ctx context.Context, req *Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, req)
+}
+
+// RequestFromContext returns the request served by a handler, or nil when ctx
+// does not belong to a request
+func RequestFromContext(ctx context.Context) *Request {
+	req, _ := ctx.Value(requestKey{}).(*Request)
+	return req
+}
+
+// SessionFromContext returns the session of the request served by a handler,
+// or nil when ctx does not belong to a request
+func SessionFromContext(ctx context.Context) *Session {
+	if req := RequestFromContext(ctx); req != nil {
+		return req.Session
+	}
+	return nil
+}
+
+// CompletionRequest is passed to completion providers
+type CompletionRequest struct {
+	*Request
+	Ref       types.CompletionReference // A types.PromptReference or types.ResourceTemplateReference
+	Argument  string                    // Name of the argument or template variable being completed
+	Value     string                    // Value typed so far
+	Arguments map[string]string         // Values of the arguments already filled in
+}
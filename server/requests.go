@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// ErrNotSupported is returned when the server sends a request the client did
+// not declare a capability for in initialize
+var ErrNotSupported = errors.New("not supported by the client")
+
+// CreateMessage asks the client to sample its LLM. It fails with
+// ErrNotSupported unless the client declared the sampling capability.
+func (sess *Session) CreateMessage(ctx context.Context, req *types.CreateMessageRequest) (*types.CreateMessageResult, error) {
+	if sess.ClientCapabilities().Sampling == nil {
+		return nil, fmt.Errorf("sampling: %w", ErrNotSupported)
+	}
+	var result types.CreateMessageResult
+	if err := sess.request(ctx, "sampling/createMessage", req.Params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Elicit asks the client to request information from the user. It fails with
+// ErrNotSupported unless the client declared the elicitation capability.
+func (sess *Session) Elicit(ctx context.Context, req *types.ElicitRequest) (*types.ElicitResult, error) {
+	if sess.ClientCapabilities().Elicitation == nil {
+		return nil, fmt.Errorf("elicitation: %w", ErrNotSupported)
+	}
+	var result types.ElicitResult
+	if err := sess.request(ctx, "elicitation/create", req.Params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListRoots asks the client for its roots. It fails with ErrNotSupported
+// unless the client declared the roots capability.
+func (sess *Session) ListRoots(ctx context.Context) (*types.ListRootsResult, error) {
+	if sess.ClientCapabilities().Roots == nil {
+		return nil, fmt.Errorf("roots: %w", ErrNotSupported)
+	}
+	var result types.ListRootsResult
+	if err := sess.request(ctx, "roots/list", nil, &result); err != nil {
+		return nil, err
+	}
+	if result.Roots == nil {
+		result.Roots = []types.Root{}
+	}
+	return &result, nil
+}
+
+// CreateMessage asks the client of the request's session to sample its LLM,
+// as Session.CreateMessage does
+func (r *Request) CreateMessage(ctx context.Context, req *types.CreateMessageRequest) (*types.CreateMessageResult, error) {
+	return r.Session.CreateMessage(ctx, req)
+}
+
+// Elicit asks the client of the request's session to request information
+// from the user, as Session.Elicit does
+func (r *Request) Elicit(ctx context.Context, req *types.ElicitRequest) (*types.ElicitResult, error) {
+	return r.Session.Elicit(ctx, req)
+}
+
+// ListRoots asks the client of the request's session for its roots, as
+// Session.ListRoots does
+func (r *Request) ListRoots(ctx context.Context) (*types.ListRootsResult, error) {
+	return r.Session.ListRoots(ctx)
+}
+
+// request sends a request to the client and decodes the result of its
+// response into result. Within a handler, pass the handler's context so that
+// transports deliver the request alongside the response being prepared. When
+// ctx is done first, the client is told with notifications/cancelled.
+func (sess *Session) request(ctx context.Context, method string, params interface{}, result interface{}) error {
+	msg := message{JSONRPC: types.JSONRPCVersion, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s params: %w", method, err)
+		}
+		msg.Params = data
+	}
+
+	responses := make(chan message, 1)
+	sess.mu.Lock()
+	if sess.closed || sess.inputEnded {
+		sess.mu.Unlock()
+		return fmt.Errorf("%s: %w", method, ErrSessionClosed)
+	}
+	sess.nextID++
+	msg.ID = json.RawMessage(strconv.FormatInt(sess.nextID, 10))
+	id := string(msg.ID)
+	sess.pending[id] = responses
+	sess.mu.Unlock()
+
+	defer func() {
+		sess.mu.Lock()
+		delete(sess.pending, id)
+		sess.mu.Unlock()
+	}()
+
+	if err := sess.write(ctx, msg); err != nil {
+		return fmt.Errorf("failed to send %s: %w", method, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		sess.cancelRequest(ctx, msg.ID)
+		return ctx.Err()
+	case response, ok := <-responses:
+		if !ok {
+			return fmt.Errorf("%s: %w", method, ErrSessionClosed)
+		}
+		if response.Error != nil {
+			return fmt.Errorf("%s failed: %w", method, response.Error)
+		}
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("failed to decode %s result: %w", method, err)
+		}
+		return nil
+	}
+}
+
+// cancelRequest tells the client that the server no longer waits for the
+// response to the request with id, whose context ended
+func (sess *Session) cancelRequest(ctx context.Context, id json.RawMessage) {
+	params := struct {
+		RequestID json.RawMessage `json:"requestId"`
+		Reason    string          `json:"reason,omitempty"`
+	}{id, context.Cause(ctx).Error()}
+	if err := sess.Notify(context.WithoutCancel(ctx), "notifications/cancelled", params); err != nil {
+		sess.server.logger.Debug("failed to send cancellation", "session", sess.id, "id", string(id), "error", err)
+	}
+}
+
+// handleResponse passes a response from the client to the request waiting for it
+func (sess *Session) handleResponse(msg message) {
+	sess.mu.Lock()
+	responses, ok := sess.pending[string(msg.ID)]
+	delete(sess.pending, string(msg.ID))
+	sess.mu.Unlock()
+
+	if !ok {
+		sess.server.logger.Debug("response to unknown request", "session", sess.id, "id", string(msg.ID))
+		return
+	}
+	responses <- msg
+}
+
+// endInput records that no more messages will arrive from the client, so
+// requests waiting for a response and later requests fail with ErrSessionClosed
+func (sess *Session) endInput() {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.inputEnded = true
+	sess.failRequests()
+}
+
+// failRequests fails the requests waiting for a response from the client.
+// Callers must hold sess.mu.
+func (sess *Session) failRequests() {
+	for id, responses := range sess.pending {
+		close(responses)
+		delete(sess.pending, id)
+	}
+}
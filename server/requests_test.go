@@ -0,0 +1,239 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Convict3d/mcp-go/client"
+	mcphttp "github.com/Convict3d/mcp-go/transport/http"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// addAskTool registers a tool that samples, elicits and lists roots through
+// its request
+func addAskTool(srv *Server) {
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "ask"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		var sampling types.CreateMessageRequest
+		sampling.Params.Messages = []types.SamplingMessage{{Role: "user", Content: types.TextContent{Type: types.ContentTypeText, Text: "Hi"}}}
+		sampling.Params.MaxTokens = 10
+		message, err := req.CreateMessage(ctx, &sampling)
+		if err != nil {
+			return nil, err
+		}
+
+		var elicit types.ElicitRequest
+		elicit.Params.Message = "Your name?"
+		elicit.Params.RequestedSchema = types.ElicitRequestSchema{
+			Type:       "object",
+			Properties: map[string]types.PrimitiveSchemaDefinition{"name": types.StringSchema{Type: "string"}},
+		}
+		answer, err := req.Elicit(ctx, &elicit)
+		if err != nil {
+			return nil, err
+		}
+
+		roots, err := req.ListRoots(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return textResult(fmt.Sprintf("%s %s %v %d", message.Model, answer.Action, answer.Content["name"], len(roots.Roots))), nil
+	})
+}
+
+// askClientOptions returns the options of a client answering the requests of
+// the ask tool, recording the sampling params in sampled
+func askClientOptions(sampled *[]interface{}) []client.Option {
+	return []client.Option{
+		client.WithClientCapabilities(types.ClientCapabilities{
+			Sampling:    &types.SamplingCapability{},
+			Elicitation: &types.ElicitationCapability{},
+			Roots:       &types.RootsCapability{},
+		}),
+		client.WithRequestHandler("sampling/createMessage", func(ctx context.Context, params interface{}) (interface{}, error) {
+			*sampled = append(*sampled, params)
+			return map[string]interface{}{"role": "assistant", "content": map[string]interface{}{"type": "text", "text": "Hello"}, "model": "test-model"}, nil
+		}),
+		client.WithRequestHandler("elicitation/create", func(ctx context.Context, params interface{}) (interface{}, error) {
+			return map[string]interface{}{"action": "accept", "content": map[string]interface{}{"name": "Ada"}}, nil
+		}),
+		client.WithRequestHandler("roots/list", func(ctx context.Context, params interface{}) (interface{}, error) {
+			return map[string]interface{}{"roots": []interface{}{map[string]interface{}{"uri": "file:///src"}}}, nil
+		}),
+	}
+}
+
+func TestServerRequests(t *testing.T) {
+	srv := New("test", "1.0.0")
+	addAskTool(srv)
+
+	var sampled []interface{}
+	c, _ := servePipe(t, context.Background(), srv, askClientOptions(&sampled)...)
+	defer c.Close()
+
+	result, err := c.CallTool("ask", nil)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if texts := result.GetTextStrings(); result.IsError || len(texts) != 1 || texts[0] != "test-model accept Ada 1" {
+		t.Errorf("Result = %+v", result)
+	}
+	if params, _ := sampled[0].(map[string]interface{}); len(sampled) != 1 || params["maxTokens"] != float64(10) {
+		t.Errorf("Sampling params = %v", sampled)
+	}
+}
+
+func TestServerRequestsHTTP(t *testing.T) {
+	srv := New("test", "1.0.0")
+	addAskTool(srv)
+	ts := newHTTPTestServer(t, srv)
+
+	var sampled []interface{}
+	c := client.NewClient(append([]client.Option{client.WithTransport(mcphttp.NewHTTPTransport(ts.URL))}, askClientOptions(&sampled)...)...)
+	defer c.Close()
+	if err := c.Initialize(types.LatestProtocolVersion); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	result, err := c.CallTool("ask", nil)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if texts := result.GetTextStrings(); result.IsError || len(texts) != 1 || texts[0] != "test-model accept Ada 1" {
+		t.Errorf("Result = %+v", result)
+	}
+}
+
+func TestServerRequestsUnsupported(t *testing.T) {
+	srv := New("test", "1.0.0")
+	addAskTool(srv)
+	c, _ := servePipe(t, context.Background(), srv)
+	defer c.Close()
+
+	result, err := c.CallTool("ask", nil)
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if texts := result.GetTextStrings(); !result.IsError || texts[0] != "sampling: not supported by the client" {
+		t.Errorf("Result = %+v", result)
+	}
+
+	sess := srv.Sessions()[0]
+	if _, err := sess.ListRoots(context.Background()); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("ListRoots error = %v", err)
+	}
+}
+
+func TestServerRequestsFailOnClose(t *testing.T) {
+	srv := New("test", "1.0.0")
+	sess := newTestSession(t, srv)
+	sess.call(t, "initialize", map[string]interface{}{
+		"protocolVersion": types.LatestProtocolVersion,
+		"capabilities":    map[string]interface{}{"roots": map[string]interface{}{}},
+	}, &types.InitializeResult{})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := sess.ListRoots(context.Background())
+		done <- err
+	}()
+	waitSent(t, sess, 1)
+	sess.Close()
+	if err := <-done; !errors.Is(err, ErrSessionClosed) {
+		t.Errorf("ListRoots error = %v", err)
+	}
+
+	// Client errors and late responses
+	sess = newTestSession(t, srv)
+	sess.call(t, "initialize", map[string]interface{}{
+		"protocolVersion": types.LatestProtocolVersion,
+		"capabilities":    map[string]interface{}{"roots": map[string]interface{}{}},
+	}, &types.InitializeResult{})
+	go func() {
+		_, err := sess.ListRoots(context.Background())
+		done <- err
+	}()
+	waitSent(t, sess, 1)
+	sess.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"no roots"}}`))
+	var rpcErr *Error
+	if err := <-done; !errors.As(err, &rpcErr) || rpcErr.Message != "no roots" {
+		t.Errorf("ListRoots error = %v", err)
+	}
+	if response := sess.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":7,"result":{}}`)); response != nil {
+		t.Errorf("Response to unknown request answered with %s", response)
+	}
+}
+
+func TestServerRequestsCancelled(t *testing.T) {
+	srv := New("test", "1.0.0")
+	sess := newTestSession(t, srv)
+	sess.call(t, "initialize", map[string]interface{}{
+		"protocolVersion": types.LatestProtocolVersion,
+		"capabilities":    map[string]interface{}{"roots": map[string]interface{}{}},
+	}, &types.InitializeResult{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := sess.ListRoots(ctx)
+		done <- err
+	}()
+	waitSent(t, sess, 1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("ListRoots error = %v", err)
+	}
+
+	// The client learns that the request was abandoned
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if len(sess.sent) != 2 || sess.sent[1].Method != "notifications/cancelled" || string(sess.sent[1].Params) != `{"requestId":1,"reason":"context canceled"}` {
+		t.Errorf("Sent %+v", sess.sent)
+	}
+}
+
+func TestServerRequestsFailOnEOF(t *testing.T) {
+	srv := New("test", "1.0.0")
+	addAskTool(srv)
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- ServeStreams(context.Background(), srv, inReader, outWriter)
+		outWriter.Close()
+	}()
+	output := bufio.NewScanner(outReader)
+
+	io.WriteString(inWriter, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"sampling":{}}}}`+"\n")
+	output.Scan()
+	io.WriteString(inWriter, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"ask"}}`+"\n")
+	if !output.Scan() || !strings.Contains(output.Text(), `"method":"sampling/createMessage"`) {
+		t.Fatalf("Expected a sampling request, got %s", output.Text())
+	}
+
+	// The client goes away without answering
+	inWriter.Close()
+	if !output.Scan() || !strings.Contains(output.Text(), `"text":"sampling/createMessage: session closed"`) {
+		t.Errorf("Response = %s", output.Text())
+	}
+	if err := <-done; err != nil {
+		t.Errorf("ServeStreams failed: %v", err)
+	}
+}
+
+// waitSent waits until the session has sent n messages
+func waitSent(t *testing.T, ts *testSession, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(ts.notifications()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Session sent %d messages, expected %d", len(ts.notifications()), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
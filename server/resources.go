@@ -0,0 +1,66 @@
+package server
+
+import (
+	"context"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// ResourceContents is the contents of a resource as returned by a handler:
+// a types.TextResourceContents or a types.BlobResourceContents
+type ResourceContents interface {
+	Contents() types.ResourceContents
+}
+
+// ReadResult returns a read result holding the given text and blob contents
+func ReadResult(contents ...ResourceContents) *types.ReadResourceResult {
+	result := &types.ReadResourceResult{Contents: make([]types.ResourceContents, 0, len(contents))}
+	for _, c := range contents {
+		result.Contents = append(result.Contents, c.Contents())
+	}
+	return result
+}
+
+// NotifyResourceUpdated tells the sessions subscribed to uri that the
+// resource changed, so that they can read it again
+func (s *Server) NotifyResourceUpdated(uri string) {
+	s.mu.RLock()
+	sessions := make([]*Session, 0, len(s.subscriptions[uri]))
+	for session := range s.subscriptions[uri] {
+		sessions = append(sessions, session)
+	}
+	s.mu.RUnlock()
+
+	params := map[string]string{"uri": uri}
+	for _, session := range sessions {
+		if err := session.Notify(context.Background(), "notifications/resources/updated", params); err != nil {
+			s.logger.Debug("failed to send notification", "method", "notifications/resources/updated", "session", session.ID(), "error", err)
+		}
+	}
+}
+
+// subscribe records that session wants updates of uri
+func (s *Server) subscribe(session *Session, uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.subscriptions[uri] == nil {
+		s.subscriptions[uri] = make(map[*Session]bool)
+	}
+	s.subscriptions[uri][session] = true
+}
+
+// unsubscribe removes the subscription of session to uri
+func (s *Server) unsubscribe(session *Session, uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unsubscribeLocked(session, uri)
+}
+
+// unsubscribeLocked removes a subscription. Callers must hold s.mu.
+func (s *Server) unsubscribeLocked(session *Session, uri string) {
+	delete(s.subscriptions[uri], session)
+	if len(s.subscriptions[uri]) == 0 {
+		delete(s.subscriptions, uri)
+	}
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+func TestReadResult(t *testing.T) {
+	srv := New("test", "1.0.0")
+	srv.AddResource(types.Resource{BaseMetadata: types.BaseMetadata{Name: "logo"}, URI: "file:///logo.png"}, func(ctx context.Context, req *ResourceRequest) (*types.ReadResourceResult, error) {
+		return ReadResult(
+			types.TextResourceContents{ResourceContents: types.ResourceContents{URI: req.URI, MimeType: "text/plain"}, Text: "alt text"},
+			types.BlobResourceContents{ResourceContents: types.ResourceContents{URI: req.URI, MimeType: "image/png"}, Blob: "iVBORw0K"},
+		), nil
+	})
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	var result types.ReadResourceResult
+	sess.call(t, "resources/read", map[string]interface{}{"uri": "file:///logo.png"}, &result)
+	if len(result.Contents) != 2 {
+		t.Fatalf("Contents = %+v", result.Contents)
+	}
+	if c := result.Contents[0]; c.Text != "alt text" || c.Blob != "" || c.MimeType != "text/plain" {
+		t.Errorf("Text contents = %+v", c)
+	}
+	if c := result.Contents[1]; c.Blob != "iVBORw0K" || c.Text != "" || c.URI != "file:///logo.png" {
+		t.Errorf("Blob contents = %+v", c)
+	}
+}
+
+func TestResourceSubscriptions(t *testing.T) {
+	srv := newTestServer()
+	sess := newTestSession(t, srv)
+	other := newTestSession(t, srv)
+	result := sess.initialize(t)
+	other.initialize(t)
+	if result.Capabilities.Resources == nil || !result.Capabilities.Resources.Subscribe {
+		t.Errorf("Resources capability = %+v", result.Capabilities.Resources)
+	}
+
+	var empty struct{}
+	sess.call(t, "resources/subscribe", map[string]interface{}{"uri": "file:///readme.md"}, &empty)
+	sess.call(t, "resources/subscribe", map[string]interface{}{"uri": "users://42/profile"}, &empty)
+	other.call(t, "resources/subscribe", map[string]interface{}{"uri": "users://42/profile"}, &empty)
+
+	response := sess.request(t, "resources/subscribe", map[string]interface{}{"uri": "file:///missing"})
+	if response.Error == nil || response.Error.Code != CodeResourceNotFound {
+		t.Errorf("Subscribe to missing resource: %+v", response.Error)
+	}
+
+	srv.NotifyResourceUpdated("file:///readme.md")
+	srv.NotifyResourceUpdated("users://42/profile")
+	srv.NotifyResourceUpdated("users://7/profile")
+	sess.call(t, "resources/unsubscribe", map[string]interface{}{"uri": "file:///readme.md"}, &empty)
+	srv.NotifyResourceUpdated("file:///readme.md")
+
+	expected := []string{"file:///readme.md", "users://42/profile"}
+	if got := updatedURIs(t, sess); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Updated = %v, expected %v", got, expected)
+	}
+	if got := updatedURIs(t, other); fmt.Sprint(got) != "[users://42/profile]" {
+		t.Errorf("Other session updated = %v", got)
+	}
+
+	other.Close()
+	sess.Close()
+	srv.mu.RLock()
+	defer srv.mu.RUnlock()
+	if len(srv.subscriptions) != 0 {
+		t.Errorf("Subscriptions of closed sessions = %v", srv.subscriptions)
+	}
+}
+
+// updatedURIs returns the URIs of the resources/updated notifications sent to ts
+func updatedURIs(t *testing.T, ts *testSession) []string {
+	t.Helper()
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	var uris []string
+	for _, msg := range ts.sent {
+		if msg.Method != "notifications/resources/updated" {
+			continue
+		}
+		var params struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			t.Fatalf("Invalid params %s: %v", msg.Params, err)
+		}
+		uris = append(uris, params.URI)
+	}
+	return uris
+}
@@ -0,0 +1,439 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Convict3d/mcp-go/types"
+	"github.com/Convict3d/mcp-go/uritemplate"
+)
+
+// ToolHandler handles tools/call requests for one tool. Returning an *Error
+// fails the request; any other error is returned to the client as a tool
+// result with isError set.
+type ToolHandler func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error)
+
+// ResourceHandler reads a static resource or a resource matched by a template
+type ResourceHandler func(ctx context.Context, req *ResourceRequest) (*types.ReadResourceResult, error)
+
+// PromptHandler handles prompts/get requests for one prompt
+type PromptHandler func(ctx context.Context, req *PromptRequest) (*types.GetPromptResult, error)
+
+// PromptMessages adapts a function returning the prompt messages to a PromptHandler
+func PromptMessages(fn func(ctx context.Context, req *PromptRequest) ([]types.PromptMessage, error)) PromptHandler {
+	return func(ctx context.Context, req *PromptRequest) (*types.GetPromptResult, error) {
+		messages, err := fn(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		return &types.GetPromptResult{Messages: messages}, nil
+	}
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithInstructions sets the instructions returned to clients during initialization
+func WithInstructions(instructions string) Option {
+	return func(s *Server) {
+		s.instructions = instructions
+	}
+}
+
+// WithPageSize sets the maximum number of items returned by one list request.
+// The default of 0 returns every item in one page.
+func WithPageSize(size int) Option {
+	return func(s *Server) {
+		s.pageSize = size
+	}
+}
+
+// WithLogger sets the logger for server errors, such as panics in handlers.
+// The default logs to stderr, never to stdout.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// WithRequestTimeout limits the time a handler may take to answer a request.
+// The handler's context is cancelled after timeout and the request fails with
+// an internal error. The default of 0 sets no limit.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(s *Server) {
+		s.requestTimeout = timeout
+	}
+}
+
+// WithCapabilities sets the function returning the capabilities advertised in
+// initialize, in place of those derived from what is registered. Servers that
+// front other servers use it to advertise what those servers support.
+func WithCapabilities(capabilities func() types.ServerCapabilities) Option {
+	return func(s *Server) {
+		s.advertised = capabilities
+	}
+}
+
+type tool struct {
+	tool    types.Tool
+	handler ToolHandler
+}
+
+type resource struct {
+	resource types.Resource
+	handler  ResourceHandler
+}
+
+type resourceTemplate struct {
+	template    types.ResourceTemplate
+	parsed      *uritemplate.Template
+	handler     ResourceHandler
+	completions map[string]CompletionProvider
+}
+
+type prompt struct {
+	prompt      types.Prompt
+	handler     PromptHandler
+	completions map[string]CompletionProvider
+}
+
+// Server is an MCP server. Register tools, resources and prompts, then serve
+// it over a transport; each connection is served by a Session.
+type Server struct {
+	info           types.Implementation
+	instructions   string
+	pageSize       int
+	logger         *slog.Logger
+	requestTimeout time.Duration
+	advertised     func() types.ServerCapabilities // set by WithCapabilities
+
+	mu        sync.RWMutex
+	tools     map[string]*tool
+	resources map[string]*resource
+	templates map[string]*resourceTemplate
+	prompts   map[string]*prompt
+	sessions  map[*Session]bool
+
+	// Sessions subscribed to resource updates, by resource URI
+	subscriptions map[string]map[*Session]bool
+
+	logging bool // a LogHandler sends log messages to clients
+}
+
+// New creates a server that identifies itself with name and version
+func New(name, version string, opts ...Option) *Server {
+	s := &Server{
+		info:      types.Implementation{Name: name, Version: version},
+		logger:    slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		tools:     make(map[string]*tool),
+		resources: make(map[string]*resource),
+		templates: make(map[string]*resourceTemplate),
+		prompts:   make(map[string]*prompt),
+		sessions:  make(map[*Session]bool),
+
+		subscriptions: make(map[string]map[*Session]bool),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// AddTool registers a tool, replacing any tool with the same name.
+// Connected clients are told that the tool list changed.
+func (s *Server) AddTool(t types.Tool, handler ToolHandler) {
+	if t.InputSchema.Type == "" {
+		t.InputSchema.Type = "object"
+	}
+
+	s.mu.Lock()
+	s.tools[t.Name] = &tool{tool: t, handler: handler}
+	s.mu.Unlock()
+
+	s.broadcast("notifications/tools/list_changed")
+}
+
+// RemoveTools unregisters tools by name
+func (s *Server) RemoveTools(names ...string) {
+	if s.remove(names, func(name string) bool {
+		_, ok := s.tools[name]
+		delete(s.tools, name)
+		return ok
+	}) {
+		s.broadcast("notifications/tools/list_changed")
+	}
+}
+
+// AddResource registers a resource with a fixed URI, replacing any resource
+// with the same URI
+func (s *Server) AddResource(r types.Resource, handler ResourceHandler) {
+	s.mu.Lock()
+	s.resources[r.URI] = &resource{resource: r, handler: handler}
+	s.mu.Unlock()
+
+	s.broadcast("notifications/resources/list_changed")
+}
+
+// RemoveResources unregisters resources by URI
+func (s *Server) RemoveResources(uris ...string) {
+	if s.remove(uris, func(uri string) bool {
+		_, ok := s.resources[uri]
+		delete(s.resources, uri)
+		return ok
+	}) {
+		s.broadcast("notifications/resources/list_changed")
+	}
+}
+
+// AddResourceTemplate registers a resource template. Reads of URIs matching
+// the RFC 6570 template are passed to handler with the matched variables.
+// Template variables are completed by the providers given with WithCompletion.
+func (s *Server) AddResourceTemplate(t types.ResourceTemplate, handler ResourceHandler, opts ...CompletionOption) error {
+	parsed, err := uritemplate.Parse(t.URITemplate)
+	if err != nil {
+		return fmt.Errorf("invalid resource template %q: %w", t.URITemplate, err)
+	}
+
+	s.mu.Lock()
+	s.templates[t.URITemplate] = &resourceTemplate{
+		template:    t,
+		parsed:      parsed,
+		handler:     handler,
+		completions: completionProviders(opts),
+	}
+	s.mu.Unlock()
+
+	s.broadcast("notifications/resources/list_changed")
+	return nil
+}
+
+// RemoveResourceTemplates unregisters resource templates
+func (s *Server) RemoveResourceTemplates(uriTemplates ...string) {
+	if s.remove(uriTemplates, func(uriTemplate string) bool {
+		_, ok := s.templates[uriTemplate]
+		delete(s.templates, uriTemplate)
+		return ok
+	}) {
+		s.broadcast("notifications/resources/list_changed")
+	}
+}
+
+// AddPrompt registers a prompt, replacing any prompt with the same name.
+// Requests missing an argument marked as required in p.Arguments are rejected
+// before handler is called. Arguments are completed by the providers given
+// with WithCompletion.
+func (s *Server) AddPrompt(p types.Prompt, handler PromptHandler, opts ...CompletionOption) {
+	s.mu.Lock()
+	s.prompts[p.Name] = &prompt{prompt: p, handler: handler, completions: completionProviders(opts)}
+	s.mu.Unlock()
+
+	s.broadcast("notifications/prompts/list_changed")
+}
+
+// RemovePrompts unregisters prompts by name
+func (s *Server) RemovePrompts(names ...string) {
+	if s.remove(names, func(name string) bool {
+		_, ok := s.prompts[name]
+		delete(s.prompts, name)
+		return ok
+	}) {
+		s.broadcast("notifications/prompts/list_changed")
+	}
+}
+
+// remove calls del for every key under the lock and reports whether any entry was removed
+func (s *Server) remove(keys []string, del func(key string) bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := false
+	for _, key := range keys {
+		if del(key) {
+			removed = true
+		}
+	}
+	return removed
+}
+
+// Sessions returns the connected sessions
+func (s *Server) Sessions() []*Session {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sessions := make([]*Session, 0, len(s.sessions))
+	for session := range s.sessions {
+		sessions = append(sessions, session)
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].id < sessions[j].id })
+	return sessions
+}
+
+// broadcast sends a notification without params to every initialized session
+func (s *Server) broadcast(method string) {
+	for _, session := range s.Sessions() {
+		if session.Initialized() {
+			if err := session.Notify(context.Background(), method, nil); err != nil {
+				s.logger.Debug("failed to send notification", "method", method, "session", session.ID(), "error", err)
+			}
+		}
+	}
+}
+
+// capabilities returns the capabilities advertised for what is registered,
+// or those set with WithCapabilities
+func (s *Server) capabilities() types.ServerCapabilities {
+	if s.advertised != nil {
+		return s.advertised()
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var caps types.ServerCapabilities
+	if len(s.tools) > 0 {
+		caps.Tools = &types.ToolsCapability{ListChanged: true}
+	}
+	if len(s.resources) > 0 || len(s.templates) > 0 {
+		caps.Resources = &types.ResourcesCapability{Subscribe: true, ListChanged: true}
+	}
+	if len(s.prompts) > 0 {
+		caps.Prompts = &types.PromptsCapability{ListChanged: true}
+	}
+	if s.logging {
+		caps.Logging = &types.LoggingCapability{}
+	}
+	if s.hasCompletions() {
+		caps.Completions = &types.CompletionsCapability{}
+	}
+	return caps
+}
+
+// hasCompletions reports whether any prompt or template has a completion
+// provider. Callers must hold s.mu.
+func (s *Server) hasCompletions() bool {
+	for _, p := range s.prompts {
+		if len(p.completions) > 0 {
+			return true
+		}
+	}
+	for _, t := range s.templates {
+		if len(t.completions) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// listTools returns the registered tools sorted by name
+func (s *Server) listTools() []types.Tool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tools := make([]types.Tool, 0, len(s.tools))
+	for _, t := range s.tools {
+		tools = append(tools, t.tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+// listResources returns the registered resources sorted by URI
+func (s *Server) listResources() []types.Resource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resources := make([]types.Resource, 0, len(s.resources))
+	for _, r := range s.resources {
+		resources = append(resources, r.resource)
+	}
+	sort.Slice(resources, func(i, j int) bool { return resources[i].URI < resources[j].URI })
+	return resources
+}
+
+// listResourceTemplates returns the registered templates sorted by URI template
+func (s *Server) listResourceTemplates() []types.ResourceTemplate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	templates := make([]types.ResourceTemplate, 0, len(s.templates))
+	for _, t := range s.templates {
+		templates = append(templates, t.template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].URITemplate < templates[j].URITemplate })
+	return templates
+}
+
+// listPrompts returns the registered prompts sorted by name
+func (s *Server) listPrompts() []types.Prompt {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	prompts := make([]types.Prompt, 0, len(s.prompts))
+	for _, p := range s.prompts {
+		prompts = append(prompts, p.prompt)
+	}
+	sort.Slice(prompts, func(i, j int) bool { return prompts[i].Name < prompts[j].Name })
+	return prompts
+}
+
+// lookupTool returns the registered tool with the given name, or nil
+func (s *Server) lookupTool(name string) *tool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tools[name]
+}
+
+// lookupPrompt returns the registered prompt with the given name, or nil
+func (s *Server) lookupPrompt(name string) *prompt {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.prompts[name]
+}
+
+// lookupResource returns the handler for uri and the variables matched by a
+// template. Static resources take precedence over templates, and templates
+// are tried in sorted order.
+func (s *Server) lookupResource(uri string) (ResourceHandler, map[string]string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if r, ok := s.resources[uri]; ok {
+		return r.handler, nil
+	}
+
+	keys := make([]string, 0, len(s.templates))
+	for key := range s.templates {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		t := s.templates[key]
+		if vars, ok := t.parsed.Match(uri); ok {
+			return t.handler, vars
+		}
+	}
+	return nil, nil
+}
+
+// addSession registers a connected session
+func (s *Server) addSession(session *Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session] = true
+}
+
+// removeSession forgets a closed session and its subscriptions
+func (s *Server) removeSession(session *Session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, session)
+	for uri := range s.subscriptions {
+		s.unsubscribeLocked(session, uri)
+	}
+}
@@ -0,0 +1,426 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// testSession records the messages a session sends to the client
+type testSession struct {
+	*Session
+	mu   sync.Mutex
+	sent []message
+	id   int
+}
+
+// newTestSession creates a session on srv without initializing it
+func newTestSession(t *testing.T, srv *Server) *testSession {
+	t.Helper()
+	ts := &testSession{}
+	ts.Session = srv.NewSession(func(ctx context.Context, data []byte) error {
+		var msg message
+		if err := json.Unmarshal(data, &msg); err != nil {
+			t.Errorf("Session sent invalid JSON %s: %v", data, err)
+		}
+		ts.mu.Lock()
+		ts.sent = append(ts.sent, msg)
+		ts.mu.Unlock()
+		return nil
+	})
+	t.Cleanup(func() { ts.Close() })
+	return ts
+}
+
+// initialize performs the initialize handshake and returns the result
+func (ts *testSession) initialize(t *testing.T) types.InitializeResult {
+	t.Helper()
+	var result types.InitializeResult
+	ts.call(t, "initialize", map[string]interface{}{
+		"protocolVersion": types.LatestProtocolVersion,
+		"capabilities":    map[string]interface{}{"sampling": map[string]interface{}{}},
+		"clientInfo":      map[string]interface{}{"name": "test-client", "version": "1.0.0"},
+	}, &result)
+	ts.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`))
+	return result
+}
+
+// request sends a request and returns the decoded response
+func (ts *testSession) request(t *testing.T, method string, params interface{}) message {
+	t.Helper()
+	ts.id++
+	data, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": ts.id, "method": method, "params": params})
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	var response message
+	if err := json.Unmarshal(ts.HandleMessage(context.Background(), data), &response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if string(response.ID) != fmt.Sprint(ts.id) {
+		t.Errorf("Response id = %s, expected %d", response.ID, ts.id)
+	}
+	return response
+}
+
+// call sends a request that must succeed and decodes its result into result
+func (ts *testSession) call(t *testing.T, method string, params interface{}, result interface{}) {
+	t.Helper()
+	response := ts.request(t, method, params)
+	if response.Error != nil {
+		t.Fatalf("%s failed: %v", method, response.Error)
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		t.Fatalf("Failed to decode %s result %s: %v", method, response.Result, err)
+	}
+}
+
+// notifications returns the methods of the notifications sent so far
+func (ts *testSession) notifications() []string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	var methods []string
+	for _, msg := range ts.sent {
+		methods = append(methods, msg.Method)
+	}
+	return methods
+}
+
+func textResult(text string) *types.CallToolResult {
+	return &types.CallToolResult{Content: []interface{}{types.TextContent{Type: types.ContentTypeText, Text: text}}}
+}
+
+func newTestServer() *Server {
+	srv := New("test-server", "1.0.0",
+		WithInstructions("Use the echo tool"),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "echo"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		if SessionFromContext(ctx) != req.Session {
+			return nil, errors.New("context does not carry the session")
+		}
+		return textResult(fmt.Sprint(req.Arguments["text"])), nil
+	})
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "fail"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		return nil, errors.New("disk full")
+	})
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "reject"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		return nil, NewError(CodeInvalidParams, "bad input")
+	})
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "panic"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		panic("boom")
+	})
+
+	srv.AddResource(types.Resource{BaseMetadata: types.BaseMetadata{Name: "readme"}, URI: "file:///readme.md"}, func(ctx context.Context, req *ResourceRequest) (*types.ReadResourceResult, error) {
+		return &types.ReadResourceResult{Contents: []types.ResourceContents{{URI: req.URI, Text: "# Readme"}}}, nil
+	})
+	if err := srv.AddResourceTemplate(types.ResourceTemplate{BaseMetadata: types.BaseMetadata{Name: "users"}, URITemplate: "users://{id}/profile"}, func(ctx context.Context, req *ResourceRequest) (*types.ReadResourceResult, error) {
+		return &types.ReadResourceResult{Contents: []types.ResourceContents{{URI: req.URI, Text: "user " + req.Vars["id"]}}}, nil
+	}); err != nil {
+		panic(err)
+	}
+
+	srv.AddPrompt(types.Prompt{BaseMetadata: types.BaseMetadata{Name: "greet"}}, func(ctx context.Context, req *PromptRequest) (*types.GetPromptResult, error) {
+		return &types.GetPromptResult{Messages: []types.PromptMessage{
+			{Role: "user", Content: types.TextContent{Type: types.ContentTypeText, Text: "Hello " + req.Arguments["name"]}},
+		}}, nil
+	})
+	return srv
+}
+
+func TestInitialize(t *testing.T) {
+	srv := newTestServer()
+	sess := newTestSession(t, srv)
+
+	if sess.Initialized() {
+		t.Error("Session is initialized before the handshake")
+	}
+	result := sess.initialize(t)
+
+	if result.ProtocolVersion != types.LatestProtocolVersion {
+		t.Errorf("Protocol version = %q", result.ProtocolVersion)
+	}
+	if result.ServerInfo.Name != "test-server" || result.ServerInfo.Version != "1.0.0" {
+		t.Errorf("Server info = %+v", result.ServerInfo)
+	}
+	if result.Instructions != "Use the echo tool" {
+		t.Errorf("Instructions = %q", result.Instructions)
+	}
+	caps := result.Capabilities
+	if caps.Tools == nil || !caps.Tools.ListChanged || caps.Resources == nil || caps.Prompts == nil {
+		t.Errorf("Capabilities = %+v", caps)
+	}
+	if caps.Logging != nil || caps.Completions != nil {
+		t.Errorf("Unexpected capabilities: %+v", caps)
+	}
+
+	if !sess.Initialized() {
+		t.Error("Session is not initialized after the handshake")
+	}
+	if sess.ClientInfo().Name != "test-client" || sess.ClientCapabilities().Sampling == nil {
+		t.Errorf("Client info = %+v, capabilities = %+v", sess.ClientInfo(), sess.ClientCapabilities())
+	}
+
+	if response := sess.request(t, "initialize", map[string]interface{}{"protocolVersion": types.LatestProtocolVersion}); response.Error == nil || response.Error.Code != CodeInvalidRequest {
+		t.Errorf("Second initialize: expected an invalid request error, got %+v", response)
+	}
+}
+
+func TestWithCapabilities(t *testing.T) {
+	var caps types.ServerCapabilities
+	srv := New("proxy", "1.0.0", WithCapabilities(func() types.ServerCapabilities { return caps }))
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "echo"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		return textResult("echo"), nil
+	})
+
+	sess := newTestSession(t, srv)
+	if result := sess.initialize(t); result.Capabilities.Tools != nil {
+		t.Errorf("Capabilities = %+v, expected the advertised ones", result.Capabilities)
+	}
+	if response := sess.request(t, "logging/setLevel", map[string]interface{}{"level": "debug"}); response.Error == nil || response.Error.Code != CodeMethodNotFound {
+		t.Errorf("logging/setLevel without logging: %+v", response.Error)
+	}
+
+	caps.Logging = &types.LoggingCapability{}
+	sess.call(t, "logging/setLevel", map[string]interface{}{"level": "debug"}, &struct{}{})
+	if sess.LogLevel() != types.LoggingLevelDebug {
+		t.Errorf("LogLevel = %q", sess.LogLevel())
+	}
+}
+
+func TestInitializeVersionNegotiation(t *testing.T) {
+	tests := map[string]string{
+		"2024-11-05": "2024-11-05",
+		"2025-03-26": "2025-03-26",
+		"1999-01-01": types.LatestProtocolVersion,
+	}
+	for requested, expected := range tests {
+		sess := newTestSession(t, New("test", "1.0.0"))
+		var result types.InitializeResult
+		sess.call(t, "initialize", map[string]interface{}{"protocolVersion": requested}, &result)
+		if result.ProtocolVersion != expected || sess.ProtocolVersion() != expected {
+			t.Errorf("Requested %s: negotiated %s, expected %s", requested, result.ProtocolVersion, expected)
+		}
+		if result.Capabilities.Tools != nil || result.Capabilities.Resources != nil || result.Capabilities.Prompts != nil {
+			t.Errorf("Empty server advertises capabilities: %+v", result.Capabilities)
+		}
+	}
+}
+
+func TestProtocolErrors(t *testing.T) {
+	srv := newTestServer()
+	sess := newTestSession(t, srv)
+
+	tests := []struct {
+		message string
+		id      string
+		code    int
+	}{
+		{`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, "1", CodeInvalidRequest},
+		{`{not json`, "null", CodeParseError},
+		{`[{"jsonrpc":"2.0","id":1,"method":"ping"}]`, "null", CodeInvalidRequest},
+		{`{"jsonrpc":"1.0","id":2,"method":"ping"}`, "2", CodeInvalidRequest},
+		{`{"jsonrpc":"2.0","id":"a"}`, "", 0},
+	}
+	for _, tt := range tests {
+		response := sess.HandleMessage(context.Background(), []byte(tt.message))
+		if tt.code == 0 {
+			if response != nil {
+				t.Errorf("%s: expected no response, got %s", tt.message, response)
+			}
+			continue
+		}
+		var msg message
+		if err := json.Unmarshal(response, &msg); err != nil {
+			t.Fatalf("%s: invalid response %s", tt.message, response)
+		}
+		if msg.Error == nil || msg.Error.Code != tt.code || string(msg.ID) != tt.id {
+			t.Errorf("%s: response %s, expected code %d and id %s", tt.message, response, tt.code, tt.id)
+		}
+	}
+
+	var pong struct{}
+	sess.call(t, "ping", nil, &pong)
+
+	sess.initialize(t)
+	if response := sess.request(t, "tools/unknown", nil); response.Error == nil || response.Error.Code != CodeMethodNotFound {
+		t.Errorf("Unknown method: %+v", response.Error)
+	}
+	if response := sess.request(t, "tools/call", []int{1}); response.Error == nil || response.Error.Code != CodeInvalidParams {
+		t.Errorf("Invalid params: %+v", response.Error)
+	}
+	if response := sess.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"notifications/unknown"}`)); response != nil {
+		t.Errorf("Notification got a response: %s", response)
+	}
+}
+
+func TestCallTool(t *testing.T) {
+	srv := newTestServer()
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	var result types.CallToolResult
+	sess.call(t, "tools/call", map[string]interface{}{"name": "echo", "arguments": map[string]interface{}{"text": "hi"}}, &result)
+	if texts := result.GetTextStrings(); len(texts) != 1 || texts[0] != "hi" || result.IsError {
+		t.Errorf("echo result = %+v", result)
+	}
+
+	result = types.CallToolResult{}
+	sess.call(t, "tools/call", map[string]interface{}{"name": "fail"}, &result)
+	if texts := result.GetTextStrings(); !result.IsError || len(texts) != 1 || texts[0] != "disk full" {
+		t.Errorf("fail result = %+v", result)
+	}
+
+	tests := map[string]int{"reject": CodeInvalidParams, "panic": CodeInternalError, "missing": CodeInvalidParams}
+	for name, code := range tests {
+		response := sess.request(t, "tools/call", map[string]interface{}{"name": name})
+		if response.Error == nil || response.Error.Code != code {
+			t.Errorf("%s: error = %+v, expected code %d", name, response.Error, code)
+		}
+	}
+
+	if response := sess.request(t, "tools/call", map[string]interface{}{"name": "echo", "arguments": []int{1}}); response.Error == nil || response.Error.Code != CodeInvalidParams {
+		t.Errorf("Non-object arguments: %+v", response.Error)
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	srv := New("test", "1.0.0", WithPageSize(2))
+	for _, name := range []string{"e", "c", "a", "d", "b"} {
+		srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: name}}, nil)
+	}
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	var names []string
+	params := map[string]interface{}{}
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("Pagination does not end")
+		}
+		var result types.ListToolsResult
+		sess.call(t, "tools/list", params, &result)
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+			if tool.InputSchema.Type != "object" {
+				t.Errorf("Tool %s input schema type = %q", tool.Name, tool.InputSchema.Type)
+			}
+		}
+		if result.NextCursor == nil {
+			break
+		}
+		params["cursor"] = *result.NextCursor
+	}
+	if strings.Join(names, "") != "abcde" {
+		t.Errorf("Listed tools %v", names)
+	}
+
+	if response := sess.request(t, "tools/list", map[string]interface{}{"cursor": "bogus"}); response.Error == nil || response.Error.Code != CodeInvalidParams {
+		t.Errorf("Invalid cursor: %+v", response.Error)
+	}
+}
+
+func TestResources(t *testing.T) {
+	srv := newTestServer()
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	var list types.ListResourcesResult
+	sess.call(t, "resources/list", nil, &list)
+	if len(list.Resources) != 1 || list.Resources[0].URI != "file:///readme.md" {
+		t.Errorf("Resources = %+v", list.Resources)
+	}
+	var templates types.ListResourceTemplatesResult
+	sess.call(t, "resources/templates/list", nil, &templates)
+	if len(templates.ResourceTemplates) != 1 || templates.ResourceTemplates[0].URITemplate != "users://{id}/profile" {
+		t.Errorf("Templates = %+v", templates.ResourceTemplates)
+	}
+
+	for uri, text := range map[string]string{"file:///readme.md": "# Readme", "users://42/profile": "user 42"} {
+		var result types.ReadResourceResult
+		sess.call(t, "resources/read", map[string]interface{}{"uri": uri}, &result)
+		if len(result.Contents) != 1 || result.Contents[0].Text != text || result.Contents[0].URI != uri {
+			t.Errorf("Read %s = %+v", uri, result)
+		}
+	}
+
+	response := sess.request(t, "resources/read", map[string]interface{}{"uri": "file:///missing"})
+	if response.Error == nil || response.Error.Code != CodeResourceNotFound {
+		t.Errorf("Missing resource: %+v", response.Error)
+	}
+
+	if err := srv.AddResourceTemplate(types.ResourceTemplate{URITemplate: "users://{id"}, nil); err == nil {
+		t.Error("Expected an error for an invalid template")
+	}
+}
+
+func TestPrompts(t *testing.T) {
+	srv := newTestServer()
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	var list types.ListPromptsResult
+	sess.call(t, "prompts/list", nil, &list)
+	if len(list.Prompts) != 1 || list.Prompts[0].Name != "greet" {
+		t.Errorf("Prompts = %+v", list.Prompts)
+	}
+
+	var result types.GetPromptResult
+	sess.call(t, "prompts/get", map[string]interface{}{"name": "greet", "arguments": map[string]string{"name": "Ada"}}, &result)
+	if len(result.Messages) != 1 {
+		t.Fatalf("Messages = %+v", result.Messages)
+	}
+	if text, ok := result.Messages[0].Content.(types.TextContent); !ok || text.Text != "Hello Ada" {
+		t.Errorf("Message content = %#v", result.Messages[0].Content)
+	}
+
+	if response := sess.request(t, "prompts/get", map[string]interface{}{"name": "missing"}); response.Error == nil || response.Error.Code != CodeInvalidParams {
+		t.Errorf("Missing prompt: %+v", response.Error)
+	}
+}
+
+func TestListChanged(t *testing.T) {
+	srv := newTestServer()
+	pending := newTestSession(t, srv)
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "new"}}, nil)
+	srv.RemoveTools("new", "missing")
+	srv.RemoveTools("missing")
+	srv.AddPrompt(types.Prompt{BaseMetadata: types.BaseMetadata{Name: "new"}}, nil)
+	srv.RemoveResources("file:///readme.md")
+
+	expected := []string{
+		"notifications/tools/list_changed",
+		"notifications/tools/list_changed",
+		"notifications/prompts/list_changed",
+		"notifications/resources/list_changed",
+	}
+	if got := sess.notifications(); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Notifications = %v, expected %v", got, expected)
+	}
+	if got := pending.notifications(); len(got) != 0 {
+		t.Errorf("Uninitialized session got notifications %v", got)
+	}
+
+	if len(srv.Sessions()) != 2 {
+		t.Errorf("Sessions = %d, expected 2", len(srv.Sessions()))
+	}
+	sess.Close()
+	if len(srv.Sessions()) != 1 {
+		t.Errorf("Closed session is still listed")
+	}
+	if err := sess.Notify(context.Background(), "notifications/message", nil); !errors.Is(err, ErrSessionClosed) {
+		t.Errorf("Notify after Close: %v", err)
+	}
+}
@@ -0,0 +1,329 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// ErrSessionClosed is returned when sending to a session that has been closed
+var ErrSessionClosed = errors.New("session closed")
+
+// supportedVersions lists the protocol versions the server accepts. Clients
+// asking for any other version are offered the latest one.
+var supportedVersions = map[string]bool{
+	types.LatestProtocolVersion: true,
+	"2025-03-26":                true,
+	"2024-11-05":                true,
+}
+
+// SendFunc writes one encoded JSON-RPC message to the client
+type SendFunc func(ctx context.Context, msg []byte) error
+
+// message is a JSON-RPC message as read from or written to the wire
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Session is one client connection to a server. Transports create a session
+// per connection, pass every message they read to HandleMessage and close the
+// session when the connection ends.
+type Session struct {
+	server *Server
+	id     string
+	send   SendFunc
+
+	mu              sync.Mutex
+	clientInfo      types.Implementation
+	caps            types.ClientCapabilities
+	protocolVersion string
+	initializing    bool // initialize has been answered
+	initialized     bool // notifications/initialized has been received
+	closed          bool
+	logLevel        types.LoggingLevel // set by logging/setLevel
+
+	// Requests sent to the client awaiting a response, by JSON-RPC id
+	nextID     int64
+	pending    map[string]chan message
+	inputEnded bool // the transport will deliver no more responses
+
+	// Cancel functions of the requests being handled, by JSON-RPC id
+	inflight map[string]context.CancelCauseFunc
+}
+
+// NewSession creates a session that writes messages to the client with send
+func (s *Server) NewSession(send SendFunc) *Session {
+	session := &Session{
+		server:   s,
+		id:       newSessionID(),
+		send:     send,
+		pending:  make(map[string]chan message),
+		inflight: make(map[string]context.CancelCauseFunc),
+	}
+	s.addSession(session)
+	return session
+}
+
+// newSessionID returns a random session id
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate session id: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// ID returns the unique id of the session
+func (sess *Session) ID() string {
+	return sess.id
+}
+
+// Server returns the server the session belongs to
+func (sess *Session) Server() *Server {
+	return sess.server
+}
+
+// ClientInfo returns the client implementation sent in initialize
+func (sess *Session) ClientInfo() types.Implementation {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.clientInfo
+}
+
+// ClientCapabilities returns the capabilities the client declared in initialize
+func (sess *Session) ClientCapabilities() types.ClientCapabilities {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.caps
+}
+
+// ProtocolVersion returns the negotiated protocol version, or "" before initialize
+func (sess *Session) ProtocolVersion() string {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.protocolVersion
+}
+
+// LogLevel returns the minimum log level the client asked for with
+// logging/setLevel, or "" if it did not ask
+func (sess *Session) LogLevel() types.LoggingLevel {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.logLevel
+}
+
+// Initialized reports whether the client completed the initialize handshake
+// and the session is still open
+func (sess *Session) Initialized() bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.initialized && !sess.closed
+}
+
+// isClosed reports whether the session has been closed
+func (sess *Session) isClosed() bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.closed
+}
+
+// Close ends the session. Later sends and requests waiting for a response
+// from the client fail with ErrSessionClosed.
+func (sess *Session) Close() error {
+	sess.mu.Lock()
+	sess.closed = true
+	sess.failRequests()
+	sess.mu.Unlock()
+
+	sess.server.removeSession(sess)
+	return nil
+}
+
+// Notify sends a notification to the client
+func (sess *Session) Notify(ctx context.Context, method string, params interface{}) error {
+	msg := message{JSONRPC: types.JSONRPCVersion, Method: method}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s params: %w", method, err)
+		}
+		msg.Params = data
+	}
+	return sess.write(ctx, msg)
+}
+
+// write encodes and sends a message unless the session is closed
+func (sess *Session) write(ctx context.Context, msg message) error {
+	if sess.isClosed() {
+		return ErrSessionClosed
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return sess.send(ctx, data)
+}
+
+// HandleMessage handles one message from the client and returns the encoded
+// response, or nil for notifications, responses and requests the client
+// cancelled. Requests may be handled concurrently; transports usually call
+// HandleMessage in a goroutine.
+func (sess *Session) HandleMessage(ctx context.Context, data []byte) []byte {
+	msg, rpcErr := decodeMessage(data)
+	if rpcErr != nil {
+		return encodeError(nil, rpcErr)
+	}
+
+	hasID := msg.hasID()
+	switch {
+	case msg.JSONRPC != types.JSONRPCVersion:
+		if !hasID && msg.Method != "" {
+			return nil
+		}
+		return encodeError(msg.ID, NewError(CodeInvalidRequest, "jsonrpc must be %q", types.JSONRPCVersion))
+	case msg.Method == "" && hasID:
+		sess.handleResponse(msg)
+		return nil
+	case msg.Method == "":
+		return encodeError(msg.ID, NewError(CodeInvalidRequest, "missing method"))
+	case !hasID:
+		sess.handleNotification(msg)
+		return nil
+	}
+
+	result, err := sess.dispatch(ctx, msg)
+	if errors.Is(err, errRequestCancelled) {
+		return nil
+	}
+	if err != nil {
+		return encodeError(msg.ID, toError(err))
+	}
+
+	resultData, err := json.Marshal(result)
+	if err != nil {
+		return encodeError(msg.ID, NewError(CodeInternalError, "failed to marshal result: %v", err))
+	}
+	response, _ := json.Marshal(message{JSONRPC: types.JSONRPCVersion, ID: msg.ID, Result: resultData})
+	return response
+}
+
+// decodeMessage decodes one JSON-RPC message
+func decodeMessage(data []byte) (message, *Error) {
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		var array []json.RawMessage
+		if json.Unmarshal(data, &array) == nil {
+			return msg, NewError(CodeInvalidRequest, "batch requests are not supported")
+		}
+		return msg, NewError(CodeParseError, "parse error: %v", err)
+	}
+	return msg, nil
+}
+
+// hasID reports whether the message has an id, which makes it a request
+// if it has a method and a response if it does not
+func (m *message) hasID() bool {
+	return len(m.ID) > 0 && string(m.ID) != "null"
+}
+
+// handleNotification handles a notification from the client
+func (sess *Session) handleNotification(msg message) {
+	switch msg.Method {
+	case "notifications/initialized":
+		sess.mu.Lock()
+		if sess.initializing {
+			sess.initialized = true
+		}
+		sess.mu.Unlock()
+	case "notifications/cancelled":
+		sess.handleCancelled(msg.Params)
+	}
+}
+
+// dispatch runs the handler for a request, turning panics into internal errors
+func (sess *Session) dispatch(ctx context.Context, msg message) (result interface{}, err error) {
+	handler, ok := methods[msg.Method]
+	if !ok {
+		return nil, NewError(CodeMethodNotFound, "method not found: %s", msg.Method)
+	}
+
+	sess.mu.Lock()
+	ready := sess.initializing
+	sess.mu.Unlock()
+	if !ready && msg.Method != "initialize" && msg.Method != "ping" {
+		return nil, NewError(CodeInvalidRequest, "session is not initialized")
+	}
+
+	var meta struct {
+		Meta types.Meta `json:"_meta"`
+	}
+	if len(msg.Params) > 0 {
+		if err := json.Unmarshal(msg.Params, &meta); err != nil {
+			return nil, NewError(CodeInvalidParams, "invalid params: %v", err)
+		}
+	}
+
+	req := &Request{
+		Session: sess,
+		Method:  msg.Method,
+		ID:      msg.ID,
+		Params:  msg.Params,
+		Meta:    meta.Meta,
+	}
+
+	// The initialize request cannot be cancelled
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	if msg.Method != "initialize" {
+		defer sess.startRequest(msg.ID, cancel)()
+	}
+	if timeout := sess.server.requestTimeout; timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, timeout, errRequestTimeout)
+		defer cancelTimeout()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			sess.server.logger.Error("panic in handler", "method", msg.Method, "panic", r)
+			result, err = nil, NewError(CodeInternalError, "internal error")
+		}
+		switch {
+		case errors.Is(context.Cause(ctx), errRequestCancelled):
+			result, err = nil, errRequestCancelled
+		case errors.Is(context.Cause(ctx), errRequestTimeout):
+			result, err = nil, NewError(CodeInternalError, "request timed out after %s", sess.server.requestTimeout)
+		}
+	}()
+	return handler(withRequest(ctx, req), req)
+}
+
+// toError converts a handler error to a JSON-RPC error
+func toError(err error) *Error {
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	return NewError(CodeInternalError, "%v", err)
+}
+
+// encodeError encodes an error response. A missing id is sent as null.
+func encodeError(id json.RawMessage, rpcErr *Error) []byte {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	data, _ := json.Marshal(message{JSONRPC: types.JSONRPCVersion, ID: id, Error: rpcErr})
+	return data
+}
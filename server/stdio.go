@@ -0,0 +1,109 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// maxMessageSize is the largest message accepted over stdio
+const maxMessageSize = 10 * 1024 * 1024
+
+// ServeStdio serves srv to one client over the process's stdin and stdout.
+// It returns nil when stdin ends, ctx is cancelled or the process receives an
+// interrupt or termination signal. Nothing but protocol messages is written
+// to stdout; the server logger writes to stderr.
+func ServeStdio(ctx context.Context, srv *Server) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := ServeStreams(ctx, srv, os.Stdin, os.Stdout)
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// ServeStreams serves srv to one client reading newline-delimited JSON-RPC
+// messages from r and writing them to w. Requests are handled concurrently
+// and writes to w are serialized.
+//
+// ServeStreams returns nil when r ends, after the requests in flight have
+// been answered. Requests sent to the client that are still waiting for a
+// response fail with ErrSessionClosed. When ctx is cancelled the handlers' contexts are cancelled
+// and ServeStreams returns ctx.Err() once they return.
+func ServeStreams(ctx context.Context, srv *Server, r io.Reader, w io.Writer) error {
+	var writeMu sync.Mutex
+	write := func(msg []byte) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, err := w.Write(append(msg, '\n'))
+		return err
+	}
+
+	sess := srv.NewSession(func(ctx context.Context, msg []byte) error {
+		return write(msg)
+	})
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	defer func() {
+		close(done)
+		// Handlers waiting for a response from the client would never return
+		sess.endInput()
+		wg.Wait()
+		sess.Close()
+	}()
+
+	// The reader runs on its own so that cancellation is not held up by a
+	// blocked read; it exits at the next line once ServeStreams returns
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), maxMessageSize)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			select {
+			case lines <- append([]byte(nil), line...):
+			case <-done:
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case err := <-readErr:
+			if err != nil {
+				return fmt.Errorf("failed to read message: %w", err)
+			}
+			return nil
+
+		case msg := <-lines:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if response := sess.HandleMessage(ctx, msg); response != nil {
+					if err := write(response); err != nil {
+						srv.logger.Error("failed to write response", "session", sess.ID(), "error", err)
+					}
+				}
+			}()
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Convict3d/mcp-go/client"
+	"github.com/Convict3d/mcp-go/transport/stdio"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// servePipe serves srv over pipes and returns a client created with opts
+// connected to it and the channel ServeStreams reports its result on
+func servePipe(t *testing.T, ctx context.Context, srv *Server, opts ...client.Option) (*client.Client, <-chan error) {
+	t.Helper()
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		done <- ServeStreams(ctx, srv, inReader, outWriter)
+		outWriter.Close()
+	}()
+
+	tr, err := stdio.NewTransportFromStreams(inWriter, outReader, nil)
+	if err != nil {
+		t.Fatalf("Failed to create transport: %v", err)
+	}
+	c := client.NewClient(append([]client.Option{client.WithTransport(tr), client.WithTimeout(5 * time.Second)}, opts...)...)
+	if err := c.Initialize(types.LatestProtocolVersion); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+	return c, done
+}
+
+func TestServeStreams(t *testing.T) {
+	srv := newTestServer()
+	release := make(chan struct{})
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "wait"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		<-release
+		return textResult("released"), nil
+	})
+
+	c, done := servePipe(t, context.Background(), srv)
+	if info := c.GetServerInfo(); info == nil || info.Name != "test-server" {
+		t.Errorf("Server info = %+v", info)
+	}
+
+	tools, err := c.ListTools()
+	if err != nil || len(tools) != 5 {
+		t.Fatalf("ListTools = %d tools, %v", len(tools), err)
+	}
+
+	// A blocked tool call does not hold up other requests
+	waited := make(chan *types.CallToolResult, 1)
+	go func() {
+		result, err := c.CallTool("wait", nil)
+		if err != nil {
+			t.Errorf("CallTool(wait) failed: %v", err)
+		}
+		waited <- result
+	}()
+
+	results := make(chan error, 10)
+	for i := 0; i < cap(results); i++ {
+		go func() {
+			result, err := c.CallTool("echo", map[string]interface{}{"text": "hi"})
+			if err == nil && (len(result.GetTextStrings()) != 1 || result.GetTextStrings()[0] != "hi") {
+				err = errors.New("unexpected echo result")
+			}
+			results <- err
+		}()
+	}
+	for i := 0; i < cap(results); i++ {
+		if err := <-results; err != nil {
+			t.Errorf("CallTool(echo): %v", err)
+		}
+	}
+
+	close(release)
+	if result := <-waited; result == nil || result.GetTextStrings()[0] != "released" {
+		t.Errorf("wait result = %+v", result)
+	}
+
+	// Closing the client's end of stdin shuts the server down
+	c.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("ServeStreams returned %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeStreams did not return after EOF")
+	}
+	if len(srv.Sessions()) != 0 {
+		t.Errorf("Session was not closed")
+	}
+}
+
+func TestServeStreamsCancel(t *testing.T) {
+	srv := newTestServer()
+	started := make(chan struct{})
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "block"}}, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c, done := servePipe(t, ctx, srv)
+	defer c.Close()
+
+	go c.CallTool("block", nil)
+	<-started
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("ServeStreams returned %v, expected context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeStreams did not return after cancellation")
+	}
+}
+
+func TestServeStreamsNotifiesClient(t *testing.T) {
+	srv := newTestServer()
+	logger := slog.New(NewLogHandler(srv))
+	logs := make(chan types.LoggingMessageNotification, 1)
+	c, _ := servePipe(t, context.Background(), srv, client.WithCatalog(), client.WithLogSink(func(msg types.LoggingMessageNotification) {
+		logs <- msg
+	}))
+	defer c.Close()
+
+	// ServeStreams handles notifications/initialized concurrently with later messages
+	deadline := time.Now().Add(5 * time.Second)
+	for sessions := srv.Sessions(); len(sessions) != 1 || !sessions[0].Initialized(); sessions = srv.Sessions() {
+		if time.Now().After(deadline) {
+			t.Fatal("Session is not initialized after Initialize")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	changes := make(chan client.ListChange[types.Tool], 1)
+	c.Catalog().OnToolsChanged(func(change client.ListChange[types.Tool]) {
+		changes <- change
+	})
+	if _, err := c.Catalog().Tools(context.Background()); err != nil {
+		t.Fatalf("Tools failed: %v", err)
+	}
+
+	srv.AddTool(types.Tool{BaseMetadata: types.BaseMetadata{Name: "added"}}, nil)
+	select {
+	case change := <-changes:
+		if len(change.Added) != 1 || change.Added[0].Name != "added" {
+			t.Errorf("Change = %+v", change)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Catalog was not refreshed after tools/list_changed")
+	}
+
+	// Records logged outside a request go to the initialized session
+	logger.Info("started")
+	select {
+	case msg := <-logs:
+		if msg.Params.Data != "started" {
+			t.Errorf("Log message = %+v", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Log message did not reach the client")
+	}
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Convict3d/mcp-go/schema"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+// ToolOption configures a tool registered with AddTool
+type ToolOption func(*types.Tool)
+
+// WithToolAnnotations sets the annotations of a tool
+func WithToolAnnotations(annotations types.ToolAnnotations) ToolOption {
+	return func(t *types.Tool) {
+		t.Annotations = &annotations
+	}
+}
+
+// AddTool registers a tool implemented by a typed Go function. The input and
+// output schemas are derived from In and Out with schema.Reflect, so field
+// names, required fields, enums, descriptions and bounds come from the json
+// and jsonschema struct tags.
+//
+// Arguments are validated against the input schema and decoded into In;
+// arguments that do not conform fail the request with an invalid params
+// error. When Out encodes to a JSON object, the value returned by handler is
+// sent as structuredContent and as JSON text for clients that ignore
+// structured output; otherwise it is only sent as JSON text. A nil pointer or
+// map returned for an object Out is reported as a tool error, since the tool
+// declares an output schema. Handlers that need the request, such as its
+// session, can get it with RequestFromContext.
+//
+// AddTool fails if a schema cannot be derived or In does not encode to a
+// JSON object.
+func AddTool[In, Out any](srv *Server, name, description string, handler func(ctx context.Context, in In) (Out, error), opts ...ToolOption) error {
+	inSchema, err := schema.For[In]()
+	if err != nil {
+		return fmt.Errorf("tool %q: invalid input type: %w", name, err)
+	}
+	if inSchema["type"] != "object" {
+		return fmt.Errorf("tool %q: input type must encode to a JSON object", name)
+	}
+	outSchema, err := schema.For[Out]()
+	if err != nil {
+		return fmt.Errorf("tool %q: invalid output type: %w", name, err)
+	}
+	structured := outSchema["type"] == "object"
+
+	tool := types.Tool{
+		BaseMetadata: types.BaseMetadata{Name: name},
+		Description:  description,
+		InputSchema: types.ToolInputSchema{
+			Type:                 "object",
+			Properties:           properties(inSchema),
+			Required:             required(inSchema),
+			AdditionalProperties: inSchema["additionalProperties"],
+		},
+	}
+	if structured {
+		tool.OutputSchema = &types.ToolOutputSchema{
+			Type:                 "object",
+			Properties:           properties(outSchema),
+			Required:             required(outSchema),
+			AdditionalProperties: outSchema["additionalProperties"],
+		}
+	}
+	for _, opt := range opts {
+		opt(&tool)
+	}
+
+	srv.AddTool(tool, func(ctx context.Context, req *ToolRequest) (*types.CallToolResult, error) {
+		if err := schema.Validate(inSchema, req.Arguments); err != nil {
+			return nil, NewError(CodeInvalidParams, "invalid arguments for tool %q: %v", name, err)
+		}
+		var in In
+		if err := json.Unmarshal(req.RawArguments, &in); err != nil {
+			return nil, NewError(CodeInvalidParams, "invalid arguments for tool %q: %v", name, err)
+		}
+
+		out, err := handler(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		return toolResult(out, structured)
+	})
+	return nil
+}
+
+// toolResult encodes the output of a typed tool as JSON text and, when
+// structured is set, as structured content. Tools with an output schema must
+// return structured content, so a nil output fails the call with a tool error.
+func toolResult(out interface{}, structured bool) (*types.CallToolResult, error) {
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode tool output: %w", err)
+	}
+
+	result := &types.CallToolResult{
+		Content: []interface{}{types.TextContent{Type: types.ContentTypeText, Text: string(data)}},
+	}
+	if structured {
+		// A nil pointer or map encodes to null, which has no structured form
+		_ = json.Unmarshal(data, &result.StructuredContent)
+		if result.StructuredContent == nil {
+			return nil, errors.New("tool returned no output but declares an output schema")
+		}
+	}
+	return result, nil
+}
+
+// properties returns the properties of an object schema
+func properties(s map[string]interface{}) map[string]interface{} {
+	props, _ := s["properties"].(map[string]interface{})
+	return props
+}
+
+// required returns the required properties of an object schema
+func required(s map[string]interface{}) []string {
+	names, _ := s["required"].([]string)
+	return names
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Convict3d/mcp-go/client"
+	"github.com/Convict3d/mcp-go/types"
+)
+
+type forecastInput struct {
+	City  string `json:"city" jsonschema:"description=City name"`
+	Days  int    `json:"days" jsonschema:"minimum=1,maximum=14"`
+	Units string `json:"units,omitempty" jsonschema:"enum=metric|imperial"`
+}
+
+type forecastOutput struct {
+	City  string    `json:"city"`
+	Highs []float64 `json:"highs"`
+}
+
+func addForecastTool(t *testing.T, srv *Server) {
+	t.Helper()
+	err := AddTool(srv, "forecast", "Daily high temperatures", func(ctx context.Context, in forecastInput) (forecastOutput, error) {
+		if RequestFromContext(ctx) == nil {
+			return forecastOutput{}, errors.New("context does not carry the request")
+		}
+		if in.City == "Atlantis" {
+			return forecastOutput{}, errors.New("no weather under water")
+		}
+		out := forecastOutput{City: in.City}
+		for i := 0; i < in.Days; i++ {
+			out.Highs = append(out.Highs, 20+float64(i))
+		}
+		return out, nil
+	}, WithToolAnnotations(types.ToolAnnotations{ReadOnlyHint: true}))
+	if err != nil {
+		t.Fatalf("AddTool failed: %v", err)
+	}
+}
+
+func TestAddToolSchemas(t *testing.T) {
+	srv := New("test", "1.0.0")
+	addForecastTool(t, srv)
+	if err := AddTool(srv, "count", "", func(ctx context.Context, in map[string]string) (int, error) {
+		return len(in), nil
+	}); err != nil {
+		t.Fatalf("AddTool failed: %v", err)
+	}
+
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+	var list types.ListToolsResult
+	sess.call(t, "tools/list", nil, &list)
+	if len(list.Tools) != 2 {
+		t.Fatalf("Tools = %+v", list.Tools)
+	}
+
+	count, forecast := list.Tools[0], list.Tools[1]
+	in := forecast.InputSchema
+	if in.Type != "object" || strings.Join(in.Required, ",") != "city,days" || in.AdditionalProperties != false {
+		t.Errorf("Input schema = %+v", in)
+	}
+	days, _ := in.Properties["days"].(map[string]interface{})
+	if days["type"] != "integer" || days["minimum"] != float64(1) || days["maximum"] != float64(14) {
+		t.Errorf("days schema = %v", days)
+	}
+	if forecast.OutputSchema == nil || strings.Join(forecast.OutputSchema.Required, ",") != "city,highs" {
+		t.Errorf("Output schema = %+v", forecast.OutputSchema)
+	}
+	if forecast.Description != "Daily high temperatures" || forecast.Annotations == nil || !forecast.Annotations.ReadOnlyHint {
+		t.Errorf("Tool = %+v", forecast)
+	}
+	if count.OutputSchema != nil {
+		t.Errorf("Non-object output has an output schema: %+v", count.OutputSchema)
+	}
+
+	if err := AddTool(srv, "bad", "", func(ctx context.Context, in string) (int, error) { return 0, nil }); err == nil {
+		t.Error("Expected an error for a non-object input type")
+	}
+	if err := AddTool(srv, "bad", "", func(ctx context.Context, in struct{ C chan int }) (int, error) { return 0, nil }); err == nil {
+		t.Error("Expected an error for an unsupported input type")
+	}
+}
+
+func TestAddToolCall(t *testing.T) {
+	srv := New("test", "1.0.0")
+	addForecastTool(t, srv)
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	var result types.CallToolResult
+	sess.call(t, "tools/call", map[string]interface{}{"name": "forecast", "arguments": map[string]interface{}{"city": "Paris", "days": 2}}, &result)
+	if result.IsError || result.StructuredContent["city"] != "Paris" {
+		t.Errorf("Result = %+v", result)
+	}
+	if texts := result.GetTextStrings(); len(texts) != 1 || texts[0] != `{"city":"Paris","highs":[20,21]}` {
+		t.Errorf("Text fallback = %v", texts)
+	}
+
+	result = types.CallToolResult{}
+	sess.call(t, "tools/call", map[string]interface{}{"name": "forecast", "arguments": map[string]interface{}{"city": "Atlantis", "days": 1}}, &result)
+	if !result.IsError || result.GetTextStrings()[0] != "no weather under water" {
+		t.Errorf("Error result = %+v", result)
+	}
+
+	for _, arguments := range []map[string]interface{}{
+		{"city": "Paris"},
+		{"city": "Paris", "days": 30},
+		{"city": "Paris", "days": 2, "units": "kelvin"},
+		{"city": "Paris", "days": 2, "extra": true},
+		{"city": 7, "days": 2},
+	} {
+		response := sess.request(t, "tools/call", map[string]interface{}{"name": "forecast", "arguments": arguments})
+		if response.Error == nil || response.Error.Code != CodeInvalidParams {
+			t.Errorf("Arguments %v: error = %+v, expected invalid params", arguments, response.Error)
+		}
+	}
+}
+
+func TestAddToolCallTyped(t *testing.T) {
+	srv := New("test", "1.0.0")
+	addForecastTool(t, srv)
+	c, _ := servePipe(t, context.Background(), srv)
+	defer c.Close()
+
+	out, err := client.CallTyped[forecastInput, forecastOutput](context.Background(), c, "forecast", forecastInput{City: "Oslo", Days: 3})
+	if err != nil {
+		t.Fatalf("CallTyped failed: %v", err)
+	}
+	if out.City != "Oslo" || len(out.Highs) != 3 {
+		t.Errorf("Output = %+v", out)
+	}
+}
+
+func TestAddToolNilOutput(t *testing.T) {
+	srv := New("test", "1.0.0")
+	if err := AddTool(srv, "lookup", "", func(ctx context.Context, in struct{}) (*forecastOutput, error) {
+		return nil, nil
+	}); err != nil {
+		t.Fatalf("AddTool failed: %v", err)
+	}
+	sess := newTestSession(t, srv)
+	sess.initialize(t)
+
+	var result types.CallToolResult
+	sess.call(t, "tools/call", map[string]interface{}{"name": "lookup"}, &result)
+	if !result.IsError || result.StructuredContent != nil || !strings.Contains(result.GetTextStrings()[0], "no output") {
+		t.Errorf("Result = %+v, expected a tool error", result)
+	}
+}
+
+func TestAddToolNilOutputFields(t *testing.T) {
+	type searchOutput struct {
+		Items []string          `json:"items"`
+		Note  *string           `json:"note"`
+		Meta  map[string]string `json:"meta"`
+	}
+	srv := New("test", "1.0.0")
+	if err := AddTool(srv, "search", "", func(ctx context.Context, in struct{}) (searchOutput, error) {
+		return searchOutput{}, nil
+	}); err != nil {
+		t.Fatalf("AddTool failed: %v", err)
+	}
+	c, _ := servePipe(t, context.Background(), srv, client.WithOutputValidation())
+	defer c.Close()
+
+	out, err := client.CallTyped[struct{}, searchOutput](context.Background(), c, "search", struct{}{})
+	if err != nil {
+		t.Fatalf("CallTyped rejected the zero output: %v", err)
+	}
+	if out.Items != nil || out.Note != nil || out.Meta != nil {
+		t.Errorf("Output = %+v", out)
+	}
+}
@@ -2,10 +2,15 @@
 package http
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ybbus/jsonrpc/v3"
@@ -15,6 +20,10 @@ import (
 type SessionAwareHTTPClient struct {
 	client    *http.Client
 	sessionID string
+
+	handlerMu           sync.RWMutex
+	notificationHandler func(method string, params interface{})
+	requestHandler      func(method string, params interface{}) (interface{}, error)
 }
 
 // NewSessionAwareHTTPClient creates a new session-aware HTTP client
@@ -45,7 +54,7 @@ func (s *SessionAwareHTTPClient) Do(req *http.Request) (*http.Response, error) {
 
 	// Handle Server-Sent Events format
 	if strings.Contains(resp.Header.Get("Content-Type"), "text/event-stream") {
-		body, err := s.parseSSEResponse(resp.Body)
+		body, err := s.parseSSEResponse(resp.Body, req)
 		if err != nil {
 			resp.Body.Close()
 			return nil, err
@@ -61,31 +70,138 @@ func (s *SessionAwareHTTPClient) GetSessionID() string {
 	return s.sessionID
 }
 
-// parseSSEResponse parses Server-Sent Events format and extracts JSON data
-func (s *SessionAwareHTTPClient) parseSSEResponse(body io.ReadCloser) (io.ReadCloser, error) {
+// SetNotificationHandler sets the handler for notifications received in SSE streams
+func (s *SessionAwareHTTPClient) SetNotificationHandler(handler func(method string, params interface{})) {
+	s.handlerMu.Lock()
+	defer s.handlerMu.Unlock()
+	s.notificationHandler = handler
+}
+
+// SetRequestHandler sets the handler for server requests received in SSE streams
+func (s *SessionAwareHTTPClient) SetRequestHandler(handler func(method string, params interface{}) (interface{}, error)) {
+	s.handlerMu.Lock()
+	defer s.handlerMu.Unlock()
+	s.requestHandler = handler
+}
+
+// parseSSEResponse parses Server-Sent Events format and extracts JSON data.
+// Events are processed as they arrive: notifications and server requests are
+// dispatched to their handlers, while all other messages make up the returned
+// body. Responses to server requests are posted to the URL of req.
+func (s *SessionAwareHTTPClient) parseSSEResponse(body io.ReadCloser, req *http.Request) (io.ReadCloser, error) {
 	defer body.Close()
 
-	data, err := io.ReadAll(body)
-	if err != nil {
-		return nil, err
+	reader := bufio.NewReader(body)
+	var jsonData strings.Builder
+	var event []string
+
+	flush := func() {
+		if len(event) > 0 {
+			data := strings.Join(event, "\n")
+			if !s.dispatchMessage(data, req) {
+				jsonData.WriteString(data)
+			}
+		}
+		event = event[:0]
 	}
 
-	lines := strings.Split(string(data), "\n")
-	var jsonData strings.Builder
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
 
-	for _, line := range lines {
 		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "data: ") {
+		switch {
+		case line == "":
+			flush()
+		case strings.HasPrefix(line, "data: "):
 			jsonLine := strings.TrimPrefix(line, "data: ")
 			if jsonLine != "" && jsonLine != "[DONE]" {
-				jsonData.WriteString(jsonLine)
+				event = append(event, jsonLine)
 			}
 		}
+
+		if err == io.EOF {
+			flush()
+			break
+		}
 	}
 
 	return io.NopCloser(strings.NewReader(jsonData.String())), nil
 }
 
+// dispatchMessage hands server notifications and requests to their handlers.
+// It reports whether the message was consumed; responses are left for the caller.
+func (s *SessionAwareHTTPClient) dispatchMessage(data string, req *http.Request) bool {
+	var message struct {
+		ID     interface{} `json:"id"`
+		Method string      `json:"method"`
+		Params interface{} `json:"params"`
+	}
+	if err := json.Unmarshal([]byte(data), &message); err != nil || message.Method == "" {
+		return false
+	}
+
+	s.handlerMu.RLock()
+	notificationHandler := s.notificationHandler
+	requestHandler := s.requestHandler
+	s.handlerMu.RUnlock()
+
+	if message.ID == nil {
+		if notificationHandler != nil {
+			notificationHandler(message.Method, message.Params)
+		}
+		return true
+	}
+
+	// Server requests are answered with a separate POST; without a request to
+	// reply to there is nowhere to send the response
+	if req == nil {
+		return true
+	}
+
+	response := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      message.ID,
+	}
+	if requestHandler == nil {
+		response["error"] = map[string]interface{}{"code": -32601, "message": "Method not found"}
+	} else if result, err := requestHandler(message.Method, message.Params); err != nil {
+		response["error"] = map[string]interface{}{"code": -32000, "message": err.Error()}
+	} else {
+		response["result"] = result
+	}
+
+	_ = s.postMessage(req, response)
+	return true
+}
+
+// postMessage sends a JSON-RPC message to the endpoint of req, reusing its headers
+func (s *SessionAwareHTTPClient) postMessage(req *http.Request, message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	post, err := http.NewRequestWithContext(req.Context(), http.MethodPost, req.URL.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	post.Header = req.Header.Clone()
+	post.Header.Set("Content-Type", "application/json")
+	if s.sessionID != "" {
+		post.Header.Set("Mcp-Session-Id", s.sessionID)
+	}
+
+	resp, err := s.client.Do(post)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
 // Config represents transport configuration
 type Config struct {
 	ServerURL     string
@@ -215,6 +331,42 @@ func (t *HTTPTransport) Call(ctx context.Context, result interface{}, method str
 	return t.client.CallFor(ctx, result, method, params[0])
 }
 
+// Notify posts a JSON-RPC notification to the server
+func (t *HTTPTransport) Notify(ctx context.Context, method string, params interface{}) error {
+	notification := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+	}
+	if params != nil {
+		notification["params"] = params
+	}
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.config.ServerURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	for k, v := range t.config.CustomHeaders {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := t.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notification %s rejected with status %s", method, resp.Status)
+	}
+	return nil
+}
+
 // CallRaw makes a JSON-RPC call and returns the raw response
 func (t *HTTPTransport) CallRaw(ctx context.Context, method string, params interface{}) (map[string]interface{}, error) {
 	var result map[string]interface{}
@@ -227,6 +379,18 @@ func (t *HTTPTransport) GetSessionID() string {
 	return t.http.GetSessionID()
 }
 
+// SetNotificationHandler sets the handler for server notifications.
+// Notifications are delivered when the server streams them in SSE responses.
+func (t *HTTPTransport) SetNotificationHandler(handler func(method string, params interface{})) {
+	t.http.SetNotificationHandler(handler)
+}
+
+// SetRequestHandler sets the handler for server requests.
+// Requests streamed in SSE responses are answered with a POST to the server URL.
+func (t *HTTPTransport) SetRequestHandler(handler func(method string, params interface{}) (interface{}, error)) {
+	t.http.SetRequestHandler(handler)
+}
+
 // Close closes the transport (no-op for HTTP)
 func (t *HTTPTransport) Close() error {
 	return nil
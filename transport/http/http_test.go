@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -132,7 +133,7 @@ func TestParseSSEResponse(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			reader := io.NopCloser(strings.NewReader(tt.input))
-			result, err := client.parseSSEResponse(reader)
+			result, err := client.parseSSEResponse(reader, nil)
 			if err != nil {
 				t.Fatalf("parseSSEResponse failed: %v", err)
 			}
@@ -365,3 +366,128 @@ func TestHTTPTransportCallRaw(t *testing.T) {
 		t.Errorf("Expected result['raw'] = 'data', got '%v'", result["raw"])
 	}
 }
+
+func TestHTTPTransportSSENotifications(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("event: message\ndata: {\"jsonrpc\": \"2.0\", \"method\": \"notifications/message\", \"params\": {\"level\": \"info\", \"data\": \"working\"}}\n\n"))
+		w.Write([]byte("data: {\"jsonrpc\": \"2.0\", \"method\": \"notifications/progress\", \"params\": {\"progressToken\": 1, \"progress\": 50}}\n\n"))
+		w.Write([]byte("data: {\"jsonrpc\": \"2.0\", \"result\": {\"done\": true}, \"id\": 1}\n\n"))
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(server.URL)
+	defer transport.Close()
+
+	var methods []string
+	transport.SetNotificationHandler(func(method string, params interface{}) {
+		methods = append(methods, method)
+	})
+
+	var result map[string]interface{}
+	if err := transport.Call(context.Background(), &result, "tools/call"); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if result["done"] != true {
+		t.Errorf("Expected result['done'] = true, got %v", result["done"])
+	}
+
+	if len(methods) != 2 || methods[0] != "notifications/message" || methods[1] != "notifications/progress" {
+		t.Errorf("Expected notifications in order, got %v", methods)
+	}
+}
+
+func TestHTTPTransportSSEServerRequests(t *testing.T) {
+	replies := make(chan map[string]interface{}, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&message)
+
+		// The client's reply to the server request arrives as a separate POST
+		if _, isRequest := message["method"]; !isRequest {
+			replies <- message
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("data: {\"jsonrpc\": \"2.0\", \"id\": \"srv-1\", \"method\": \"roots/list\"}\n\n"))
+		w.(http.Flusher).Flush()
+
+		// Finish the stream only once the client has replied
+		for len(replies) == 0 {
+			time.Sleep(5 * time.Millisecond)
+		}
+		w.Write([]byte("data: {\"jsonrpc\": \"2.0\", \"result\": {\"done\": true}, \"id\": 1}\n\n"))
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(server.URL)
+	defer transport.Close()
+
+	var gotMethod string
+	transport.SetRequestHandler(func(method string, params interface{}) (interface{}, error) {
+		gotMethod = method
+		return map[string]interface{}{"roots": []interface{}{}}, nil
+	})
+
+	var result map[string]interface{}
+	if err := transport.Call(context.Background(), &result, "tools/call"); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+
+	if gotMethod != "roots/list" {
+		t.Errorf("Expected request handler to receive roots/list, got %q", gotMethod)
+	}
+	if result["done"] != true {
+		t.Errorf("Expected result['done'] = true, got %v", result["done"])
+	}
+
+	reply := <-replies
+	if reply["id"] != "srv-1" || reply["result"] == nil {
+		t.Errorf("Unexpected reply to server request: %v", reply)
+	}
+}
+
+func TestHTTPTransportNotify(t *testing.T) {
+	var body map[string]interface{}
+	var sessionID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID = r.Header.Get("Mcp-Session-Id")
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(server.URL)
+	defer transport.Close()
+	transport.http.sessionID = "session-1"
+
+	if err := transport.Notify(context.Background(), "notifications/initialized", nil); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+	if body["method"] != "notifications/initialized" || body["jsonrpc"] != "2.0" || body["id"] != nil || body["params"] != nil {
+		t.Errorf("Notification = %v", body)
+	}
+	if sessionID != "session-1" {
+		t.Errorf("Mcp-Session-Id = %q", sessionID)
+	}
+}
+
+func TestHTTPTransportNotifyRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	transport := NewHTTPTransport(server.URL)
+	defer transport.Close()
+
+	if err := transport.Notify(context.Background(), "notifications/initialized", nil); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Notify = %v, expected the 404 status", err)
+	}
+}
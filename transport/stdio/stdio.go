@@ -13,6 +13,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/Convict3d/mcp-go/transport"
 	"github.com/ybbus/jsonrpc/v3"
 )
 
@@ -399,7 +400,7 @@ func (t *Transport) sendMessage(message interface{}) error {
 	defer t.mu.Unlock()
 
 	if t.closed {
-		return fmt.Errorf("transport is closed")
+		return transport.ErrClosed
 	}
 
 	_, err = t.stdin.Write(append(data, '\n'))
@@ -436,7 +437,7 @@ func (t *Transport) Call(ctx context.Context, result interface{}, method string,
 	t.mu.RLock()
 	if t.closed {
 		t.mu.RUnlock()
-		return fmt.Errorf("transport is closed")
+		return transport.ErrClosed
 	}
 	t.mu.RUnlock()
 
@@ -496,10 +497,26 @@ func (t *Transport) Call(ctx context.Context, result interface{}, method string,
 		return ctx.Err()
 
 	case <-time.After(30 * time.Second):
-		return fmt.Errorf("request timeout")
+		return transport.ErrTimeout
 	}
 }
 
+// Notify sends a JSON-RPC notification over stdio
+func (t *Transport) Notify(ctx context.Context, method string, params interface{}) error {
+	notification := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+	}
+	if params != nil {
+		notification["params"] = params
+	}
+
+	if err := t.sendMessage(notification); err != nil {
+		return fmt.Errorf("failed to send notification: %w", err)
+	}
+	return nil
+}
+
 // CallRaw makes a JSON-RPC call and returns the raw response
 func (t *Transport) CallRaw(ctx context.Context, method string, params interface{}) (map[string]interface{}, error) {
 	var result map[string]interface{}
@@ -1,7 +1,18 @@
 // Package transport provides MCP transport layer declarations
 package transport
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrClosed is returned for calls made after the transport has been closed
+	ErrClosed = errors.New("transport is closed")
+
+	// ErrTimeout is returned when the server does not answer a request in time
+	ErrTimeout = errors.New("request timeout")
+)
 
 // Transport interface defines the transport layer
 type Transport interface {
@@ -10,3 +21,21 @@ type Transport interface {
 	GetSessionID() string
 	Close() error
 }
+
+// NotificationReceiver is implemented by transports that can deliver
+// notifications sent by the server
+type NotificationReceiver interface {
+	SetNotificationHandler(handler func(method string, params interface{}))
+}
+
+// RequestReceiver is implemented by transports that can deliver requests sent
+// by the server and return the handler's result or error as the response
+type RequestReceiver interface {
+	SetRequestHandler(handler func(method string, params interface{}) (interface{}, error))
+}
+
+// Notifier is implemented by transports that can send notifications, which
+// the server does not answer
+type Notifier interface {
+	Notify(ctx context.Context, method string, params interface{}) error
+}
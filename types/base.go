@@ -3,6 +3,7 @@ package types
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 // Protocol constants
@@ -269,6 +270,48 @@ func (rc ResourceContent) ContentType() string {
 	return ContentTypeResource
 }
 
+// UnmarshalContentBlock decodes a JSON content block into the concrete type
+// named by its type field
+func UnmarshalContentBlock(data []byte) (ContentBlock, error) {
+	var header struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, err
+	}
+
+	var block ContentBlock
+	var err error
+	switch header.Type {
+	case ContentTypeText:
+		var c TextContent
+		err = json.Unmarshal(data, &c)
+		block = c
+	case ContentTypeImage:
+		var c ImageContent
+		err = json.Unmarshal(data, &c)
+		block = c
+	case ContentTypeAudio:
+		var c AudioContent
+		err = json.Unmarshal(data, &c)
+		block = c
+	case ContentTypeResourceLink:
+		var c ResourceLinkContent
+		err = json.Unmarshal(data, &c)
+		block = c
+	case ContentTypeResource:
+		var c ResourceContent
+		err = json.Unmarshal(data, &c)
+		block = c
+	default:
+		return nil, fmt.Errorf("unknown content type %q", header.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
 // Capabilities
 
 // ClientCapabilities represents what the client supports
@@ -276,12 +319,14 @@ type ClientCapabilities struct {
 	Experimental map[string]interface{} `json:"experimental,omitempty"`
 	Sampling     *SamplingCapability    `json:"sampling,omitempty"`
 	Roots        *RootsCapability       `json:"roots,omitempty"`
+	Elicitation  *ElicitationCapability `json:"elicitation,omitempty"`
 }
 
 // ServerCapabilities represents what the server supports
 type ServerCapabilities struct {
 	Experimental map[string]interface{} `json:"experimental,omitempty"`
 	Logging      *LoggingCapability     `json:"logging,omitempty"`
+	Completions  *CompletionsCapability `json:"completions,omitempty"`
 	Prompts      *PromptsCapability     `json:"prompts,omitempty"`
 	Resources    *ResourcesCapability   `json:"resources,omitempty"`
 	Tools        *ToolsCapability       `json:"tools,omitempty"`
@@ -289,10 +334,12 @@ type ServerCapabilities struct {
 
 // Individual capability structures
 type SamplingCapability struct{}
+type ElicitationCapability struct{}
 type RootsCapability struct {
 	ListChanged bool `json:"listChanged,omitempty"`
 }
 type LoggingCapability struct{}
+type CompletionsCapability struct{}
 type PromptsCapability struct {
 	ListChanged bool `json:"listChanged,omitempty"`
 }
@@ -548,3 +548,24 @@ func equalValues(a, b interface{}) bool {
 
 	return a == b
 }
+
+func TestServerCapabilities_Completions(t *testing.T) {
+	data := `{"completions": {}, "tools": {"listChanged": true}}`
+
+	var caps ServerCapabilities
+	if err := json.Unmarshal([]byte(data), &caps); err != nil {
+		t.Fatalf("Failed to unmarshal ServerCapabilities: %v", err)
+	}
+
+	if caps.Completions == nil {
+		t.Error("Expected completions capability to be present")
+	}
+
+	var empty ServerCapabilities
+	if err := json.Unmarshal([]byte(`{}`), &empty); err != nil {
+		t.Fatalf("Failed to unmarshal ServerCapabilities: %v", err)
+	}
+	if empty.Completions != nil {
+		t.Error("Expected completions capability to be absent")
+	}
+}
@@ -1,6 +1,8 @@
 // Package types contains MCP protocol client feature definitions
 package types
 
+import "encoding/json"
+
 // Sampling and client feature types
 
 // SamplingMessage describes a message issued to or received from an LLM API
@@ -9,6 +11,30 @@ type SamplingMessage struct {
 	Content ContentBlock `json:"content"`
 }
 
+// UnmarshalJSON decodes the message content into its concrete content type
+func (sm *SamplingMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Role    Role            `json:"role"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	sm.Role = raw.Role
+	sm.Content = nil
+	if len(raw.Content) == 0 || string(raw.Content) == "null" {
+		return nil
+	}
+
+	content, err := UnmarshalContentBlock(raw.Content)
+	if err != nil {
+		return err
+	}
+	sm.Content = content
+	return nil
+}
+
 // CreateMessageRequest is a request from the server to sample an LLM via the client
 type CreateMessageRequest struct {
 	Method string `json:"method"`
@@ -31,6 +57,24 @@ type CreateMessageResult struct {
 	StopReason string `json:"stopReason,omitempty"` // "endTurn" | "stopSequence" | "maxTokens" | string
 }
 
+// UnmarshalJSON decodes the result, including the content of the embedded
+// message, which would otherwise hide the other fields
+func (r *CreateMessageResult) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Model      string `json:"model"`
+		StopReason string `json:"stopReason"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &r.SamplingMessage); err != nil {
+		return err
+	}
+	r.Model = raw.Model
+	r.StopReason = raw.StopReason
+	return nil
+}
+
 // ModelPreferences represents the server's preferences for model selection
 type ModelPreferences struct {
 	Hints                []ModelHint `json:"hints,omitempty"`
@@ -169,6 +213,12 @@ type CompleteRequest struct {
 // CompletionReference represents a reference to a resource or prompt
 type CompletionReference interface{}
 
+// Completion reference types
+const (
+	ReferenceTypePrompt   = "ref/prompt"
+	ReferenceTypeResource = "ref/resource"
+)
+
 // ResourceTemplateReference represents a reference to a resource template
 type ResourceTemplateReference struct {
 	Type string `json:"type"`
@@ -180,6 +180,22 @@ func TestCreateMessageResult_JSONSerialization(t *testing.T) {
 	}
 }
 
+func TestCreateMessageResult_JSONRoundTrip(t *testing.T) {
+	data := []byte(`{"role":"assistant","content":{"type":"text","text":"Hello"},"model":"test-model","stopReason":"maxTokens"}`)
+
+	var result CreateMessageResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Failed to unmarshal CreateMessageResult: %v", err)
+	}
+
+	if result.Role != RoleAssistant || result.Model != "test-model" || result.StopReason != "maxTokens" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+	if text, ok := result.Content.(TextContent); !ok || text.Text != "Hello" {
+		t.Errorf("Expected text content 'Hello', got %#v", result.Content)
+	}
+}
+
 func TestModelPreferences_JSONSerialization(t *testing.T) {
 	costPriority := 0.3
 	speedPriority := 0.7
@@ -1,6 +1,8 @@
 // Package types contains MCP protocol prompt definitions
 package types
 
+import "encoding/json"
+
 // Prompt represents a prompt or prompt template that the server offers
 type Prompt struct {
 	BaseMetadata
@@ -22,6 +24,30 @@ type PromptMessage struct {
 	Content ContentBlock `json:"content"`
 }
 
+// UnmarshalJSON decodes the message content into its concrete content type
+func (pm *PromptMessage) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Role    Role            `json:"role"`
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	pm.Role = raw.Role
+	pm.Content = nil
+	if len(raw.Content) == 0 || string(raw.Content) == "null" {
+		return nil
+	}
+
+	content, err := UnmarshalContentBlock(raw.Content)
+	if err != nil {
+		return err
+	}
+	pm.Content = content
+	return nil
+}
+
 // Prompt request/response types
 
 // ListPromptsRequest is sent from the client to request a list of prompts
@@ -41,12 +67,15 @@ type ListPromptsResult struct {
 
 // GetPromptRequest is used by the client to get a prompt provided by the server
 type GetPromptRequest struct {
-	Method string `json:"method"`
-	Params struct {
-		Name      string            `json:"name"`
-		Arguments map[string]string `json:"arguments,omitempty"`
-		Meta      Meta              `json:"_meta,omitempty"`
-	} `json:"params"`
+	Method string          `json:"method"`
+	Params GetPromptParams `json:"params"`
+}
+
+// GetPromptParams holds the parameters of a prompts/get request
+type GetPromptParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+	Meta      Meta              `json:"_meta,omitempty"`
 }
 
 // GetPromptResult is the server's response to a prompts/get request
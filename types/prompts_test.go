@@ -161,6 +161,34 @@ func TestPromptMessage_JSONSerialization(t *testing.T) {
 	}
 }
 
+func TestPromptMessage_UnmarshalJSON(t *testing.T) {
+	var result GetPromptResult
+	data := `{"messages": [
+		{"role": "user", "content": {"type": "text", "text": "Review this code"}},
+		{"role": "assistant", "content": {"type": "image", "data": "aGk=", "mimeType": "image/png"}}
+	]}`
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatalf("Failed to unmarshal GetPromptResult: %v", err)
+	}
+
+	if len(result.Messages) != 2 {
+		t.Fatalf("Expected 2 messages, got %d", len(result.Messages))
+	}
+	text, ok := result.Messages[0].Content.(TextContent)
+	if !ok || text.Text != "Review this code" {
+		t.Errorf("Expected text content, got %#v", result.Messages[0].Content)
+	}
+	image, ok := result.Messages[1].Content.(ImageContent)
+	if !ok || image.MimeType != "image/png" || result.Messages[1].Role != RoleAssistant {
+		t.Errorf("Expected image content from the assistant, got %#v", result.Messages[1])
+	}
+
+	var message PromptMessage
+	if err := json.Unmarshal([]byte(`{"role": "user", "content": {"type": "video"}}`), &message); err == nil {
+		t.Error("Expected an error for an unknown content type")
+	}
+}
+
 func TestListPromptsRequest(t *testing.T) {
 	request := ListPromptsRequest{
 		Method: "prompts/list",
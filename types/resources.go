@@ -22,10 +22,14 @@ type ResourceTemplate struct {
 	Meta        Meta         `json:"_meta,omitempty"`
 }
 
-// ResourceContents represents the contents of a specific resource or sub-resource
+// ResourceContents represents the contents of a specific resource or sub-resource.
+// Text holds the contents of text resources and Blob the base64-encoded
+// contents of binary resources.
 type ResourceContents struct {
 	URI      string `json:"uri"`
 	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
 	Meta     Meta   `json:"_meta,omitempty"`
 }
 
@@ -35,12 +39,28 @@ type TextResourceContents struct {
 	Text string `json:"text"`
 }
 
+// Contents returns the contents in the form used by ReadResourceResult
+func (c TextResourceContents) Contents() ResourceContents {
+	contents := c.ResourceContents
+	contents.Text = c.Text
+	contents.Blob = ""
+	return contents
+}
+
 // BlobResourceContents represents binary resource contents
 type BlobResourceContents struct {
 	ResourceContents
 	Blob string `json:"blob"`
 }
 
+// Contents returns the contents in the form used by ReadResourceResult
+func (c BlobResourceContents) Contents() ResourceContents {
+	contents := c.ResourceContents
+	contents.Blob = c.Blob
+	contents.Text = ""
+	return contents
+}
+
 // ResourceLink represents a resource that can be included in prompts or tool results
 type ResourceLink struct {
 	Resource
@@ -87,11 +107,14 @@ type ListResourceTemplatesResult struct {
 
 // ReadResourceRequest is sent from the client to read a specific resource
 type ReadResourceRequest struct {
-	Method string `json:"method"`
-	Params struct {
-		URI  string `json:"uri"`
-		Meta Meta   `json:"_meta,omitempty"`
-	} `json:"params"`
+	Method string             `json:"method"`
+	Params ReadResourceParams `json:"params"`
+}
+
+// ReadResourceParams holds the parameters of a resources/read request
+type ReadResourceParams struct {
+	URI  string `json:"uri"`
+	Meta Meta   `json:"_meta,omitempty"`
 }
 
 // ReadResourceResult is the server's response to a resources/read request
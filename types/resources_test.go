@@ -208,6 +208,24 @@ func TestBlobResourceContents(t *testing.T) {
 	}
 }
 
+func TestResourceContentsConversion(t *testing.T) {
+	text := TextResourceContents{
+		ResourceContents: ResourceContents{URI: "file:///a.txt", MimeType: "text/plain", Blob: "stale"},
+		Text:             "hello",
+	}.Contents()
+	if text.URI != "file:///a.txt" || text.MimeType != "text/plain" || text.Text != "hello" || text.Blob != "" {
+		t.Errorf("Text contents = %+v", text)
+	}
+
+	blob := BlobResourceContents{
+		ResourceContents: ResourceContents{URI: "file:///a.bin", Text: "stale"},
+		Blob:             "AAEC",
+	}.Contents()
+	if blob.URI != "file:///a.bin" || blob.Blob != "AAEC" || blob.Text != "" {
+		t.Errorf("Blob contents = %+v", blob)
+	}
+}
+
 func TestResourceLink(t *testing.T) {
 	link := ResourceLink{
 		Resource: Resource{
@@ -1,6 +1,12 @@
 // Package types contains MCP protocol tool definitions
 package types
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
 // Tool represents a tool the client can call
 type Tool struct {
 	BaseMetadata
@@ -13,16 +19,22 @@ type Tool struct {
 
 // ToolInputSchema defines the expected parameters for the tool
 type ToolInputSchema struct {
-	Type       string                 `json:"type"`
-	Properties map[string]interface{} `json:"properties,omitempty"`
-	Required   []string               `json:"required,omitempty"`
+	Type                 string                 `json:"type"`
+	Properties           map[string]interface{} `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"` // bool or schema object
+	Defs                 map[string]interface{} `json:"$defs,omitempty"`
+	Definitions          map[string]interface{} `json:"definitions,omitempty"` // Pre-2019-09 name for $defs
 }
 
 // ToolOutputSchema defines the structure of the tool's output
 type ToolOutputSchema struct {
-	Type       string                 `json:"type"`
-	Properties map[string]interface{} `json:"properties,omitempty"`
-	Required   []string               `json:"required,omitempty"`
+	Type                 string                 `json:"type"`
+	Properties           map[string]interface{} `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"` // bool or schema object
+	Defs                 map[string]interface{} `json:"$defs,omitempty"`
+	Definitions          map[string]interface{} `json:"definitions,omitempty"` // Pre-2019-09 name for $defs
 }
 
 // ToolAnnotations provide additional properties describing a Tool to clients
@@ -53,19 +65,45 @@ type ListToolsResult struct {
 
 // CallToolRequest is sent from the client to invoke a tool
 type CallToolRequest struct {
-	Method string `json:"method"`
-	Params struct {
-		Name      string                 `json:"name"`
-		Arguments map[string]interface{} `json:"arguments,omitempty"`
-		Meta      Meta                   `json:"_meta,omitempty"`
-	} `json:"params"`
+	Method string         `json:"method"`
+	Params CallToolParams `json:"params"`
+}
+
+// CallToolParams holds the parameters of a tools/call request
+type CallToolParams struct {
+	Name      string                 `json:"name"`
+	Arguments map[string]interface{} `json:"arguments,omitempty"`
+	Meta      Meta                   `json:"_meta,omitempty"`
 }
 
 // CallToolResult is the server's response to a tools/call request
 type CallToolResult struct {
-	Content []interface{} `json:"content"` // Using interface{} for flexible JSON unmarshaling
-	IsError bool          `json:"isError,omitempty"`
-	Meta    Meta          `json:"_meta,omitempty"`
+	Content           []interface{}          `json:"content"` // Using interface{} for flexible JSON unmarshaling
+	StructuredContent map[string]interface{} `json:"structuredContent,omitempty"`
+	IsError           bool                   `json:"isError,omitempty"`
+	Meta              Meta                   `json:"_meta,omitempty"`
+}
+
+// ErrNoStructuredContent is returned when a tool result carries no structured content
+var ErrNoStructuredContent = errors.New("tool result has no structured content")
+
+// DecodeStructuredContent decodes the structured content of a tool result into T
+func DecodeStructuredContent[T any](result *CallToolResult) (T, error) {
+	var out T
+	if result == nil || result.StructuredContent == nil {
+		return out, ErrNoStructuredContent
+	}
+
+	data, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return out, fmt.Errorf("failed to marshal structured content: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &out); err != nil {
+		return out, fmt.Errorf("failed to decode structured content: %w", err)
+	}
+
+	return out, nil
 }
 
 // GetTextContent extracts text content from the result as properly typed TextContent structs
@@ -2,6 +2,8 @@ package types
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -443,3 +445,80 @@ func TestToolComplexSchema(t *testing.T) {
 		}
 	}
 }
+
+func TestCallToolResult_StructuredContent(t *testing.T) {
+	data := `{
+		"content": [{"type": "text", "text": "{\"temperature\": 22.5}"}],
+		"structuredContent": {"temperature": 22.5, "conditions": "sunny"}
+	}`
+
+	var result CallToolResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatalf("Failed to unmarshal CallToolResult: %v", err)
+	}
+
+	if result.StructuredContent == nil {
+		t.Fatal("Expected structured content to be present")
+	}
+
+	type weather struct {
+		Temperature float64 `json:"temperature"`
+		Conditions  string  `json:"conditions"`
+	}
+
+	decoded, err := DecodeStructuredContent[weather](&result)
+	if err != nil {
+		t.Fatalf("DecodeStructuredContent failed: %v", err)
+	}
+
+	if decoded.Temperature != 22.5 || decoded.Conditions != "sunny" {
+		t.Errorf("Unexpected decoded value: %+v", decoded)
+	}
+}
+
+func TestDecodeStructuredContent_Missing(t *testing.T) {
+	result := &CallToolResult{}
+
+	_, err := DecodeStructuredContent[map[string]interface{}](result)
+	if !errors.Is(err, ErrNoStructuredContent) {
+		t.Errorf("Expected ErrNoStructuredContent, got %v", err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Failed to marshal CallToolResult: %v", err)
+	}
+	if strings.Contains(string(data), "structuredContent") {
+		t.Errorf("Expected structuredContent to be omitted, got %s", data)
+	}
+}
+
+func TestToolInputSchema_DefsAndAdditionalProperties(t *testing.T) {
+	data := `{
+		"type": "object",
+		"properties": {"home": {"$ref": "#/$defs/address"}},
+		"additionalProperties": false,
+		"$defs": {"address": {"type": "object"}}
+	}`
+
+	var schema ToolInputSchema
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		t.Fatalf("Failed to unmarshal ToolInputSchema: %v", err)
+	}
+
+	if schema.AdditionalProperties != false {
+		t.Errorf("Expected additionalProperties false, got %v", schema.AdditionalProperties)
+	}
+
+	if _, ok := schema.Defs["address"]; !ok {
+		t.Error("Expected $defs to be preserved")
+	}
+
+	out, err := json.Marshal(schema)
+	if err != nil {
+		t.Fatalf("Failed to marshal ToolInputSchema: %v", err)
+	}
+	if !strings.Contains(string(out), `"$defs"`) || !strings.Contains(string(out), `"additionalProperties":false`) {
+		t.Errorf("Expected $defs and additionalProperties in output, got %s", out)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Package uritemplate implements RFC 6570 URI templates for MCP resource templates.
+
+MCP servers advertise parameterized resources as URI templates such as
+"file:///{+path}" or "weather://{city}/forecast{?days}". This package expands
+templates into concrete URIs and matches concrete URIs back to a template to
+recover the variable values.
+
+# Expansion
+
+Expand a template with a map of variables:
+
+	tmpl, err := uritemplate.Parse("weather://{city}/forecast{?days,units}")
+	if err != nil {
+		return err
+	}
+
+	uri, err := tmpl.Expand(map[string]interface{}{
+		"city": "New York",
+		"days": 3,
+	})
+	// uri == "weather://New%20York/forecast?days=3"
+
+Variable values may be strings, numbers, booleans, lists ([]string or
+[]interface{}) and associative arrays (map[string]string or
+map[string]interface{}). Nil values, empty lists and empty maps are undefined
+and are omitted from the expansion as described by the RFC.
+
+# Supported Syntax
+
+All four levels of RFC 6570 are supported:
+
+  - Level 1: simple string expansion {var}
+  - Level 2: reserved {+var} and fragment {#var} expansion
+  - Level 3: multiple variables {x,y} and the label {.var}, path {/var},
+    path-style parameter {;var}, query {?var} and query continuation {&var}
+    operators
+  - Level 4: prefix {var:3} and explode {var*} modifiers
+
+# Matching
+
+Match reverses expansion for a concrete URI:
+
+	vars, ok := tmpl.Match("weather://Paris/forecast?days=5")
+	// ok == true, vars == map[string]string{"city": "Paris", "days": "5"}
+
+Matched values are percent-decoded. Exploded and list values are returned in
+their comma-separated form. Query parameters may appear in any order.
+*/
+package uritemplate
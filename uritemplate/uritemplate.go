@@ -0,0 +1,568 @@
+package uritemplate
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// operator describes the expansion behaviour of an expression operator (RFC 6570 Appendix A)
+type operator struct {
+	first         string // String prepended to the first defined variable
+	sep           string // Separator between defined variables
+	named         bool   // Whether variables are expanded as name=value pairs
+	ifEmpty       string // String appended to the name when a named value is empty
+	allowReserved bool   // Whether reserved characters pass through unencoded
+}
+
+// operators maps each operator character to its expansion behaviour
+var operators = map[byte]operator{
+	0:   {first: "", sep: ",", named: false, ifEmpty: "", allowReserved: false},
+	'+': {first: "", sep: ",", named: false, ifEmpty: "", allowReserved: true},
+	'.': {first: ".", sep: ".", named: false, ifEmpty: "", allowReserved: false},
+	'/': {first: "/", sep: "/", named: false, ifEmpty: "", allowReserved: false},
+	';': {first: ";", sep: ";", named: true, ifEmpty: "", allowReserved: false},
+	'?': {first: "?", sep: "&", named: true, ifEmpty: "=", allowReserved: false},
+	'&': {first: "&", sep: "&", named: true, ifEmpty: "=", allowReserved: false},
+	'#': {first: "#", sep: ",", named: false, ifEmpty: "", allowReserved: true},
+}
+
+// maxPrefix is the largest prefix modifier allowed by the RFC
+const maxPrefix = 9999
+
+// varSpec is a single variable reference inside an expression
+type varSpec struct {
+	name    string
+	prefix  int // Maximum number of characters to expand, 0 for no limit
+	explode bool
+}
+
+// part is either a literal string or an expression
+type part struct {
+	literal string
+	op      byte
+	vars    []varSpec
+	isExpr  bool
+}
+
+// Template is a parsed RFC 6570 URI template
+type Template struct {
+	raw   string
+	parts []part
+
+	// Matching state, built by Parse and read-only afterwards so that
+	// templates can be matched concurrently
+	matcher   *regexp.Regexp
+	groups    []string        // Variable name for each capture group
+	queryVars map[string]bool // Variables matched from the query component
+	queryIdx  int             // Capture group holding the query component, -1 if none
+}
+
+// Parse parses a URI template
+func Parse(template string) (*Template, error) {
+	t := &Template{raw: template, queryIdx: -1}
+
+	rest := template
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			if strings.IndexByte(rest, '}') >= 0 {
+				return nil, fmt.Errorf("uritemplate: unmatched '}' in %q", template)
+			}
+			t.parts = append(t.parts, part{literal: rest})
+			break
+		}
+
+		if open > 0 {
+			literal := rest[:open]
+			if strings.IndexByte(literal, '}') >= 0 {
+				return nil, fmt.Errorf("uritemplate: unmatched '}' in %q", template)
+			}
+			t.parts = append(t.parts, part{literal: literal})
+		}
+
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("uritemplate: unclosed expression in %q", template)
+		}
+
+		expr, err := parseExpression(rest[open+1 : open+end])
+		if err != nil {
+			return nil, fmt.Errorf("uritemplate: %w in %q", err, template)
+		}
+		t.parts = append(t.parts, expr)
+		rest = rest[open+end+1:]
+	}
+
+	t.buildMatcher()
+	return t, nil
+}
+
+// MustParse is like Parse but panics if the template cannot be parsed
+func MustParse(template string) *Template {
+	t, err := Parse(template)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// Expand parses template and expands it with vars in one step
+func Expand(template string, vars map[string]interface{}) (string, error) {
+	t, err := Parse(template)
+	if err != nil {
+		return "", err
+	}
+	return t.Expand(vars)
+}
+
+// parseExpression parses the contents of a {...} expression
+func parseExpression(body string) (part, error) {
+	if body == "" {
+		return part{}, fmt.Errorf("empty expression")
+	}
+
+	p := part{isExpr: true}
+	switch body[0] {
+	case '+', '#', '.', '/', ';', '?', '&':
+		p.op = body[0]
+		body = body[1:]
+	case '=', ',', '!', '@', '|':
+		return part{}, fmt.Errorf("reserved operator %q", body[0])
+	}
+
+	for _, spec := range strings.Split(body, ",") {
+		v := varSpec{name: spec}
+
+		if strings.HasSuffix(spec, "*") {
+			v.explode = true
+			v.name = strings.TrimSuffix(spec, "*")
+		} else if idx := strings.IndexByte(spec, ':'); idx >= 0 {
+			prefix, err := strconv.Atoi(spec[idx+1:])
+			if err != nil || prefix <= 0 || prefix > maxPrefix {
+				return part{}, fmt.Errorf("invalid prefix modifier in %q", spec)
+			}
+			v.name = spec[:idx]
+			v.prefix = prefix
+		}
+
+		if !validVarName(v.name) {
+			return part{}, fmt.Errorf("invalid variable name %q", v.name)
+		}
+		p.vars = append(p.vars, v)
+	}
+
+	return p, nil
+}
+
+// validVarName reports whether name is a valid RFC 6570 varname
+func validVarName(name string) bool {
+	if name == "" || name[0] == '.' || name[len(name)-1] == '.' {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case isAlpha(c) || isDigit(c) || c == '_' || c == '.':
+		case c == '%' && i+2 < len(name) && isHex(name[i+1]) && isHex(name[i+2]):
+			i += 2
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the original template text
+func (t *Template) String() string {
+	return t.raw
+}
+
+// Varnames returns the names of all variables in the template, in order of appearance
+func (t *Template) Varnames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, p := range t.parts {
+		for _, v := range p.vars {
+			if !seen[v.name] {
+				seen[v.name] = true
+				names = append(names, v.name)
+			}
+		}
+	}
+	return names
+}
+
+// Expand substitutes vars into the template and returns the resulting URI
+func (t *Template) Expand(vars map[string]interface{}) (string, error) {
+	var b strings.Builder
+	for _, p := range t.parts {
+		if !p.isExpr {
+			b.WriteString(encode(p.literal, true))
+			continue
+		}
+		if err := expandExpression(&b, p, vars); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+// expandExpression expands a single expression according to RFC 6570 section 3.2.1
+func expandExpression(b *strings.Builder, p part, vars map[string]interface{}) error {
+	op := operators[p.op]
+	first := true
+
+	for _, v := range p.vars {
+		value, err := normalizeValue(vars[v.name])
+		if err != nil {
+			return fmt.Errorf("uritemplate: variable %q: %w", v.name, err)
+		}
+		if value == nil {
+			continue
+		}
+
+		if first {
+			b.WriteString(op.first)
+			first = false
+		} else {
+			b.WriteString(op.sep)
+		}
+
+		switch val := value.(type) {
+		case string:
+			expandString(b, op, v, val)
+		case []string:
+			expandList(b, op, v, val)
+		case []pair:
+			expandPairs(b, op, v, val)
+		}
+	}
+
+	return nil
+}
+
+// expandString expands a string value, applying any prefix modifier
+func expandString(b *strings.Builder, op operator, v varSpec, value string) {
+	if v.prefix > 0 && utf8.RuneCountInString(value) > v.prefix {
+		runes := []rune(value)
+		value = string(runes[:v.prefix])
+	}
+
+	if op.named {
+		b.WriteString(v.name)
+		if value == "" {
+			b.WriteString(op.ifEmpty)
+			return
+		}
+		b.WriteByte('=')
+	}
+	b.WriteString(encode(value, op.allowReserved))
+}
+
+// expandList expands a list value
+func expandList(b *strings.Builder, op operator, v varSpec, items []string) {
+	if !v.explode {
+		if op.named {
+			b.WriteString(v.name)
+			b.WriteByte('=')
+		}
+		for i, item := range items {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(encode(item, op.allowReserved))
+		}
+		return
+	}
+
+	for i, item := range items {
+		if i > 0 {
+			b.WriteString(op.sep)
+		}
+		if op.named {
+			b.WriteString(v.name)
+			if item == "" {
+				b.WriteString(op.ifEmpty)
+				continue
+			}
+			b.WriteByte('=')
+		}
+		b.WriteString(encode(item, op.allowReserved))
+	}
+}
+
+// expandPairs expands an associative array value
+func expandPairs(b *strings.Builder, op operator, v varSpec, pairs []pair) {
+	if !v.explode {
+		if op.named {
+			b.WriteString(v.name)
+			b.WriteByte('=')
+		}
+		for i, kv := range pairs {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(encode(kv.key, op.allowReserved))
+			b.WriteByte(',')
+			b.WriteString(encode(kv.value, op.allowReserved))
+		}
+		return
+	}
+
+	for i, kv := range pairs {
+		if i > 0 {
+			b.WriteString(op.sep)
+		}
+		b.WriteString(encode(kv.key, op.allowReserved))
+		if op.named && kv.value == "" {
+			b.WriteString(op.ifEmpty)
+			continue
+		}
+		b.WriteByte('=')
+		b.WriteString(encode(kv.value, op.allowReserved))
+	}
+}
+
+// pair is a key/value entry of an associative array, kept in sorted key order
+type pair struct {
+	key   string
+	value string
+}
+
+// normalizeValue converts a variable value into a string, []string or []pair.
+// Undefined values (nil, empty lists and empty maps) are returned as nil.
+func normalizeValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return v, nil
+	case []string:
+		if len(v) == 0 {
+			return nil, nil
+		}
+		return v, nil
+	case []interface{}:
+		if len(v) == 0 {
+			return nil, nil
+		}
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return items, nil
+	case map[string]string:
+		if len(v) == 0 {
+			return nil, nil
+		}
+		pairs := make([]pair, 0, len(v))
+		for key, val := range v {
+			pairs = append(pairs, pair{key: key, value: val})
+		}
+		sortPairs(pairs)
+		return pairs, nil
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return nil, nil
+		}
+		pairs := make([]pair, 0, len(v))
+		for key, val := range v {
+			pairs = append(pairs, pair{key: key, value: fmt.Sprint(val)})
+		}
+		sortPairs(pairs)
+		return pairs, nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v), nil
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+// sortPairs orders associative array entries by key so expansion is deterministic
+func sortPairs(pairs []pair) {
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].key < pairs[j].key })
+}
+
+// encode percent-encodes s, leaving unreserved characters (and reserved ones
+// plus existing percent-encoded triplets when allowReserved is set) untouched
+func encode(s string, allowReserved bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case isUnreserved(c):
+			b.WriteByte(c)
+		case allowReserved && isReserved(c):
+			b.WriteByte(c)
+		case allowReserved && c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			b.WriteString(s[i : i+3])
+			i += 2
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// Match reports whether uri could have been produced by expanding the template,
+// and returns the percent-decoded values of the variables it contains
+func (t *Template) Match(uri string) (map[string]string, bool) {
+	m := t.matcher.FindStringSubmatchIndex(uri)
+	if m == nil {
+		return nil, false
+	}
+
+	vars := make(map[string]string)
+	for i, name := range t.groups {
+		start, end := m[2*(i+1)], m[2*(i+1)+1]
+		if start < 0 || i == t.queryIdx {
+			continue
+		}
+		vars[name] = unescape(uri[start:end])
+	}
+
+	if t.queryIdx >= 0 {
+		start, end := m[2*(t.queryIdx+1)], m[2*(t.queryIdx+1)+1]
+		if start >= 0 {
+			t.matchQuery(uri[start:end], vars)
+		}
+	}
+
+	return vars, true
+}
+
+// matchQuery extracts query parameters declared in {?...} and {&...} expressions
+func (t *Template) matchQuery(query string, vars map[string]string) {
+	for _, param := range strings.FieldsFunc(query, func(r rune) bool { return r == '?' || r == '&' }) {
+		key, value, _ := strings.Cut(param, "=")
+		key = unescape(key)
+		if t.queryVars[key] {
+			vars[key] = unescape(value)
+		}
+	}
+}
+
+// buildMatcher compiles a regular expression that recognizes expansions of the template
+func (t *Template) buildMatcher() {
+	var b strings.Builder
+	b.WriteByte('^')
+
+	t.queryVars = make(map[string]bool)
+	for _, p := range t.parts {
+		if !p.isExpr {
+			b.WriteString(regexp.QuoteMeta(encode(p.literal, true)))
+			continue
+		}
+
+		if p.op == '?' || p.op == '&' {
+			for _, v := range p.vars {
+				t.queryVars[v.name] = true
+			}
+			// Consecutive query expressions share a single capture group
+			if t.queryIdx < 0 {
+				t.queryIdx = len(t.groups)
+				t.groups = append(t.groups, "")
+				b.WriteString(`((?:[?&][^#]*)?)`)
+			}
+			continue
+		}
+
+		t.writeExpressionPattern(&b, p)
+	}
+
+	b.WriteByte('$')
+	t.matcher = regexp.MustCompile(b.String())
+}
+
+// writeExpressionPattern writes the pattern for a non-query expression
+func (t *Template) writeExpressionPattern(b *strings.Builder, p part) {
+	op := operators[p.op]
+
+	for i, v := range p.vars {
+		t.groups = append(t.groups, v.name)
+
+		valueClass := valuePattern(p.op, v.explode, len(p.vars) > 1)
+		lead := regexp.QuoteMeta(op.sep)
+		if i == 0 {
+			lead = regexp.QuoteMeta(op.first)
+		}
+
+		switch {
+		case op.named:
+			fmt.Fprintf(b, `(?:%s%s(?:=(%s))?)?`, lead, regexp.QuoteMeta(v.name), valueClass)
+		case i == 0 && (p.op == 0 || p.op == '+'):
+			fmt.Fprintf(b, `(%s)`, valueClass)
+		default:
+			fmt.Fprintf(b, `(?:%s(%s))?`, lead, valueClass)
+		}
+	}
+}
+
+// valuePattern returns the pattern matching a single expanded value for op.
+// Values expanded without reserved characters consist only of unreserved
+// characters and percent-encoded triplets, plus the separators of lists.
+func valuePattern(op byte, explode, multi bool) string {
+	if op == '+' || op == '#' {
+		stop := `?#`
+		if op == '#' {
+			stop = ``
+		}
+		if multi && !explode {
+			stop += `,`
+		}
+		if stop == "" {
+			return `.*`
+		}
+		return "[^" + regexp.QuoteMeta(stop) + "]*"
+	}
+
+	chars := `A-Za-z0-9\-_~%`
+	if op != '.' {
+		chars += `.`
+	}
+	if !multi || explode {
+		chars += `,`
+	}
+	if explode {
+		chars += regexp.QuoteMeta(operators[op].sep)
+	}
+	return "[" + chars + "]*"
+}
+
+// unescape percent-decodes s, returning it unchanged if it is not valid
+func unescape(s string) string {
+	decoded, err := url.PathUnescape(s)
+	if err != nil {
+		return s
+	}
+	return decoded
+}
+
+func isAlpha(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHex(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// isUnreserved reports whether c is an RFC 3986 unreserved character
+func isUnreserved(c byte) bool {
+	return isAlpha(c) || isDigit(c) || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// isReserved reports whether c is an RFC 3986 reserved character
+func isReserved(c byte) bool {
+	return strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0
+}
@@ -0,0 +1,268 @@
+package uritemplate
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// rfcVars are the example variables from RFC 6570 section 3.2
+var rfcVars = map[string]interface{}{
+	"var":   "value",
+	"hello": "Hello World!",
+	"path":  "/foo/bar",
+	"empty": "",
+	"x":     1024,
+	"y":     "768",
+	"list":  []string{"red", "green", "blue"},
+	"keys":  map[string]string{"semi": ";", "dot": ".", "comma": ","},
+}
+
+func TestExpandRFCExamples(t *testing.T) {
+	// Associative arrays expand in sorted key order, so the keys examples
+	// differ in ordering from the RFC text.
+	tests := []struct {
+		template string
+		expected string
+	}{
+		// Level 1
+		{"{var}", "value"},
+		{"{hello}", "Hello%20World%21"},
+
+		// Level 2
+		{"{+var}", "value"},
+		{"{+hello}", "Hello%20World!"},
+		{"{+path}/here", "/foo/bar/here"},
+		{"here?ref={+path}", "here?ref=/foo/bar"},
+		{"X{#var}", "X#value"},
+		{"X{#hello}", "X#Hello%20World!"},
+
+		// Level 3
+		{"map?{x,y}", "map?1024,768"},
+		{"{x,hello,y}", "1024,Hello%20World%21,768"},
+		{"{+x,hello,y}", "1024,Hello%20World!,768"},
+		{"{+path,x}/here", "/foo/bar,1024/here"},
+		{"{#x,hello,y}", "#1024,Hello%20World!,768"},
+		{"X{.var}", "X.value"},
+		{"X{.x,y}", "X.1024.768"},
+		{"{/var}", "/value"},
+		{"{/var,x}/here", "/value/1024/here"},
+		{"{;x,y}", ";x=1024;y=768"},
+		{"{;x,y,empty}", ";x=1024;y=768;empty"},
+		{"{?x,y}", "?x=1024&y=768"},
+		{"{?x,y,empty}", "?x=1024&y=768&empty="},
+		{"?fixed=yes{&x}", "?fixed=yes&x=1024"},
+		{"{&x,y,empty}", "&x=1024&y=768&empty="},
+
+		// Level 4
+		{"{var:3}", "val"},
+		{"{var:30}", "value"},
+		{"{list}", "red,green,blue"},
+		{"{list*}", "red,green,blue"},
+		{"{keys}", "comma,%2C,dot,.,semi,%3B"},
+		{"{keys*}", "comma=%2C,dot=.,semi=%3B"},
+		{"{+path:6}/here", "/foo/b/here"},
+		{"{+list*}", "red,green,blue"},
+		{"{+keys*}", "comma=,,dot=.,semi=;"},
+		{"{#path:6}/here", "#/foo/b/here"},
+		{"X{.list*}", "X.red.green.blue"},
+		{"{/list*,path:4}", "/red/green/blue/%2Ffoo"},
+		{"{;list*}", ";list=red;list=green;list=blue"},
+		{"{;keys}", ";keys=comma,%2C,dot,.,semi,%3B"},
+		{"{;keys*}", ";comma=%2C;dot=.;semi=%3B"},
+		{"{?list}", "?list=red,green,blue"},
+		{"{?list*}", "?list=red&list=green&list=blue"},
+		{"{?keys*}", "?comma=%2C&dot=.&semi=%3B"},
+		{"{&list*}", "&list=red&list=green&list=blue"},
+
+		// Undefined variables
+		{"{undef}", ""},
+		{"{?undef,x}", "?x=1024"},
+		{"X{/undef}Y", "XY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got, err := Expand(tt.template, rfcVars)
+			if err != nil {
+				t.Fatalf("Expand failed: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expand(%q) = %q, expected %q", tt.template, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExpandUnicodePrefix(t *testing.T) {
+	got, err := Expand("{name:2}", map[string]interface{}{"name": "héllo"})
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+	if got != "h%C3%A9" {
+		t.Errorf("Expected prefix to count characters, got %q", got)
+	}
+}
+
+func TestExpandUnsupportedValue(t *testing.T) {
+	_, err := Expand("{v}", map[string]interface{}{"v": struct{}{}})
+	if err == nil {
+		t.Error("Expected error for unsupported value type")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	invalid := []string{
+		"{",
+		"}",
+		"a}b",
+		"{}",
+		"{!var}",
+		"{var:0}",
+		"{var:10000}",
+		"{a b}",
+		"{.}",
+		"{var,}",
+	}
+
+	for _, template := range invalid {
+		if _, err := Parse(template); err == nil {
+			t.Errorf("Expected Parse(%q) to fail", template)
+		}
+	}
+}
+
+func TestMustParsePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustParse to panic on an invalid template")
+		}
+	}()
+	MustParse("{unclosed")
+}
+
+func TestVarnames(t *testing.T) {
+	tmpl := MustParse("repo://{owner}/{repo}/issues{/id}{?state,owner}")
+
+	expected := []string{"owner", "repo", "id", "state"}
+	if got := tmpl.Varnames(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Varnames() = %v, expected %v", got, expected)
+	}
+	if tmpl.String() != "repo://{owner}/{repo}/issues{/id}{?state,owner}" {
+		t.Errorf("String() = %q", tmpl.String())
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		template string
+		uri      string
+		expected map[string]string
+	}{
+		{
+			"weather://{city}/forecast{?days,units}",
+			"weather://Paris/forecast?units=metric&days=5",
+			map[string]string{"city": "Paris", "days": "5", "units": "metric"},
+		},
+		{
+			"weather://{city}/forecast{?days,units}",
+			"weather://New%20York/forecast",
+			map[string]string{"city": "New York"},
+		},
+		{
+			"file:///{+path}",
+			"file:///src/main.go",
+			map[string]string{"path": "src/main.go"},
+		},
+		{
+			"repo://{owner}/{repo}/issues{/id}",
+			"repo://golang/go/issues",
+			map[string]string{"owner": "golang", "repo": "go"},
+		},
+		{
+			"repo://{owner}/{repo}/issues{/id}",
+			"repo://golang/go/issues/42",
+			map[string]string{"owner": "golang", "repo": "go", "id": "42"},
+		},
+		{
+			"db://{table}{;limit,offset}",
+			"db://users;limit=10;offset=20",
+			map[string]string{"table": "users", "limit": "10", "offset": "20"},
+		},
+		{
+			"file{.ext}",
+			"file.json",
+			map[string]string{"ext": "json"},
+		},
+		{
+			"doc://{id}{#section}",
+			"doc://readme#install",
+			map[string]string{"id": "readme", "section": "install"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			got, ok := MustParse(tt.template).Match(tt.uri)
+			if !ok {
+				t.Fatalf("Expected %q to match %q", tt.uri, tt.template)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Match(%q) = %v, expected %v", tt.uri, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchNoMatch(t *testing.T) {
+	tmpl := MustParse("weather://{city}/forecast{?days}")
+
+	for _, uri := range []string{
+		"other://Paris/forecast",
+		"weather://Paris/history",
+		"weather://Paris/extra/forecast",
+	} {
+		if vars, ok := tmpl.Match(uri); ok {
+			t.Errorf("Expected %q not to match, got %v", uri, vars)
+		}
+	}
+}
+
+func TestMatchRoundTrip(t *testing.T) {
+	tmpl := MustParse("weather://{city}/forecast{?days,units}")
+	vars := map[string]interface{}{"city": "São Paulo/BR", "days": 3, "units": "metric & more"}
+
+	uri, err := tmpl.Expand(vars)
+	if err != nil {
+		t.Fatalf("Expand failed: %v", err)
+	}
+
+	got, ok := tmpl.Match(uri)
+	if !ok {
+		t.Fatalf("Expected expanded URI %q to match its template", uri)
+	}
+
+	expected := map[string]string{"city": "São Paulo/BR", "days": "3", "units": "metric & more"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Round trip = %v, expected %v", got, expected)
+	}
+}
+
+func TestMatchConcurrent(t *testing.T) {
+	tmpl := MustParse("users://{id}/profile{?fields}")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := fmt.Sprint(i)
+			vars, ok := tmpl.Match("users://" + id + "/profile?fields=name")
+			if !ok || vars["id"] != id || vars["fields"] != "name" {
+				t.Errorf("Match = %v, %v", vars, ok)
+			}
+		}(i)
+	}
+	wg.Wait()
+}